@@ -26,6 +26,8 @@ type ABIEncoder interface {
 // ABI represents the ethereum abi format
 type ABI struct {
 	Constructor        *Method
+	Fallback           *Method
+	Receive            *Method
 	Methods            map[string]*Method
 	MethodsBySignature map[string]*Method
 	Events             map[string]*Event
@@ -103,7 +105,8 @@ func NewABIFromSlice(abi []compiler.AbiField) (*ABI, error) {
 				return nil, err
 			}
 			a.Constructor = &Method{
-				Inputs: input,
+				Inputs:          input,
+				StateMutability: field.StateMutability,
 			}
 
 		case "function", "":
@@ -121,10 +124,11 @@ func NewABIFromSlice(abi []compiler.AbiField) (*ABI, error) {
 				return nil, err
 			}
 			method := &Method{
-				Name:    field.Name,
-				Const:   c,
-				Inputs:  inputs,
-				Outputs: outputs,
+				Name:            field.Name,
+				Const:           c,
+				Inputs:          inputs,
+				Outputs:         outputs,
+				StateMutability: field.StateMutability,
 			}
 			a.addMethod(method)
 
@@ -152,8 +156,10 @@ func NewABIFromSlice(abi []compiler.AbiField) (*ABI, error) {
 			a.addError(errObj)
 
 		case "fallback":
+			a.Fallback = &Method{StateMutability: field.StateMutability}
+
 		case "receive":
-			// do nothing
+			a.Receive = &Method{StateMutability: field.StateMutability}
 
 		default:
 			return nil, fmt.Errorf("unknown field type '%s'", field.Type)
@@ -187,6 +193,7 @@ func (a *ABI) UnmarshalJSON(data []byte) error {
 		Type            string
 		Name            string
 		Constant        bool
+		Payable         bool
 		Anonymous       bool
 		StateMutability string
 		Inputs          []*compiler.IOField
@@ -208,7 +215,8 @@ func (a *ABI) UnmarshalJSON(data []byte) error {
 				panic(err)
 			}
 			a.Constructor = &Method{
-				Inputs: input,
+				Inputs:          input,
+				StateMutability: legacyStateMutability(field.StateMutability, false, field.Payable),
 			}
 
 		case "function", "":
@@ -226,10 +234,11 @@ func (a *ABI) UnmarshalJSON(data []byte) error {
 				panic(err)
 			}
 			method := &Method{
-				Name:    field.Name,
-				Const:   c,
-				Inputs:  inputs,
-				Outputs: outputs,
+				Name:            field.Name,
+				Const:           c,
+				Inputs:          inputs,
+				Outputs:         outputs,
+				StateMutability: legacyStateMutability(field.StateMutability, field.Constant, field.Payable),
 			}
 			a.addMethod(method)
 
@@ -257,8 +266,14 @@ func (a *ABI) UnmarshalJSON(data []byte) error {
 			a.addError(errObj)
 
 		case "fallback":
+			a.Fallback = &Method{
+				StateMutability: legacyStateMutability(field.StateMutability, false, field.Payable),
+			}
+
 		case "receive":
-			// do nothing
+			a.Receive = &Method{
+				StateMutability: legacyStateMutability(field.StateMutability, false, field.Payable),
+			}
 
 		default:
 			return fmt.Errorf("unknown field type '%s'", field.Type)
@@ -267,12 +282,31 @@ func (a *ABI) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// legacyStateMutability returns the state mutability of abis generated
+// before solidity 0.4.16 that used the constant and payable fields
+func legacyStateMutability(stateMutability string, constant, payable bool) string {
+	if stateMutability != "" {
+		return stateMutability
+	}
+	if constant {
+		return "view"
+	}
+	if payable {
+		return "payable"
+	}
+	return ""
+}
+
 // Method is a callable function in the contract
 type Method struct {
 	Name    string
 	Const   bool
 	Inputs  *Type
 	Outputs *Type
+
+	// StateMutability is either pure, view, nonpayable or payable.
+	// It is empty if the abi does not include it.
+	StateMutability string
 }
 
 // Sig returns the signature of the method
@@ -300,11 +334,11 @@ func (m *Method) Encode(args interface{}) ([]byte, error) {
 }
 
 // Decode decodes the output with this function
-func (m *Method) Decode(data []byte) (map[string]interface{}, error) {
+func (m *Method) Decode(data []byte, opts ...DecodeOption) (map[string]interface{}, error) {
 	if len(data) == 0 {
 		return nil, fmt.Errorf("empty response")
 	}
-	respInterface, err := Decode(m.Outputs, data)
+	respInterface, err := Decode(m.Outputs, data, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -322,54 +356,70 @@ func MustNewMethod(name string) *Method {
 }
 
 func NewMethod(name string) (*Method, error) {
-	name, inputs, outputs, err := parseMethodSignature(name)
+	name, inputs, outputs, modifiers, err := parseMethodSignature(name)
 	if err != nil {
 		return nil, err
 	}
 	m := &Method{Name: name, Inputs: inputs, Outputs: outputs}
+	m.StateMutability = parseStateMutability(modifiers)
+	m.Const = m.StateMutability == "view" || m.StateMutability == "pure"
 	return m, nil
 }
 
+// parseStateMutability returns the state mutability in the modifiers
+// of a human readable function (i.e. 'external view')
+func parseStateMutability(modifiers string) string {
+	for _, modifier := range strings.Fields(modifiers) {
+		switch modifier {
+		case "view", "pure", "payable", "nonpayable":
+			return modifier
+		}
+	}
+	return ""
+}
+
 var (
 	funcRegexpWithReturn    = regexp.MustCompile(`(\w*)\s*\((.*)\)(.*)\s*returns\s*\((.*)\)`)
 	funcRegexpWithoutReturn = regexp.MustCompile(`(\w*)\s*\((.*)\)(.*)`)
 )
 
-func parseMethodSignature(name string) (string, *Type, *Type, error) {
+func parseMethodSignature(name string) (string, *Type, *Type, string, error) {
 	name = strings.Replace(name, "\n", " ", -1)
 	name = strings.Replace(name, "\t", " ", -1)
 
 	name = strings.TrimPrefix(name, "function ")
 	name = strings.TrimSpace(name)
 
-	var funcName, inputArgs, outputArgs string
+	var funcName, inputArgs, outputArgs, modifiers string
 
 	if strings.Contains(name, "returns") {
 		matches := funcRegexpWithReturn.FindAllStringSubmatch(name, -1)
 		if len(matches) == 0 {
-			return "", nil, nil, fmt.Errorf("no matches found")
+			return "", nil, nil, "", fmt.Errorf("no matches found")
 		}
 		funcName = strings.TrimSpace(matches[0][1])
 		inputArgs = strings.TrimSpace(matches[0][2])
+		modifiers = strings.TrimSpace(matches[0][3])
 		outputArgs = strings.TrimSpace(matches[0][4])
 	} else {
 		matches := funcRegexpWithoutReturn.FindAllStringSubmatch(name, -1)
 		if len(matches) == 0 {
-			return "", nil, nil, fmt.Errorf("no matches found")
+			return "", nil, nil, "", fmt.Errorf("no matches found")
 		}
 		funcName = strings.TrimSpace(matches[0][1])
 		inputArgs = strings.TrimSpace(matches[0][2])
+		modifiers = strings.TrimSpace(matches[0][3])
 	}
 
 	input, err := NewType("tuple(" + inputArgs + ")")
 	if err != nil {
-		return "", nil, nil, err
+		return "", nil, nil, "", err
 	}
 	output, err := NewType("tuple(" + outputArgs + ")")
 	if err != nil {
-		return "", nil, nil, err
+		return "", nil, nil, "", err
 	}
-	return funcName, input, output, nil
+	return funcName, input, output, modifiers, nil
 }
 
 // Event is a triggered log mechanism
@@ -405,11 +455,18 @@ func MustNewEvent(name string) *Event {
 
 // NewEvent creates a new solidity event object using the signature
 func NewEvent(name string) (*Event, error) {
+	name = strings.TrimSpace(name)
+
+	anonymous := strings.HasSuffix(name, " anonymous")
+	name = strings.TrimSpace(strings.TrimSuffix(name, " anonymous"))
+
 	name, typ, err := parseEventOrErrorSignature("event ", name)
 	if err != nil {
 		return nil, err
 	}
-	return NewEventFromType(name, typ), nil
+	evnt := NewEventFromType(name, typ)
+	evnt.Anonymous = anonymous
+	return evnt, nil
 }
 
 // Error is a solidity error object
@@ -418,6 +475,20 @@ type Error struct {
 	Inputs *Type
 }
 
+// Sig returns the signature of the error
+func (e *Error) Sig() string {
+	return buildSignature(e.Name, e.Inputs)
+}
+
+// ID returns the selector of the error in the revert data
+func (e *Error) ID() []byte {
+	k := acquireKeccak()
+	k.Write([]byte(e.Sig()))
+	dst := k.Sum(nil)[:4]
+	releaseKeccak(k)
+	return dst
+}
+
 // NewError creates a new solidity error object
 func NewError(name string) (*Error, error) {
 	name, typ, err := parseEventOrErrorSignature("error ", name)
@@ -502,12 +573,31 @@ func NewABIFromList(humanReadableAbi []string) (*ABI, error) {
 	res := &ABI{}
 	for _, c := range humanReadableAbi {
 		if strings.HasPrefix(c, "constructor") {
-			typ, err := NewType("tuple" + strings.TrimPrefix(c, "constructor"))
+			args, modifiers, err := splitModifiers(strings.TrimPrefix(c, "constructor"))
+			if err != nil {
+				return nil, err
+			}
+			typ, err := NewType("tuple" + args)
 			if err != nil {
 				return nil, err
 			}
 			res.Constructor = &Method{
-				Inputs: typ,
+				Inputs:          typ,
+				StateMutability: parseStateMutability(modifiers),
+			}
+
+		} else if strings.HasPrefix(c, "fallback") || strings.HasPrefix(c, "receive") {
+			_, modifiers, err := splitModifiers(strings.TrimPrefix(strings.TrimPrefix(c, "fallback"), "receive"))
+			if err != nil {
+				return nil, err
+			}
+			method := &Method{
+				StateMutability: parseStateMutability(modifiers),
+			}
+			if strings.HasPrefix(c, "fallback") {
+				res.Fallback = method
+			} else {
+				res.Receive = method
 			}
 
 		} else if strings.HasPrefix(c, "function ") {
@@ -537,3 +627,17 @@ func NewABIFromList(humanReadableAbi []string) (*ABI, error) {
 	}
 	return res, nil
 }
+
+// splitModifiers splits the arguments in parenthesis of a
+// human readable declaration from the modifiers after them
+func splitModifiers(str string) (string, string, error) {
+	str = strings.TrimSpace(str)
+	if !strings.HasPrefix(str, "(") {
+		return "", "", fmt.Errorf("failed to parse input, expected '(types)'")
+	}
+	indx := strings.LastIndex(str, ")")
+	if indx == -1 {
+		return "", "", fmt.Errorf("failed to parse input, expected '(types)'")
+	}
+	return str[:indx+1], str[indx+1:], nil
+}
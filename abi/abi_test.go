@@ -15,10 +15,11 @@ func TestAbi(t *testing.T) {
 		Outputs: MustNewType("tuple()"),
 	}
 	balanceFunc := &Method{
-		Name:    "balanceOf",
-		Const:   true,
-		Inputs:  MustNewType("tuple(address owner)"),
-		Outputs: MustNewType("tuple(uint256 balance)"),
+		Name:            "balanceOf",
+		Const:           true,
+		Inputs:          MustNewType("tuple(address owner)"),
+		Outputs:         MustNewType("tuple(uint256 balance)"),
+		StateMutability: "view",
 	}
 
 	cases := []struct {
@@ -253,14 +254,18 @@ func TestAbi_HumanReadable(t *testing.T) {
 				Outputs: MustNewType("tuple()"),
 			},
 			"balanceOf": {
-				Name:    "balanceOf",
-				Inputs:  MustNewType("tuple(address owner)"),
-				Outputs: MustNewType("tuple(uint256 balance)"),
+				Name:            "balanceOf",
+				Const:           true,
+				Inputs:          MustNewType("tuple(address owner)"),
+				Outputs:         MustNewType("tuple(uint256 balance)"),
+				StateMutability: "view",
 			},
 			"balanceOf0": {
-				Name:    "balanceOf",
-				Inputs:  MustNewType("tuple()"),
-				Outputs: MustNewType("tuple()"),
+				Name:            "balanceOf",
+				Const:           true,
+				Inputs:          MustNewType("tuple()"),
+				Outputs:         MustNewType("tuple()"),
+				StateMutability: "view",
 			},
 			"addPerson": {
 				Name:    "addPerson",
@@ -273,9 +278,11 @@ func TestAbi_HumanReadable(t *testing.T) {
 				Outputs: MustNewType("tuple()"),
 			},
 			"getPerson": {
-				Name:    "getPerson",
-				Inputs:  MustNewType("tuple(uint256 id)"),
-				Outputs: MustNewType("tuple(tuple(string name, uint16 age))"),
+				Name:            "getPerson",
+				Const:           true,
+				Inputs:          MustNewType("tuple(uint256 id)"),
+				Outputs:         MustNewType("tuple(tuple(string name, uint16 age))"),
+				StateMutability: "view",
 			},
 		},
 		Events: map[string]*Event{
@@ -343,7 +350,7 @@ func TestAbi_ParseMethodSignature(t *testing.T) {
 	}
 
 	for _, c := range cases {
-		name, input, output, err := parseMethodSignature(c.signature)
+		name, input, output, _, err := parseMethodSignature(c.signature)
 		if err != nil {
 			t.Fatal(err)
 		}
@@ -0,0 +1,544 @@
+package abi
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Ethernal-Tech/ethgo"
+)
+
+// ParseArg parses a textual or json argument into a value of the type that can be
+// encoded with Encode (the same values returned by Decode). The format is:
+//   - integers in decimal (i.e. '-12' or '1e18') or hex (i.e. '0x1f')
+//   - fixed point numbers in decimal (i.e. '1.5')
+//   - addresses, bytes, bytesN and functions in hex ('0x...'). Mixed case addresses must
+//     have a valid checksum and bytesN must have exactly N bytes.
+//   - booleans as 'true' or 'false'
+//   - strings quoted with json escapes (i.e. "hi") or unquoted if they have no separators
+//   - arrays in brackets (i.e. '[1,2,3]')
+//   - tuples in parenthesis or brackets (i.e. '(0x1,true,"hi")') or as a json object with
+//     the names of the elements
+//
+// Any scalar value can also be quoted as a json string (i.e. "1000").
+func ParseArg(t *Type, arg string) (interface{}, error) {
+	str := strings.TrimSpace(arg)
+
+	// an unquoted scalar at the top level takes the whole
+	// input even if it has separators (i.e. a string 'a, b')
+	if isScalarType(t) && !strings.HasPrefix(str, `"`) {
+		return parseScalar(t, str)
+	}
+
+	p := &argParser{input: str}
+	val, err := p.parseValue(t)
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos != len(p.input) {
+		return nil, p.errorf("unexpected input after the value")
+	}
+	return val, nil
+}
+
+// ParseArgs parses a list of arguments (i.e. from the command line) for the elements
+// of a tuple type (i.e. the inputs of a method). The result can be encoded with the type.
+func ParseArgs(t *Type, args []string) ([]interface{}, error) {
+	if t.kind != KindTuple {
+		return nil, fmt.Errorf("expected a tuple type but found %s", t)
+	}
+	if len(args) != len(t.tuple) {
+		return nil, fmt.Errorf("expected %d arguments but found %d", len(t.tuple), len(args))
+	}
+
+	res := make([]interface{}, len(args))
+	for indx, elem := range t.tuple {
+		val, err := ParseArg(elem.Elem, args[indx])
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse argument %d: %v", indx, err)
+		}
+		res[indx] = val
+	}
+	return res, nil
+}
+
+func isScalarType(t *Type) bool {
+	return t.kind != KindTuple && t.kind != KindArray && t.kind != KindSlice
+}
+
+type argParser struct {
+	input string
+	pos   int
+}
+
+func (p *argParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%s at position %d", fmt.Sprintf(format, args...), p.pos)
+}
+
+func (p *argParser) skipSpaces() {
+	for p.pos < len(p.input) && strings.IndexByte(" \t\r\n", p.input[p.pos]) != -1 {
+		p.pos++
+	}
+}
+
+func (p *argParser) peek() byte {
+	p.skipSpaces()
+	if p.pos == len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *argParser) expect(c byte) error {
+	if p.peek() != c {
+		return p.errorf("expected '%c'", c)
+	}
+	p.pos++
+	return nil
+}
+
+// parseList parses a list of values separated by commas until the close character
+func (p *argParser) parseList(close byte, parseElem func(indx int) error) error {
+	if p.peek() == close {
+		p.pos++
+		return nil
+	}
+	for indx := 0; ; indx++ {
+		if err := parseElem(indx); err != nil {
+			return err
+		}
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case close:
+			p.pos++
+			return nil
+		default:
+			return p.errorf("expected ',' or '%c'", close)
+		}
+	}
+}
+
+func (p *argParser) parseValue(t *Type) (interface{}, error) {
+	switch t.kind {
+	case KindTuple:
+		return p.parseTuple(t)
+
+	case KindSlice, KindArray:
+		return p.parseArray(t)
+
+	default:
+		str, err := p.parseToken()
+		if err != nil {
+			return nil, err
+		}
+		val, err := parseScalar(t, str)
+		if err != nil {
+			return nil, p.errorf("%v", err)
+		}
+		return val, nil
+	}
+}
+
+func (p *argParser) parseTuple(t *Type) (interface{}, error) {
+	res := map[string]interface{}{}
+
+	switch p.peek() {
+	case '(', '[':
+		close := byte(')')
+		if p.input[p.pos] == '[' {
+			close = ']'
+		}
+		p.pos++
+
+		err := p.parseList(close, func(indx int) error {
+			if indx >= len(t.tuple) {
+				return p.errorf("expected %d elements for %s", len(t.tuple), t)
+			}
+			val, err := p.parseValue(t.tuple[indx].Elem)
+			if err != nil {
+				return err
+			}
+			res[tupleElemName(t.tuple[indx], indx)] = val
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+	case '{':
+		p.pos++
+
+		err := p.parseList('}', func(int) error {
+			key, err := p.parseToken()
+			if err != nil {
+				return err
+			}
+			if err := p.expect(':'); err != nil {
+				return err
+			}
+			for indx, elem := range t.tuple {
+				if name := tupleElemName(elem, indx); name == key {
+					if _, ok := res[name]; ok {
+						return p.errorf("duplicated element '%s'", key)
+					}
+					val, err := p.parseValue(elem.Elem)
+					if err != nil {
+						return err
+					}
+					res[name] = val
+					return nil
+				}
+			}
+			return p.errorf("element '%s' not found in %s", key, t)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, p.errorf("expected a tuple")
+	}
+
+	if len(res) != len(t.tuple) {
+		return nil, p.errorf("expected %d elements for %s but found %d", len(t.tuple), t, len(res))
+	}
+	return res, nil
+}
+
+func (p *argParser) parseArray(t *Type) (interface{}, error) {
+	if err := p.expect('['); err != nil {
+		return nil, err
+	}
+
+	elems := []reflect.Value{}
+	err := p.parseList(']', func(int) error {
+		val, err := p.parseValue(t.elem)
+		if err != nil {
+			return err
+		}
+		elems = append(elems, reflect.ValueOf(val))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var res reflect.Value
+	if t.kind == KindArray {
+		if len(elems) != t.size {
+			return nil, p.errorf("expected %d elements for %s but found %d", t.size, t, len(elems))
+		}
+		res = reflect.New(t.t).Elem()
+	} else {
+		res = reflect.MakeSlice(t.t, len(elems), len(elems))
+	}
+	for i, elem := range elems {
+		res.Index(i).Set(elem)
+	}
+	return res.Interface(), nil
+}
+
+// parseToken parses either a json string or an unquoted word
+func (p *argParser) parseToken() (string, error) {
+	if p.peek() == '"' {
+		start := p.pos
+		for p.pos++; p.pos < len(p.input); p.pos++ {
+			if p.input[p.pos] == '\\' {
+				p.pos++
+			} else if p.input[p.pos] == '"' {
+				p.pos++
+
+				var str string
+				if err := json.Unmarshal([]byte(p.input[start:p.pos]), &str); err != nil {
+					return "", p.errorf("invalid string: %v", err)
+				}
+				return str, nil
+			}
+		}
+		return "", p.errorf("unterminated string")
+	}
+
+	start := p.pos
+	for p.pos < len(p.input) && strings.IndexByte(",:()[]{}\"", p.input[p.pos]) == -1 {
+		p.pos++
+	}
+	str := strings.TrimSpace(p.input[start:p.pos])
+	if str == "" {
+		return "", p.errorf("expected a value")
+	}
+	return str, nil
+}
+
+func parseScalar(t *Type, str string) (interface{}, error) {
+	switch t.kind {
+	case KindString:
+		return str, nil
+
+	case KindBool:
+		switch str {
+		case "true":
+			return true, nil
+		case "false":
+			return false, nil
+		}
+		return nil, fmt.Errorf("invalid boolean '%s'", str)
+
+	case KindAddress:
+		return parseAddress(str)
+
+	case KindBytes:
+		return parseHex(str)
+
+	case KindFixedBytes, KindFunction:
+		buf, err := parseHex(str)
+		if err != nil {
+			return nil, err
+		}
+		if len(buf) != t.size {
+			return nil, fmt.Errorf("expected %d bytes for %s but found %d", t.size, t, len(buf))
+		}
+		array := reflect.New(t.t).Elem()
+		reflect.Copy(array, reflect.ValueOf(buf))
+		return array.Interface(), nil
+
+	case KindInt, KindUInt:
+		return parseInteger(t, str)
+
+	case KindFixedPoint:
+		d, err := ethgo.ParseDecimal(str, t.decimals)
+		if err != nil {
+			return nil, err
+		}
+		if !fitsInType(t, d.Value) {
+			return nil, fmt.Errorf("value %s out of range for %s", str, t)
+		}
+		return d, nil
+
+	default:
+		return nil, fmt.Errorf("cannot parse type %s", t)
+	}
+}
+
+func parseHex(str string) ([]byte, error) {
+	if !strings.HasPrefix(str, "0x") && !strings.HasPrefix(str, "0X") {
+		return nil, fmt.Errorf("expected hex value with 0x prefix but found '%s'", str)
+	}
+	buf, err := hex.DecodeString(str[2:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex '%s': %v", str, err)
+	}
+	return buf, nil
+}
+
+func parseAddress(str string) (ethgo.Address, error) {
+	buf, err := parseHex(str)
+	if err != nil {
+		return ethgo.Address{}, err
+	}
+	if len(buf) != 20 {
+		return ethgo.Address{}, fmt.Errorf("expected 20 bytes for an address but found %d", len(buf))
+	}
+	addr := ethgo.BytesToAddress(buf)
+
+	// mixed case addresses have the checksum
+	hexStr := str[2:]
+	if hexStr != strings.ToLower(hexStr) && hexStr != strings.ToUpper(hexStr) && addr.String() != str {
+		return ethgo.Address{}, fmt.Errorf("invalid checksum for address '%s'", str)
+	}
+	return addr, nil
+}
+
+func parseInteger(t *Type, str string) (interface{}, error) {
+	var num *big.Int
+
+	abs, neg := str, false
+	if strings.HasPrefix(str, "-") || strings.HasPrefix(str, "+") {
+		abs, neg = str[1:], str[0] == '-'
+	}
+
+	if strings.HasPrefix(abs, "0x") || strings.HasPrefix(abs, "0X") {
+		digits := abs[2:]
+		if digits == "" || strings.IndexAny(digits, "+-_") != -1 {
+			return nil, fmt.Errorf("invalid integer '%s'", str)
+		}
+		var ok bool
+		if num, ok = new(big.Int).SetString(digits, 16); !ok {
+			return nil, fmt.Errorf("invalid integer '%s'", str)
+		}
+		if neg {
+			num.Neg(num)
+		}
+	} else {
+		d, err := ethgo.ParseDecimal(str, 0)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer '%s': %v", str, err)
+		}
+		num = d.Value
+	}
+
+	if !fitsInType(t, num) {
+		return nil, fmt.Errorf("value %s out of range for %s", str, t)
+	}
+
+	switch t.t.Kind() {
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v := reflect.New(t.t).Elem()
+		v.SetUint(num.Uint64())
+		return v.Interface(), nil
+
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v := reflect.New(t.t).Elem()
+		v.SetInt(num.Int64())
+		return v.Interface(), nil
+
+	default:
+		return num, nil
+	}
+}
+
+// fitsInType checks that the (scaled) number is in the range of the type
+func fitsInType(t *Type, num *big.Int) bool {
+	signed := t.kind == KindInt || (t.kind == KindFixedPoint && t.signed)
+	if !signed {
+		return num.Sign() >= 0 && num.BitLen() <= t.size
+	}
+	if num.Sign() >= 0 {
+		return num.BitLen() < t.size
+	}
+	// the minimum value is -2^(size-1)
+	return new(big.Int).Add(num, big.NewInt(1)).BitLen() < t.size
+}
+
+// FormatArg formats a value of the type (i.e. returned by Decode) as the canonical string
+// accepted by ParseArg: decimal numbers, checksummed addresses, lowercase hex bytes,
+// quoted strings, arrays in brackets and tuples in parenthesis.
+func FormatArg(t *Type, val interface{}) (string, error) {
+	var b strings.Builder
+	if err := formatArg(&b, t, reflect.ValueOf(val)); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+func formatArg(b *strings.Builder, t *Type, v reflect.Value) error {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return fmt.Errorf("no value for %s", t)
+	}
+
+	switch t.kind {
+	case KindTuple:
+		if v.Kind() == reflect.Ptr {
+			v = v.Elem()
+		}
+		if v.Kind() == reflect.Struct {
+			var err error
+			if v, err = mapFromStruct(v); err != nil {
+				return err
+			}
+		}
+
+		b.WriteByte('(')
+		for indx, elem := range t.tuple {
+			var aux reflect.Value
+			switch v.Kind() {
+			case reflect.Map:
+				aux = v.MapIndex(reflect.ValueOf(tupleElemName(elem, indx)))
+			case reflect.Slice, reflect.Array:
+				if indx < v.Len() {
+					aux = v.Index(indx)
+				}
+			default:
+				return encodeErr(v, "tuple")
+			}
+			if indx != 0 {
+				b.WriteByte(',')
+			}
+			if err := formatArg(b, elem.Elem, aux); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(')')
+
+	case KindSlice, KindArray:
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return encodeErr(v, "array or slice")
+		}
+		if t.kind == KindArray && v.Len() != t.size {
+			return fmt.Errorf("expected array of size %d but found %d", t.size, v.Len())
+		}
+
+		b.WriteByte('[')
+		for i := 0; i < v.Len(); i++ {
+			if i != 0 {
+				b.WriteByte(',')
+			}
+			if err := formatArg(b, t.elem, v.Index(i)); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+
+	case KindString:
+		if v.Kind() != reflect.String {
+			return encodeErr(v, "string")
+		}
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		enc.SetEscapeHTML(false)
+		if err := enc.Encode(v.String()); err != nil {
+			return err
+		}
+		b.Write(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+
+	case KindBool:
+		if v.Kind() != reflect.Bool {
+			return encodeErr(v, "bool")
+		}
+		b.WriteString(strconv.FormatBool(v.Bool()))
+
+	case KindAddress:
+		buf, err := encodeAddress(v)
+		if err != nil {
+			return err
+		}
+		b.WriteString(ethgo.BytesToAddress(buf[12:]).String())
+
+	case KindBytes, KindFixedBytes, KindFunction:
+		buf, err := packedBytes(v)
+		if err != nil {
+			return err
+		}
+		if t.kind != KindBytes && len(buf) != t.size {
+			return fmt.Errorf("expected %d bytes for %s but found %d", t.size, t, len(buf))
+		}
+		b.WriteString("0x" + hex.EncodeToString(buf))
+
+	case KindInt, KindUInt:
+		num, err := encodeNum(v)
+		if err != nil {
+			return err
+		}
+		b.WriteString(readBigInt(num, t.kind == KindInt).String())
+
+	case KindFixedPoint:
+		buf, err := encodeFixedPoint(v, t)
+		if err != nil {
+			return err
+		}
+		b.WriteString(readFixedPoint(t, buf).String())
+
+	default:
+		return fmt.Errorf("cannot format type %s", t)
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package abi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseArg(t *testing.T) {
+	addr := ethgo.HexToAddress("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+
+	cases := []struct {
+		typ      string
+		arg      string
+		expected interface{}
+	}{
+		{"uint8", "255", uint8(255)},
+		{"int16", "-2", int16(-2)},
+		{"int64", "-0x10", int64(-16)},
+		{"uint256", "1e18", ethgo.Ether(1)},
+		{"uint256", "1.5e3", big.NewInt(1500)},
+		{"uint256", `"1000"`, big.NewInt(1000)},
+		{"int256", "-57896044618658097711785492504343953926634992332820282019728792003956564819968", new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 255))},
+		{"bool", "true", true},
+		{"address", "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", addr},
+		{"address", "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", addr},
+		{"bytes", "0x0102", []byte{0x1, 0x2}},
+		{"bytes", "0x", []byte{}},
+		{"bytes4", "0x01020304", [4]byte{0x1, 0x2, 0x3, 0x4}},
+		{"string", "hello, world", "hello, world"},
+		{"string", `"a \"quoted\" string"`, `a "quoted" string`},
+		{"ufixed128x18", "1.5", ethgo.NewDecimal(big.NewInt(15e17), 18)},
+		{"uint8[]", "[1, 2, 3]", []uint8{1, 2, 3}},
+		{"uint8[]", "[]", []uint8{}},
+		{"int8[2]", "[-1,1]", [2]int8{-1, 1}},
+		{"string[][]", `[["a", "b,c"], []]`, [][]string{{"a", "b,c"}, {}}},
+		{
+			"tuple(address a, bool b, string c)",
+			`(0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed, true, "hi")`,
+			map[string]interface{}{"a": addr, "b": true, "c": "hi"},
+		},
+		{
+			// json
+			"tuple(uint256 a, tuple(bytes2 c, int32[] d)[] b)",
+			`{"b": [{"c": "0x0102", "d": [-1, 2]}], "a": "1000"}`,
+			map[string]interface{}{
+				"a": big.NewInt(1000),
+				"b": []map[string]interface{}{
+					{"c": [2]byte{0x1, 0x2}, "d": []int32{-1, 2}},
+				},
+			},
+		},
+		{
+			// unnamed elements
+			"tuple(uint8, (bool, string))",
+			`[1, [false, abc]]`,
+			map[string]interface{}{
+				"0": uint8(1),
+				"1": map[string]interface{}{"0": false, "1": "abc"},
+			},
+		},
+	}
+
+	for _, c := range cases {
+		typ := MustNewType(c.typ)
+
+		val, err := ParseArg(typ, c.arg)
+		require.NoError(t, err, c.arg)
+		require.Equal(t, c.expected, val, c.arg)
+
+		// the value can be encoded
+		_, err = Encode(val, typ)
+		require.NoError(t, err)
+	}
+}
+
+func TestParseArg_Errors(t *testing.T) {
+	cases := []struct {
+		typ string
+		arg string
+	}{
+		{"uint8", "256"},
+		{"uint8", "-1"},
+		{"int8", "-129"},
+		{"uint256", "1.5"},
+		{"uint256", "abc"},
+		{"uint256", "0x"},
+		{"bool", "yes"},
+		{"address", "0x5aaeb6053f3e94c9b9a09f33669435e7ef1bea"},
+		{"address", "0x5AAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+		{"bytes", "0102"},
+		{"bytes4", "0x010203"},
+		{"ufixed8x1", "-1"},
+		{"uint8[2]", "[1,2,3]"},
+		{"uint8[]", "[1,2"},
+		{"uint8[]", "[1,2] 3"},
+		{"tuple(uint8 a, uint8 b)", "(1)"},
+		{"tuple(uint8 a, uint8 b)", "(1,2,3)"},
+		{"tuple(uint8 a, uint8 b)", `{"a": 1, "c": 2}`},
+		{"tuple(uint8 a, uint8 b)", `{"a": 1, "a": 2}`},
+		{"string[]", `["abc]`},
+	}
+
+	for _, c := range cases {
+		_, err := ParseArg(MustNewType(c.typ), c.arg)
+		require.Error(t, err, c.arg)
+	}
+}
+
+func TestParseArgs(t *testing.T) {
+	method := MustNewMethod("function transfer(address to, uint256 amount)")
+
+	args, err := ParseArgs(method.Inputs, []string{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "1e18"})
+	require.NoError(t, err)
+
+	_, err = method.Encode(args)
+	require.NoError(t, err)
+
+	_, err = ParseArgs(method.Inputs, []string{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"})
+	require.Error(t, err)
+}
+
+func TestFormatArg(t *testing.T) {
+	typ := MustNewType("tuple(address a, int256 b, bytes c, bytes2[] d, string e, bool f, ufixed8x1 g)")
+
+	str, err := FormatArg(typ, map[string]interface{}{
+		"a": ethgo.HexToAddress("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"),
+		"b": big.NewInt(-1),
+		"c": []byte{0xab},
+		"d": [][2]byte{{0x1, 0x2}},
+		"e": `<"hi">`,
+		"f": true,
+		"g": "0.5",
+	})
+	require.NoError(t, err)
+	require.Equal(t, `(0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed,-1,0xab,[0x0102],"<\"hi\">",true,0.5)`, str)
+
+	_, err = FormatArg(MustNewType("bytes2"), []byte{0x1})
+	require.Error(t, err)
+}
+
+func TestFormatArg_Random(t *testing.T) {
+	for i := 0; i < 500; i++ {
+		typ := MustNewType(randomType())
+		if i%5 == 0 {
+			typ = MustNewType("tuple(" + randomFixedPointType() + "[] a)")
+		}
+
+		input := generateRandomType(typ)
+
+		str, err := FormatArg(typ, input)
+		require.NoError(t, err)
+
+		// the formatted value is parsed back into the same value
+		output, err := ParseArg(typ, str)
+		require.NoError(t, err, str)
+
+		expected, err := Encode(input, typ)
+		require.NoError(t, err)
+		found, err := Encode(output, typ)
+		require.NoError(t, err)
+		require.Equal(t, expected, found, str)
+	}
+}
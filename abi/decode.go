@@ -16,17 +16,22 @@ const (
 )
 
 // Decode decodes the input with a given type
-func Decode(t *Type, input []byte) (interface{}, error) {
+func Decode(t *Type, input []byte, opts ...DecodeOption) (interface{}, error) {
 	if len(input) == 0 {
 		return nil, fmt.Errorf("empty input")
 	}
+	if newDecodeOpts(opts).strict {
+		if err := validateCanonical(t, input); err != nil {
+			return nil, err
+		}
+	}
 	val, _, err := decode(t, input)
 	return val, err
 }
 
 // DecodeStruct decodes the input with a type to a struct
-func DecodeStruct(t *Type, input []byte, out interface{}) error {
-	val, err := Decode(t, input)
+func DecodeStruct(t *Type, input []byte, out interface{}, opts ...DecodeOption) error {
+	val, err := Decode(t, input, opts...)
 	if err != nil {
 		return err
 	}
@@ -83,6 +88,9 @@ func decode(t *Type, input []byte) (interface{}, []byte, error) {
 	case KindInt, KindUInt:
 		val = readInteger(t, data)
 
+	case KindFixedPoint:
+		val = readFixedPoint(t, data)
+
 	case KindString:
 		val = string(input[storageSlotSize : storageSlotSize+length])
 
@@ -150,18 +158,26 @@ func readInteger(t *Type, b []byte) interface{} {
 		return int64(binary.BigEndian.Uint64(b[len(b)-8:]))
 
 	default:
-		ret := new(big.Int).SetBytes(b)
-		if t.kind == KindUInt {
-			return ret
-		}
+		return readBigInt(b, t.kind == KindInt)
+	}
+}
 
-		if ret.Cmp(maxInt256) > 0 {
-			ret.Add(maxUint256, big.NewInt(0).Neg(ret))
-			ret.Add(ret, big.NewInt(1))
-			ret.Neg(ret)
-		}
+func readBigInt(b []byte, signed bool) *big.Int {
+	ret := new(big.Int).SetBytes(b)
+	if !signed {
 		return ret
 	}
+
+	if ret.Cmp(maxInt256) > 0 {
+		ret.Add(maxUint256, big.NewInt(0).Neg(ret))
+		ret.Add(ret, big.NewInt(1))
+		ret.Neg(ret)
+	}
+	return ret
+}
+
+func readFixedPoint(t *Type, b []byte) *ethgo.Decimal {
+	return ethgo.NewDecimal(readBigInt(b, t.signed), t.decimals)
 }
 
 func readFunctionType(word []byte) ([24]byte, error) {
@@ -282,11 +298,10 @@ func decodeBool(data []byte) (interface{}, error) {
 }
 
 func readOffset(data []byte, len int) (int, error) {
-	offsetBig := big.NewInt(0).SetBytes(data[0:storageSlotSize])
-	if offsetBig.BitLen() > 63 {
-		return 0, fmt.Errorf("offset larger than int64: %v", offsetBig.Int64())
+	if !allZeros(data[:24]) || data[24]&0x80 != 0 {
+		return 0, fmt.Errorf("offset larger than int64: %v", new(big.Int).SetBytes(data[0:storageSlotSize]))
 	}
-	offset := int(offsetBig.Int64())
+	offset := int(binary.BigEndian.Uint64(data[24:storageSlotSize]))
 	if offset > len {
 		return 0, fmt.Errorf("offset insufficient %v require %v", len, offset)
 	}
@@ -294,11 +309,10 @@ func readOffset(data []byte, len int) (int, error) {
 }
 
 func readLength(data []byte) (int, error) {
-	lengthBig := big.NewInt(0).SetBytes(data[0:storageSlotSize])
-	if lengthBig.BitLen() > 63 {
-		return 0, fmt.Errorf("length larger than int64: %v", lengthBig.Int64())
+	if !allZeros(data[:24]) || data[24]&0x80 != 0 {
+		return 0, fmt.Errorf("length larger than int64: %v", new(big.Int).SetBytes(data[0:storageSlotSize]))
 	}
-	length := int(lengthBig.Uint64())
+	length := int(binary.BigEndian.Uint64(data[24:storageSlotSize]))
 
 	// if we trim the length in the data there should be enough
 	// bytes to cover the length
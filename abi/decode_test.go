@@ -1,8 +1,10 @@
 package abi
 
 import (
+	"encoding/hex"
 	"testing"
 
+	"github.com/Ethernal-Tech/ethgo"
 	"github.com/stretchr/testify/require"
 )
 
@@ -19,3 +21,30 @@ func TestDecode_DynamicLengthOutOfBounds(t *testing.T) {
 	_, err := Decode(typ, input)
 	require.Error(t, err)
 }
+
+func TestDecode_FixedPoint(t *testing.T) {
+	cases := []struct {
+		signed bool
+		input  string
+		value  string
+	}{
+		{false, "00000000000000000000000000000000000000000000000000000000000004d2", "1.234"},
+		{true, "fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffb2e", "-1.234"},
+		{true, "0000000000000000000000000000000000000000000000000000000000000001", "0.001"},
+	}
+
+	for _, c := range cases {
+		typ := &Type{kind: KindFixedPoint, size: 128, decimals: 3, signed: c.signed, t: decimalT}
+
+		input, err := hex.DecodeString(c.input)
+		require.NoError(t, err)
+
+		val, err := Decode(typ, input)
+		require.NoError(t, err)
+
+		dec, ok := val.(*ethgo.Decimal)
+		require.True(t, ok)
+		require.Equal(t, 3, dec.Decimals)
+		require.Equal(t, c.value, dec.String())
+	}
+}
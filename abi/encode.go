@@ -45,6 +45,9 @@ func encode(v reflect.Value, t *Type) ([]byte, error) {
 	case KindInt, KindUInt:
 		return encodeNum(v)
 
+	case KindFixedPoint:
+		return encodeFixedPoint(v, t)
+
 	case KindBytes:
 		return encodeBytes(v)
 
@@ -266,6 +269,59 @@ func encodeNum(v reflect.Value) ([]byte, error) {
 	}
 }
 
+func encodeFixedPoint(v reflect.Value, t *Type) ([]byte, error) {
+	if !v.IsValid() {
+		return nil, fmt.Errorf("failed to encode nil value as %s", t)
+	}
+
+	var num *ethgo.Decimal
+	switch {
+	case v.Type() == decimalT:
+		num = v.Interface().(*ethgo.Decimal)
+		if num == nil || num.Value == nil {
+			return nil, fmt.Errorf("failed to encode nil decimal as %s", t)
+		}
+
+	case v.Type() == decimalT.Elem():
+		val := v.Interface().(ethgo.Decimal)
+		if val.Value == nil {
+			return nil, fmt.Errorf("failed to encode nil decimal as %s", t)
+		}
+		num = &val
+
+	case v.Kind() == reflect.String:
+		var err error
+		if num, err = ethgo.ParseDecimal(v.String(), t.decimals); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, encodeErr(v, "decimal")
+	}
+
+	// the scale of the value has to match the decimals of the type
+	num, err := num.Rescale(t.decimals)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode as %s: %v", t, err)
+	}
+
+	value := num.Value
+	if value.Sign() < 0 && !t.signed {
+		return nil, fmt.Errorf("failed to encode negative decimal %s as %s", num, t)
+	}
+
+	// check that the value fits in the bits of the type
+	bits := t.size
+	if t.signed {
+		bits--
+	}
+	limit := new(big.Int).Lsh(one, uint(bits))
+	if value.Cmp(limit) >= 0 || (value.Sign() < 0 && new(big.Int).Neg(value).Cmp(limit) > 0) {
+		return nil, fmt.Errorf("decimal %s out of range for %s", num, t)
+	}
+	return toU256(value), nil
+}
+
 func encodeBool(v reflect.Value) ([]byte, error) {
 	if v.Kind() != reflect.Bool {
 		return nil, encodeErr(v, "bool")
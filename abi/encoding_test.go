@@ -8,6 +8,7 @@ import (
 	"os"
 	"reflect"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
@@ -828,3 +829,98 @@ func TestEncodingStruct_dynamicTuple(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, obj, obj2)
 }
+
+func TestEncodingFixedPoint(t *testing.T) {
+	cases := []struct {
+		typ    string
+		input  interface{}
+		output string
+		value  string
+	}{
+		{
+			"fixed128x18",
+			ethgo.NewDecimal(big.NewInt(15), 1),
+			"00000000000000000000000000000000000000000000000014d1120d7b160000",
+			"1.5",
+		},
+		{
+			"fixed128x18",
+			"-1.5",
+			"ffffffffffffffffffffffffffffffffffffffffffffffffeb2eedf284ea0000",
+			"-1.5",
+		},
+		{
+			"ufixed8x1",
+			ethgo.Decimal{Value: big.NewInt(255), Decimals: 1},
+			"00000000000000000000000000000000000000000000000000000000000000ff",
+			"25.5",
+		},
+		{
+			"fixed8x1",
+			"-12.8",
+			"ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff80",
+			"-12.8",
+		},
+	}
+
+	for _, c := range cases {
+		typ := MustNewType(c.typ)
+
+		res, err := Encode(c.input, typ)
+		require.NoError(t, err)
+		require.Equal(t, c.output, hex.EncodeToString(res))
+
+		val, err := Decode(typ, res)
+		require.NoError(t, err)
+		require.Equal(t, c.value, val.(*ethgo.Decimal).String())
+	}
+
+	failures := []struct {
+		typ   string
+		input interface{}
+	}{
+		// more decimals than the type
+		{"fixed128x2", "1.001"},
+		{"fixed128x2", ethgo.NewDecimal(big.NewInt(1001), 3)},
+		// negative value for an unsigned type
+		{"ufixed128x2", "-1"},
+		// out of range
+		{"ufixed8x1", "25.6"},
+		{"fixed8x1", "12.8"},
+		{"fixed8x1", "-12.9"},
+		// not a decimal
+		{"fixed128x2", big.NewInt(1)},
+		{"fixed128x2", (*ethgo.Decimal)(nil)},
+		{"fixed128x2", 1.5},
+	}
+
+	for _, c := range failures {
+		_, err := Encode(c.input, MustNewType(c.typ))
+		require.Error(t, err, c.typ)
+	}
+}
+
+func TestRandomEncoding_FixedPoint(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		elems := []string{}
+		for j := 0; j < randomInt(1, 5); j++ {
+			elem := randomFixedPointType()
+			if randomInt(0, 2) == 1 {
+				elem += "[]"
+			}
+			elems = append(elems, fmt.Sprintf("%s arg%d", elem, j))
+		}
+		tt := MustNewType(fmt.Sprintf("tuple(%s)", strings.Join(elems, ",")))
+
+		input := generateRandomType(tt)
+
+		res, err := Encode(input, tt)
+		require.NoError(t, err)
+
+		output, err := Decode(tt, res)
+		require.NoError(t, err)
+		require.Equal(t, input, output)
+
+		require.NoError(t, testDecodePanic(tt, input))
+	}
+}
@@ -0,0 +1,429 @@
+package abi
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// argumentJSON is an argument of the json abi
+type argumentJSON struct {
+	Name         string          `json:"name"`
+	Type         string          `json:"type"`
+	InternalType string          `json:"internalType,omitempty"`
+	Components   []*argumentJSON `json:"components,omitempty"`
+	Indexed      *bool           `json:"indexed,omitempty"`
+}
+
+// methodJSON is a function, constructor, fallback or receive item of the json abi
+type methodJSON struct {
+	Type            string           `json:"type"`
+	Name            string           `json:"name,omitempty"`
+	Inputs          *[]*argumentJSON `json:"inputs,omitempty"`
+	Outputs         *[]*argumentJSON `json:"outputs,omitempty"`
+	StateMutability string           `json:"stateMutability"`
+}
+
+// eventJSON is an event item of the json abi
+type eventJSON struct {
+	Type      string          `json:"type"`
+	Name      string          `json:"name"`
+	Inputs    []*argumentJSON `json:"inputs"`
+	Anonymous bool            `json:"anonymous"`
+}
+
+// errorJSON is an error item of the json abi
+type errorJSON struct {
+	Type   string          `json:"type"`
+	Name   string          `json:"name"`
+	Inputs []*argumentJSON `json:"inputs"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. The items are sorted by
+// kind and name since the abi does not keep the order of the original source.
+func (a *ABI) MarshalJSON() ([]byte, error) {
+	fields := []interface{}{}
+	if a.Constructor != nil {
+		inputs := newArgumentsJSON(a.Constructor.Inputs, false)
+		fields = append(fields, &methodJSON{
+			Type:            "constructor",
+			Inputs:          &inputs,
+			StateMutability: a.Constructor.stateMutability(),
+		})
+	}
+	for _, name := range sortedKeys(a.Methods) {
+		fields = append(fields, a.Methods[name].fieldJSON())
+	}
+	for _, name := range sortedKeys(a.Events) {
+		fields = append(fields, a.Events[name].fieldJSON())
+	}
+	for _, name := range sortedKeys(a.Errors) {
+		fields = append(fields, a.Errors[name].fieldJSON())
+	}
+	if a.Fallback != nil {
+		fields = append(fields, &methodJSON{
+			Type:            "fallback",
+			StateMutability: a.Fallback.stateMutability(),
+		})
+	}
+	if a.Receive != nil {
+		fields = append(fields, &methodJSON{
+			Type:            "receive",
+			StateMutability: "payable",
+		})
+	}
+	return json.Marshal(fields)
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (m *Method) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.fieldJSON())
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (e *Event) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.fieldJSON())
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.fieldJSON())
+}
+
+func (m *Method) fieldJSON() *methodJSON {
+	inputs := newArgumentsJSON(m.Inputs, false)
+	outputs := newArgumentsJSON(m.Outputs, false)
+	return &methodJSON{
+		Type:            "function",
+		Name:            m.Name,
+		Inputs:          &inputs,
+		Outputs:         &outputs,
+		StateMutability: m.stateMutability(),
+	}
+}
+
+func (e *Event) fieldJSON() *eventJSON {
+	return &eventJSON{
+		Type:      "event",
+		Name:      e.Name,
+		Inputs:    newArgumentsJSON(e.Inputs, true),
+		Anonymous: e.Anonymous,
+	}
+}
+
+func (e *Error) fieldJSON() *errorJSON {
+	return &errorJSON{
+		Type:   "error",
+		Name:   e.Name,
+		Inputs: newArgumentsJSON(e.Inputs, false),
+	}
+}
+
+// stateMutability returns the state mutability of the method or the
+// one implied by the const flag if the abi did not include it
+func (m *Method) stateMutability() string {
+	if m.StateMutability != "" {
+		return m.StateMutability
+	}
+	if m.Const {
+		return "view"
+	}
+	return "nonpayable"
+}
+
+// newArgumentsJSON returns the json arguments of a tuple type. The indexed
+// flag is only included for events.
+func newArgumentsJSON(t *Type, event bool) []*argumentJSON {
+	if t == nil {
+		return []*argumentJSON{}
+	}
+	res := make([]*argumentJSON, len(t.tuple))
+	for i, elem := range t.tuple {
+		arg := newArgumentJSON(elem.Name, elem.Elem)
+		if event {
+			indexed := elem.Indexed
+			arg.Indexed = &indexed
+		}
+		res[i] = arg
+	}
+	return res
+}
+
+func newArgumentJSON(name string, t *Type) *argumentJSON {
+	typ, tuple := jsonTypeName(t)
+
+	arg := &argumentJSON{
+		Name:         name,
+		Type:         typ,
+		InternalType: t.itype,
+	}
+	if tuple != nil {
+		arg.Components = make([]*argumentJSON, len(tuple.tuple))
+		for i, elem := range tuple.tuple {
+			arg.Components[i] = newArgumentJSON(elem.Name, elem.Elem)
+		}
+	}
+	return arg
+}
+
+// jsonTypeName returns the name of the type in the json abi where the
+// tuples are named 'tuple' and their elements are the components.
+// It also returns the tuple type if the type is a tuple or an array of tuples.
+func jsonTypeName(t *Type) (string, *Type) {
+	switch t.kind {
+	case KindTuple:
+		return "tuple", t
+
+	case KindArray:
+		name, tuple := jsonTypeName(t.elem)
+		return fmt.Sprintf("%s[%d]", name, t.size), tuple
+
+	case KindSlice:
+		name, tuple := jsonTypeName(t.elem)
+		return name + "[]", tuple
+
+	default:
+		return t.String(), nil
+	}
+}
+
+// HumanReadable returns the abi in the human readable format accepted by NewABIFromList
+func (a *ABI) HumanReadable() []string {
+	res := []string{}
+	if a.Constructor != nil {
+		res = append(res, "constructor"+humanReadableArgs(a.Constructor.Inputs)+modifier(a.Constructor.StateMutability))
+	}
+	for _, name := range sortedKeys(a.Methods) {
+		res = append(res, a.Methods[name].HumanReadable())
+	}
+	for _, name := range sortedKeys(a.Events) {
+		res = append(res, a.Events[name].HumanReadable())
+	}
+	for _, name := range sortedKeys(a.Errors) {
+		res = append(res, a.Errors[name].HumanReadable())
+	}
+	if a.Fallback != nil {
+		res = append(res, "fallback()"+modifier(a.Fallback.StateMutability))
+	}
+	if a.Receive != nil {
+		res = append(res, "receive() payable")
+	}
+	return res
+}
+
+// HumanReadable returns the method in the human readable format (i.e. 'function balanceOf(address owner) view returns (uint256)')
+func (m *Method) HumanReadable() string {
+	str := "function " + m.Name + humanReadableArgs(m.Inputs) + modifier(m.stateMutability())
+	if m.Outputs != nil && len(m.Outputs.tuple) != 0 {
+		str += " returns " + humanReadableArgs(m.Outputs)
+	}
+	return str
+}
+
+// HumanReadable returns the event in the human readable format (i.e. 'event Transfer(address indexed from, address indexed to, uint256 value)')
+func (e *Event) HumanReadable() string {
+	str := "event " + e.Name + humanReadableArgs(e.Inputs)
+	if e.Anonymous {
+		str += " anonymous"
+	}
+	return str
+}
+
+// HumanReadable returns the error in the human readable format (i.e. 'error InsufficientBalance(uint256 available)')
+func (e *Error) HumanReadable() string {
+	return "error " + e.Name + humanReadableArgs(e.Inputs)
+}
+
+func humanReadableArgs(t *Type) string {
+	if t == nil {
+		return "()"
+	}
+	args := make([]string, len(t.tuple))
+	for i, elem := range t.tuple {
+		arg := elem.Elem.Format(true)
+		if elem.Indexed {
+			arg += " indexed"
+		}
+		if elem.Name != "" {
+			arg += " " + elem.Name
+		}
+		args[i] = arg
+	}
+	return "(" + strings.Join(args, ", ") + ")"
+}
+
+// modifier returns the state mutability as a modifier of the
+// human readable format. Non payable is the default and omitted.
+func modifier(stateMutability string) string {
+	if stateMutability == "" || stateMutability == "nonpayable" {
+		return ""
+	}
+	return " " + stateMutability
+}
+
+// SolidityInterface returns the source of a Solidity interface with the given name
+// for the abi. The tuples are declared as structs named after their internal type
+// (or Struct0, Struct1... if the abi does not include it).
+func (a *ABI) SolidityInterface(name string) string {
+	s := &solidityStructs{names: map[string]string{}, used: map[string]bool{}}
+
+	body := []string{}
+	for _, key := range sortedKeys(a.Errors) {
+		e := a.Errors[key]
+		body = append(body, fmt.Sprintf("error %s(%s);", e.Name, s.params(e.Inputs, "", false)))
+	}
+	for _, key := range sortedKeys(a.Events) {
+		e := a.Events[key]
+		str := fmt.Sprintf("event %s(%s)", e.Name, s.params(e.Inputs, "", true))
+		if e.Anonymous {
+			str += " anonymous"
+		}
+		body = append(body, str+";")
+	}
+	for _, key := range sortedKeys(a.Methods) {
+		m := a.Methods[key]
+		str := fmt.Sprintf("function %s(%s) external%s", m.Name, s.params(m.Inputs, "calldata", false), modifier(m.stateMutability()))
+		if m.Outputs != nil && len(m.Outputs.tuple) != 0 {
+			str += fmt.Sprintf(" returns (%s)", s.params(m.Outputs, "memory", false))
+		}
+		body = append(body, str+";")
+	}
+	if a.Fallback != nil {
+		body = append(body, "fallback() external"+modifier(a.Fallback.StateMutability)+";")
+	}
+	if a.Receive != nil {
+		body = append(body, "receive() external payable;")
+	}
+
+	var b strings.Builder
+	b.WriteString("// SPDX-License-Identifier: UNLICENSED\n")
+	b.WriteString("pragma solidity ^0.8.4;\n\n")
+	b.WriteString("interface " + name + " {\n")
+	for _, str := range s.decls {
+		b.WriteString(str)
+	}
+	for _, str := range body {
+		b.WriteString("    " + str + "\n")
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// solidityStructs declares the structs of the tuples in a Solidity interface
+type solidityStructs struct {
+	// names are the names of the struct declared for each tuple
+	names map[string]string
+	used  map[string]bool
+	decls []string
+}
+
+func (s *solidityStructs) params(t *Type, location string, event bool) string {
+	if t == nil {
+		return ""
+	}
+	params := make([]string, len(t.tuple))
+	for i, elem := range t.tuple {
+		param := s.typeName(elem.Elem, "")
+		if location != "" && (elem.Elem.isDynamicType() || elem.Elem.kind == KindTuple || elem.Elem.kind == KindArray) {
+			param += " " + location
+		}
+		if event && elem.Indexed {
+			param += " indexed"
+		}
+		if elem.Name != "" {
+			param += " " + elem.Name
+		}
+		params[i] = param
+	}
+	return strings.Join(params, ", ")
+}
+
+// typeName returns the Solidity type. The internal type of arrays of
+// tuples is only set in the array type and it is passed as itype.
+func (s *solidityStructs) typeName(t *Type, itype string) string {
+	if t.itype != "" {
+		itype = t.itype
+	}
+
+	switch t.kind {
+	case KindTuple:
+		return s.declare(t, itype)
+
+	case KindArray:
+		return fmt.Sprintf("%s[%d]", s.typeName(t.elem, itype), t.size)
+
+	case KindSlice:
+		return s.typeName(t.elem, itype) + "[]"
+
+	case KindFunction:
+		if strings.HasPrefix(itype, "function") {
+			return itype
+		}
+		return "function() external"
+
+	default:
+		return t.String()
+	}
+}
+
+// declare declares the struct for the tuple and returns its name
+func (s *solidityStructs) declare(t *Type, itype string) string {
+	key := t.Format(true)
+	if name, ok := s.names[key]; ok {
+		return name
+	}
+
+	// the fields are declared first since they are used by the struct
+	fields := make([]string, len(t.tuple))
+	for i, elem := range t.tuple {
+		fieldName := elem.Name
+		if fieldName == "" {
+			fieldName = fmt.Sprintf("field%d", i)
+		}
+		fields[i] = fmt.Sprintf("        %s %s;\n", s.typeName(elem.Elem, ""), fieldName)
+	}
+
+	name := structName(itype)
+	if name == "" || s.used[name] {
+		base := name
+		if base == "" {
+			base = "Struct"
+		}
+		for i := 0; ; i++ {
+			name = fmt.Sprintf("%s%d", base, i)
+			if !s.used[name] {
+				break
+			}
+		}
+	}
+	s.names[key] = name
+	s.used[name] = true
+
+	s.decls = append(s.decls, "    struct "+name+" {\n"+strings.Join(fields, "")+"    }\n\n")
+	return name
+}
+
+// structName returns the name of the struct in an internal type (i.e. 'struct Contract.Name[]')
+func structName(itype string) string {
+	if !strings.HasPrefix(itype, "struct ") {
+		return ""
+	}
+	name := strings.TrimPrefix(itype, "struct ")
+	if indx := strings.Index(name, "["); indx != -1 {
+		name = name[:indx]
+	}
+	if indx := strings.LastIndex(name, "."); indx != -1 {
+		name = name[indx+1:]
+	}
+	return name
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,201 @@
+package abi
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const exportAbi = `[
+	{
+		"type": "constructor",
+		"inputs": [{"name": "owner", "type": "address", "internalType": "address"}],
+		"stateMutability": "payable"
+	},
+	{
+		"type": "function",
+		"name": "addPeople",
+		"inputs": [
+			{
+				"name": "people",
+				"type": "tuple[]",
+				"internalType": "struct Registry.Person[]",
+				"components": [
+					{"name": "name", "type": "string", "internalType": "string"},
+					{"name": "age", "type": "uint16", "internalType": "uint16"}
+				]
+			}
+		],
+		"outputs": [],
+		"stateMutability": "nonpayable"
+	},
+	{
+		"type": "function",
+		"name": "getPerson",
+		"inputs": [{"name": "id", "type": "uint256", "internalType": "uint256"}],
+		"outputs": [
+			{
+				"name": "",
+				"type": "tuple",
+				"internalType": "struct Registry.Person",
+				"components": [
+					{"name": "name", "type": "string", "internalType": "string"},
+					{"name": "age", "type": "uint16", "internalType": "uint16"}
+				]
+			}
+		],
+		"stateMutability": "view"
+	},
+	{
+		"type": "event",
+		"name": "PersonAdded",
+		"inputs": [
+			{"name": "id", "type": "uint256", "indexed": true, "internalType": "uint256"},
+			{"name": "name", "type": "string", "indexed": false, "internalType": "string"}
+		],
+		"anonymous": false
+	},
+	{
+		"type": "event",
+		"name": "Raw",
+		"inputs": [{"name": "data", "type": "bytes32[2]", "indexed": false, "internalType": "bytes32[2]"}],
+		"anonymous": true
+	},
+	{
+		"type": "error",
+		"name": "NotFound",
+		"inputs": [{"name": "id", "type": "uint256", "internalType": "uint256"}]
+	},
+	{
+		"type": "fallback",
+		"stateMutability": "nonpayable"
+	},
+	{
+		"type": "receive",
+		"stateMutability": "payable"
+	}
+]`
+
+func TestExport_JSON(t *testing.T) {
+	a, err := NewABI(exportAbi)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(a)
+	require.NoError(t, err)
+	require.JSONEq(t, exportAbi, string(data))
+
+	a2, err := NewABI(string(data))
+	require.NoError(t, err)
+	require.Equal(t, a, a2)
+
+	// single items
+	data, err = json.Marshal(a.Errors["NotFound"])
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type": "error", "name": "NotFound", "inputs": [{"name": "id", "type": "uint256", "internalType": "uint256"}]}`, string(data))
+
+	data, err = json.Marshal(MustNewMethod("function balanceOf(address owner) returns (uint256)"))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"type": "function",
+		"name": "balanceOf",
+		"inputs": [{"name": "owner", "type": "address"}],
+		"outputs": [{"name": "", "type": "uint256"}],
+		"stateMutability": "nonpayable"
+	}`, string(data))
+
+	data, err = json.Marshal(MustNewEvent("event Transfer(address indexed from, address indexed to, uint256 value)"))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"type": "event",
+		"name": "Transfer",
+		"inputs": [
+			{"name": "from", "type": "address", "indexed": true},
+			{"name": "to", "type": "address", "indexed": true},
+			{"name": "value", "type": "uint256", "indexed": false}
+		],
+		"anonymous": false
+	}`, string(data))
+}
+
+func TestExport_HumanReadable(t *testing.T) {
+	a, err := NewABI(exportAbi)
+	require.NoError(t, err)
+
+	list := a.HumanReadable()
+	require.Equal(t, []string{
+		"constructor(address owner) payable",
+		"function addPeople(tuple(string name,uint16 age)[] people)",
+		"function getPerson(uint256 id) view returns (tuple(string name,uint16 age))",
+		"event PersonAdded(uint256 indexed id, string name)",
+		"event Raw(bytes32[2] data) anonymous",
+		"error NotFound(uint256 id)",
+		"fallback()",
+		"receive() payable",
+	}, list)
+
+	a2, err := NewABIFromList(list)
+	require.NoError(t, err)
+	require.Equal(t, list, a2.HumanReadable())
+
+	for name, m := range a.Methods {
+		require.Equal(t, m.Sig(), a2.Methods[name].Sig())
+		require.Equal(t, m.stateMutability(), a2.Methods[name].stateMutability())
+		require.Equal(t, m.Const, a2.Methods[name].Const)
+	}
+	require.True(t, a2.Events["Raw"].Anonymous)
+	require.Equal(t, "payable", a2.Constructor.StateMutability)
+}
+
+func TestExport_SolidityInterface(t *testing.T) {
+	a, err := NewABI(exportAbi)
+	require.NoError(t, err)
+
+	expected := `// SPDX-License-Identifier: UNLICENSED
+pragma solidity ^0.8.4;
+
+interface IRegistry {
+    struct Person {
+        string name;
+        uint16 age;
+    }
+
+    error NotFound(uint256 id);
+    event PersonAdded(uint256 indexed id, string name);
+    event Raw(bytes32[2] data) anonymous;
+    function addPeople(Person[] calldata people) external;
+    function getPerson(uint256 id) external view returns (Person memory);
+    fallback() external;
+    receive() external payable;
+}
+`
+	require.Equal(t, expected, a.SolidityInterface("IRegistry"))
+
+	// tuples without internal type
+	a, err = NewABIFromList([]string{
+		"function f(tuple(uint256 a, tuple(bool) b) x, tuple(address) y) pure returns (uint256[] z)",
+	})
+	require.NoError(t, err)
+
+	expected = `// SPDX-License-Identifier: UNLICENSED
+pragma solidity ^0.8.4;
+
+interface I {
+    struct Struct0 {
+        bool field0;
+    }
+
+    struct Struct1 {
+        uint256 a;
+        Struct0 b;
+    }
+
+    struct Struct2 {
+        address field0;
+    }
+
+    function f(Struct1 calldata x, Struct2 calldata y) external pure returns (uint256[] memory z);
+}
+`
+	require.Equal(t, expected, a.SolidityInterface("I"))
+}
@@ -0,0 +1,177 @@
+package abi
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/Ethernal-Tech/ethgo"
+)
+
+// EncodePacked encodes the values with the non-standard packed mode of Solidity (abi.encodePacked):
+//   - static types take only the bytes they need (i.e. uint16 takes two bytes) without padding
+//   - bytes and string are encoded in place without the length
+//   - the elements of arrays and slices are padded to 32 bytes without the length
+//
+// Tuples and arrays of dynamic types are not supported in packed mode.
+func EncodePacked(values []interface{}, types []*Type) ([]byte, error) {
+	if len(values) != len(types) {
+		return nil, fmt.Errorf("expected %d values but found %d", len(types), len(values))
+	}
+
+	res := []byte{}
+	for indx, typ := range types {
+		data, err := encodePacked(reflect.ValueOf(values[indx]), typ)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode value %d: %v", indx, err)
+		}
+		res = append(res, data...)
+	}
+	return res, nil
+}
+
+// SolidityKeccak returns the keccak256 hash of the packed encoding of the
+// values (keccak256(abi.encodePacked(...)) in Solidity)
+func SolidityKeccak(values []interface{}, types []*Type) (ethgo.Hash, error) {
+	data, err := EncodePacked(values, types)
+	if err != nil {
+		return ethgo.Hash{}, err
+	}
+	return ethgo.BytesToHash(ethgo.Keccak256(data)), nil
+}
+
+func encodePacked(v reflect.Value, t *Type) ([]byte, error) {
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+
+	switch t.kind {
+	case KindTuple:
+		return nil, fmt.Errorf("tuple types are not supported in packed mode")
+
+	case KindSlice, KindArray:
+		return encodePackedArray(v, t)
+
+	case KindString:
+		if v.Kind() != reflect.String {
+			return nil, encodeErr(v, "string")
+		}
+		return []byte(v.String()), nil
+
+	case KindBytes:
+		return packedBytes(v)
+
+	case KindFixedBytes, KindFunction:
+		buf, err := packedBytes(v)
+		if err != nil {
+			return nil, err
+		}
+		if len(buf) > t.size {
+			return nil, fmt.Errorf("%d bytes do not fit in %s", len(buf), t)
+		}
+		return rightPad(buf, t.size), nil
+
+	case KindBool:
+		buf, err := encodeBool(v)
+		if err != nil {
+			return nil, err
+		}
+		return buf[31:], nil
+
+	case KindAddress:
+		if v.Kind() != reflect.Array && v.Kind() != reflect.Slice && v.Kind() != reflect.String {
+			return nil, encodeErr(v, "address")
+		}
+		buf, err := encodeAddress(v)
+		if err != nil {
+			return nil, err
+		}
+		return buf[12:], nil
+
+	case KindInt, KindUInt, KindFixedPoint:
+		var (
+			buf []byte
+			err error
+		)
+		if t.kind == KindFixedPoint {
+			buf, err = encodeFixedPoint(v, t)
+		} else {
+			buf, err = encodeNum(v)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return truncateNum(buf, t)
+
+	default:
+		return nil, fmt.Errorf("packed encoding not available for type '%s'", t.kind)
+	}
+}
+
+func encodePackedArray(v reflect.Value, t *Type) ([]byte, error) {
+	if v.Kind() != reflect.Array && v.Kind() != reflect.Slice {
+		return nil, encodeErr(v, "array or slice")
+	}
+	if t.kind == KindArray && t.size != v.Len() {
+		return nil, fmt.Errorf("expected array of size %d but found %d", t.size, v.Len())
+	}
+
+	base := t.elem
+	for base.kind == KindSlice || base.kind == KindArray {
+		base = base.elem
+	}
+	if base.kind == KindTuple || base.isDynamicType() {
+		return nil, fmt.Errorf("arrays of %s are not supported in packed mode", base)
+	}
+
+	elem := t.elem
+	res := []byte{}
+	for i := 0; i < v.Len(); i++ {
+		var (
+			buf []byte
+			err error
+		)
+		if elem.kind == KindSlice || elem.kind == KindArray {
+			// nested arrays are concatenated with the same rules
+			buf, err = encodePackedArray(v.Index(i), elem)
+		} else {
+			// the elements of the arrays are padded to 32 bytes
+			buf, err = encode(v.Index(i), elem)
+		}
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, buf...)
+	}
+	return res, nil
+}
+
+func packedBytes(v reflect.Value) ([]byte, error) {
+	if v.Kind() == reflect.Array {
+		v = convertArrayToBytes(v)
+	}
+	if v.Kind() == reflect.String {
+		return decodeHex(v.String())
+	}
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() != reflect.Uint8 {
+		return nil, encodeErr(v, "bytes")
+	}
+	return v.Bytes(), nil
+}
+
+// truncateNum returns the bytes of a 32 bytes encoded number
+// used by the type and checks that the value fits in them
+func truncateNum(buf []byte, t *Type) ([]byte, error) {
+	size := t.size / 8
+	head, tail := buf[:32-size], buf[32-size:]
+
+	var pad byte
+	if (t.kind == KindInt || (t.kind == KindFixedPoint && t.signed)) && tail[0]&0x80 != 0 {
+		pad = 0xff
+	}
+	for _, b := range head {
+		if b != pad {
+			return nil, fmt.Errorf("value out of range for %s", t)
+		}
+	}
+	return tail, nil
+}
@@ -0,0 +1,174 @@
+package abi
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func packedTypes(strs ...string) []*Type {
+	types := []*Type{}
+	for _, str := range strs {
+		types = append(types, MustNewType(str))
+	}
+	return types
+}
+
+func TestEncodePacked(t *testing.T) {
+	cases := []struct {
+		types  []string
+		values []interface{}
+		output string
+	}{
+		{
+			// example from the Solidity documentation
+			[]string{"int16", "bytes1", "uint16", "string"},
+			[]interface{}{int16(-1), [1]byte{0x42}, uint16(3), "Hello, world!"},
+			"ffff42000348656c6c6f2c20776f726c6421",
+		},
+		{
+			[]string{"address", "bool", "bytes"},
+			[]interface{}{ethgo.Address{0x1}, true, []byte{0xbe, 0xef}},
+			"0100000000000000000000000000000000000000" + "01" + "beef",
+		},
+		{
+			[]string{"uint8[]", "bytes3"},
+			[]interface{}{[]uint8{1, 2}, "0x0102"},
+			strings.Repeat("00", 31) + "01" + strings.Repeat("00", 31) + "02" + "010200",
+		},
+		{
+			// nested arrays
+			[]string{"uint24[2][]"},
+			[]interface{}{[][2]*big.Int{{big.NewInt(1), big.NewInt(2)}, {big.NewInt(3), big.NewInt(4)}}},
+			strings.Repeat("00", 31) + "01" + strings.Repeat("00", 31) + "02" + strings.Repeat("00", 31) + "03" + strings.Repeat("00", 31) + "04",
+		},
+		{
+			[]string{"int256", "ufixed16x1"},
+			[]interface{}{big.NewInt(-2), "0.5"},
+			strings.Repeat("ff", 31) + "fe" + "0005",
+		},
+		{
+			[]string{"address[]"},
+			[]interface{}{[]ethgo.Address{{0x1}}},
+			strings.Repeat("00", 12) + "01" + strings.Repeat("00", 19),
+		},
+	}
+
+	for _, c := range cases {
+		res, err := EncodePacked(c.values, packedTypes(c.types...))
+		require.NoError(t, err)
+		require.Equal(t, c.output, hex.EncodeToString(res))
+	}
+
+	failures := []struct {
+		types  []string
+		values []interface{}
+	}{
+		{[]string{"tuple(uint8)"}, []interface{}{map[string]interface{}{"0": uint8(1)}}},
+		{[]string{"string[]"}, []interface{}{[]string{"a"}}},
+		{[]string{"bytes[2][]"}, []interface{}{[][2][]byte{}}},
+		{[]string{"uint8"}, []interface{}{uint16(256)}},
+		{[]string{"uint8"}, []interface{}{-1}},
+		{[]string{"int8"}, []interface{}{128}},
+		{[]string{"bytes2"}, []interface{}{[]byte{1, 2, 3}}},
+		{[]string{"uint8[2]"}, []interface{}{[]uint8{1}}},
+		{[]string{"address"}, []interface{}{1}},
+		{[]string{"uint8", "uint8"}, []interface{}{1}},
+	}
+
+	for _, c := range failures {
+		_, err := EncodePacked(c.values, packedTypes(c.types...))
+		require.Error(t, err, c.types)
+	}
+}
+
+func TestSolidityKeccak(t *testing.T) {
+	// example from the web3.js documentation of soliditySha3
+	hash, err := SolidityKeccak(
+		[]interface{}{"Hello!%", int8(-23), "0x85F43D8a49eeB85d32Cf465507DD71d507100C1d"},
+		packedTypes("string", "int8", "address"),
+	)
+	require.NoError(t, err)
+	require.Equal(t, "0xa13b31627c1ed7aaded5aecec71baf02fe123797fffd45e662eac8e06fbe4955", hash.String())
+}
+
+func TestIntegrationEncodePacked(t *testing.T) {
+	s := testutil.NewTestServer(t)
+
+	cases := []struct {
+		types  []string
+		values []interface{}
+	}{
+		{
+			[]string{"int16", "bytes1", "uint16", "string"},
+			[]interface{}{int16(-1), [1]byte{0x42}, uint16(3), "Hello, world!"},
+		},
+		{
+			[]string{"address", "bool", "bytes", "int56"},
+			[]interface{}{ethgo.Address{0x1}, true, []byte{0xbe, 0xef}, big.NewInt(-100)},
+		},
+		{
+			[]string{"uint8[]", "bytes3", "address[2]"},
+			[]interface{}{[]uint8{1, 2}, [3]byte{1, 2, 3}, [2]ethgo.Address{{0x1}, {0x2}}},
+		},
+		{
+			[]string{"uint24[2][]", "bool[]"},
+			[]interface{}{[][2]*big.Int{{big.NewInt(1), big.NewInt(2)}, {big.NewInt(3), big.NewInt(4)}}, []bool{true, false}},
+		},
+	}
+
+	for _, c := range cases {
+		types := packedTypes(c.types...)
+
+		// the contract returns abi.encodePacked and its hash of the inputs
+		params, args := []string{}, []string{}
+		for indx, typ := range types {
+			memory := ""
+			if typ.isDynamicType() || typ.kind == KindArray {
+				memory = " memory"
+			}
+			params = append(params, fmt.Sprintf("%s%s arg%d", typ, memory, indx))
+			args = append(args, fmt.Sprintf("arg%d", indx))
+		}
+
+		cc := &testutil.Contract{}
+		cc.AddCallback(func() string {
+			return fmt.Sprintf(`function pack(%s) public pure returns (bytes memory, bytes32) {
+				return (abi.encodePacked(%s), keccak256(abi.encodePacked(%s)));
+			}`, strings.Join(params, ","), strings.Join(args, ","), strings.Join(args, ","))
+		})
+
+		artifact, addr, err := s.DeployContract(cc)
+		require.NoError(t, err)
+
+		abi, err := NewABIFromSlice(artifact.Abi)
+		require.NoError(t, err)
+
+		method := abi.Methods["pack"]
+		data, err := method.Encode(c.values)
+		require.NoError(t, err)
+
+		res, err := s.Call(&ethgo.CallMsg{
+			To:   &addr,
+			Data: data,
+		})
+		require.NoError(t, err)
+
+		output, err := method.Decode(mustDecodeHex(res))
+		require.NoError(t, err)
+
+		packed, err := EncodePacked(c.values, types)
+		require.NoError(t, err)
+		require.Equal(t, output["0"], packed)
+
+		hash, err := SolidityKeccak(c.values, types)
+		require.NoError(t, err)
+		require.Equal(t, output["1"], [32]byte(hash))
+	}
+}
@@ -0,0 +1,513 @@
+package abi
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Ethernal-Tech/ethgo"
+)
+
+var (
+	// builtinErrors are the errors generated by the compiler
+	builtinErrors = []*Error{
+		mustNewError("error Error(string)"),
+		mustNewError("error Panic(uint256)"),
+	}
+)
+
+func mustNewError(name string) *Error {
+	e, err := NewError(name)
+	if err != nil {
+		panic(err)
+	}
+	return e
+}
+
+// Registry decodes transaction inputs, logs and revert data of many contracts.
+// The abis are registered either for a specific contract address or globally,
+// in which case the methods, events and errors are found by their selector.
+// The abi of the contract is used first and the global selectors otherwise.
+type Registry struct {
+	lock      sync.RWMutex
+	contracts map[ethgo.Address]*selectors
+	global    *selectors
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		contracts: map[ethgo.Address]*selectors{},
+		global:    newSelectors(),
+	}
+}
+
+// Register adds the methods, events and errors of the abi to the global selectors
+func (r *Registry) Register(abis ...*ABI) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, a := range abis {
+		r.global.add(a)
+	}
+}
+
+// RegisterContract sets the abi of the contract at the address
+func (r *Registry) RegisterContract(addr ethgo.Address, a *ABI) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	s := newSelectors()
+	s.add(a)
+	r.contracts[addr] = s
+}
+
+// Collision is a selector shared by different methods, events or errors in the global selectors
+type Collision struct {
+	// Kind is either function, event or error
+	Kind       string   `json:"kind"`
+	Selector   string   `json:"selector"`
+	Signatures []string `json:"signatures"`
+}
+
+// Collisions returns the ambiguous selectors of the global abis. Events with the same
+// signature but different indexed arguments (i.e. the Transfer event of ERC20 and ERC721)
+// are also reported since only the number of topics tells them apart.
+func (r *Registry) Collisions() []*Collision {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	res := []*Collision{}
+	for id, methods := range r.global.methods {
+		if len(methods) > 1 {
+			sigs := make([]string, len(methods))
+			for i, m := range methods {
+				sigs[i] = m.Sig()
+			}
+			res = append(res, newCollision("function", id[:], sigs))
+		}
+	}
+	for id, events := range r.global.events {
+		if len(events) > 1 {
+			sigs := make([]string, len(events))
+			for i, e := range events {
+				sigs[i] = indexedSignature(e)
+			}
+			res = append(res, newCollision("event", id[:], sigs))
+		}
+	}
+	for id, errs := range r.global.errors {
+		if len(errs) > 1 {
+			sigs := make([]string, len(errs))
+			for i, e := range errs {
+				sigs[i] = e.Sig()
+			}
+			res = append(res, newCollision("error", id[:], sigs))
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Kind != res[j].Kind {
+			return res[i].Kind < res[j].Kind
+		}
+		return res[i].Selector < res[j].Selector
+	})
+	return res
+}
+
+func newCollision(kind string, id []byte, sigs []string) *Collision {
+	sort.Strings(sigs)
+	return &Collision{Kind: kind, Selector: "0x" + hex.EncodeToString(id), Signatures: sigs}
+}
+
+// DecodedArg is a decoded argument. The value only uses json friendly types: hex
+// strings for bytes, checksummed addresses, decimal strings for integers that
+// do not fit in 32 bits and fixed point numbers, maps for tuples and lists for arrays.
+type DecodedArg struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+// Decoded is a decoded method call, event or error
+type Decoded struct {
+	Name      string        `json:"name"`
+	Signature string        `json:"signature"`
+	Args      []*DecodedArg `json:"args"`
+
+	// Alternatives are the signatures of other candidates with
+	// the same selector that can also decode the data
+	Alternatives []string `json:"alternatives,omitempty"`
+}
+
+// Arg returns the value of the argument with the given name
+func (d *Decoded) Arg(name string) (interface{}, bool) {
+	for _, arg := range d.Args {
+		if arg.Name == name {
+			return arg.Value, true
+		}
+	}
+	return nil, false
+}
+
+// DecodeTransaction decodes the input of the transaction
+func (r *Registry) DecodeTransaction(txn *ethgo.Transaction) (*Decoded, error) {
+	if txn.To == nil {
+		return nil, fmt.Errorf("cannot decode the input of a contract creation")
+	}
+	return r.DecodeInput(*txn.To, txn.Input)
+}
+
+// DecodeInput decodes the input of a call to the contract into the method name and arguments
+func (r *Registry) DecodeInput(addr ethgo.Address, input []byte) (*Decoded, error) {
+	if len(input) < 4 {
+		return nil, fmt.Errorf("input too short to have a method selector")
+	}
+
+	var id [4]byte
+	copy(id[:], input[:4])
+
+	candidates := r.candidates(addr, func(s *selectors) []interface{} {
+		res := []interface{}{}
+		for _, m := range s.methods[id] {
+			res = append(res, m)
+		}
+		return res
+	})
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("method with selector 0x%s not found", hex.EncodeToString(id[:]))
+	}
+
+	return decodeCandidates(candidates, func(c interface{}) (*Decoded, error) {
+		m := c.(*Method)
+		return decodeArgs(m.Name, m.Sig(), m.Inputs, input[4:])
+	})
+}
+
+// DecodeLog decodes the log into the event name and arguments. Anonymous
+// events are not decoded since they do not have a selector.
+func (r *Registry) DecodeLog(log *ethgo.Log) (*Decoded, error) {
+	if len(log.Topics) == 0 {
+		return nil, fmt.Errorf("log without topics")
+	}
+
+	id := log.Topics[0]
+	candidates := r.candidates(log.Address, func(s *selectors) []interface{} {
+		res := []interface{}{}
+		for _, e := range s.events[id] {
+			res = append(res, e)
+		}
+		return res
+	})
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("event with topic %s not found", id)
+	}
+
+	return decodeCandidates(candidates, func(c interface{}) (*Decoded, error) {
+		return decodeLog(c.(*Event), log)
+	})
+}
+
+// DecodeRevert decodes the revert data of a call to the contract into the error name
+// and arguments. The Error(string) and Panic(uint256) errors are always available.
+func (r *Registry) DecodeRevert(addr ethgo.Address, data []byte) (*Decoded, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("revert data too short to have an error selector")
+	}
+
+	var id [4]byte
+	copy(id[:], data[:4])
+
+	candidates := r.candidates(addr, func(s *selectors) []interface{} {
+		res := []interface{}{}
+		for _, e := range s.errors[id] {
+			res = append(res, e)
+		}
+		return res
+	})
+	if len(candidates) == 0 {
+		for _, e := range builtinErrors {
+			if bytes.Equal(e.ID(), id[:]) {
+				candidates = append(candidates, e)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("error with selector 0x%s not found", hex.EncodeToString(id[:]))
+	}
+
+	return decodeCandidates(candidates, func(c interface{}) (*Decoded, error) {
+		e := c.(*Error)
+		return decodeArgs(e.Name, e.Sig(), e.Inputs, data[4:])
+	})
+}
+
+// candidates returns the objects with the selector from the abi of the
+// contract or from the global selectors if the contract has none
+func (r *Registry) candidates(addr ethgo.Address, find func(s *selectors) []interface{}) []interface{} {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	if s, ok := r.contracts[addr]; ok {
+		if res := find(s); len(res) != 0 {
+			return res
+		}
+	}
+	return find(r.global)
+}
+
+// decodeCandidates returns the first candidate that decodes the data. The other
+// candidates that also decode it are returned as alternatives.
+func decodeCandidates(candidates []interface{}, decode func(c interface{}) (*Decoded, error)) (*Decoded, error) {
+	var (
+		res     *Decoded
+		lastErr error
+	)
+	for _, c := range candidates {
+		decoded, err := decode(c)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if res == nil {
+			res = decoded
+		} else {
+			res.Alternatives = append(res.Alternatives, decoded.Signature)
+		}
+	}
+	if res == nil {
+		return nil, lastErr
+	}
+	return res, nil
+}
+
+func decodeArgs(name, sig string, t *Type, data []byte) (*Decoded, error) {
+	values := map[string]interface{}{}
+	if len(t.tuple) == 0 {
+		// there is nothing to decode
+		if len(data) != 0 {
+			return nil, fmt.Errorf("failed to decode %s: expected no arguments but found %d bytes", sig, len(data))
+		}
+	} else {
+		// the strict mode discards the candidates with the
+		// same selector that decode the data by chance
+		val, err := Decode(t, data, WithStrict())
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %v", sig, err)
+		}
+		values = val.(map[string]interface{})
+	}
+
+	res := &Decoded{
+		Name:      name,
+		Signature: sig,
+		Args:      make([]*DecodedArg, len(t.tuple)),
+	}
+	for indx, elem := range t.tuple {
+		argName := tupleElemName(elem, indx)
+		res.Args[indx] = &DecodedArg{
+			Name:  argName,
+			Type:  elem.Elem.String(),
+			Value: jsonValue(elem.Elem, values[argName]),
+		}
+	}
+	return res, nil
+}
+
+func decodeLog(e *Event, log *ethgo.Log) (*Decoded, error) {
+	var indexed, nonIndexed []*TupleElem
+	for _, elem := range e.Inputs.tuple {
+		if elem.Indexed {
+			indexed = append(indexed, elem)
+		} else {
+			nonIndexed = append(nonIndexed, elem)
+		}
+	}
+	if len(indexed) != len(log.Topics)-1 {
+		return nil, fmt.Errorf("expected %d topics for %s but found %d", len(indexed)+1, indexedSignature(e), len(log.Topics))
+	}
+
+	values := map[string]interface{}{}
+	if len(nonIndexed) != 0 {
+		val, err := Decode(&Type{kind: KindTuple, tuple: nonIndexed}, log.Data, WithStrict())
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s: %v", e.Sig(), err)
+		}
+		values = val.(map[string]interface{})
+	}
+
+	res := &Decoded{
+		Name:      e.Name,
+		Signature: e.Sig(),
+		Args:      make([]*DecodedArg, len(e.Inputs.tuple)),
+	}
+	topics, pos := log.Topics[1:], 0
+	for indx, elem := range e.Inputs.tuple {
+		var value interface{}
+		if elem.Indexed {
+			topic := topics[0]
+			topics = topics[1:]
+
+			if elem.Elem.isDynamicType() || elem.Elem.kind == KindTuple || elem.Elem.kind == KindArray {
+				// only the hash of the value is available in the topic
+				value = topic.String()
+			} else {
+				val, err := ParseTopic(elem.Elem, topic)
+				if err != nil {
+					return nil, fmt.Errorf("failed to decode topic of %s: %v", e.Sig(), err)
+				}
+				value = jsonValue(elem.Elem, val)
+			}
+		} else {
+			// the unnamed arguments are named after their position in the data
+			value = jsonValue(elem.Elem, values[tupleElemName(elem, pos)])
+			pos++
+		}
+		res.Args[indx] = &DecodedArg{
+			Name:  tupleElemName(elem, indx),
+			Type:  elem.Elem.String(),
+			Value: value,
+		}
+	}
+	return res, nil
+}
+
+func tupleElemName(elem *TupleElem, indx int) string {
+	if elem.Name == "" {
+		return strconv.Itoa(indx)
+	}
+	return elem.Name
+}
+
+// jsonValue converts a decoded value to json friendly types
+func jsonValue(t *Type, val interface{}) interface{} {
+	if val == nil {
+		return nil
+	}
+
+	switch t.kind {
+	case KindAddress:
+		if addr, ok := val.(ethgo.Address); ok {
+			return addr.String()
+		}
+
+	case KindBytes:
+		if buf, ok := val.([]byte); ok {
+			return "0x" + hex.EncodeToString(buf)
+		}
+
+	case KindFixedBytes, KindFunction:
+		v := reflect.ValueOf(val)
+		if v.Kind() == reflect.Array {
+			buf := make([]byte, v.Len())
+			reflect.Copy(reflect.ValueOf(buf), v)
+			return "0x" + hex.EncodeToString(buf)
+		}
+
+	case KindInt, KindUInt:
+		switch num := val.(type) {
+		case *big.Int:
+			return num.String()
+		case uint64:
+			return strconv.FormatUint(num, 10)
+		case int64:
+			return strconv.FormatInt(num, 10)
+		}
+
+	case KindFixedPoint:
+		if d, ok := val.(*ethgo.Decimal); ok {
+			return d.String()
+		}
+
+	case KindTuple:
+		if values, ok := val.(map[string]interface{}); ok {
+			res := map[string]interface{}{}
+			for indx, elem := range t.tuple {
+				name := tupleElemName(elem, indx)
+				res[name] = jsonValue(elem.Elem, values[name])
+			}
+			return res
+		}
+
+	case KindSlice, KindArray:
+		v := reflect.ValueOf(val)
+		if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+			res := make([]interface{}, v.Len())
+			for i := 0; i < v.Len(); i++ {
+				res[i] = jsonValue(t.elem, v.Index(i).Interface())
+			}
+			return res
+		}
+	}
+	return val
+}
+
+// indexedSignature returns the signature of the event with the indexed arguments
+func indexedSignature(e *Event) string {
+	types := make([]string, len(e.Inputs.tuple))
+	for i, elem := range e.Inputs.tuple {
+		types[i] = strings.Replace(elem.Elem.String(), "tuple", "", -1)
+		if elem.Indexed {
+			types[i] += " indexed"
+		}
+	}
+	return fmt.Sprintf("%v(%v)", e.Name, strings.Join(types, ","))
+}
+
+// selectors indexes the methods, events and errors by selector
+type selectors struct {
+	methods map[[4]byte][]*Method
+	events  map[ethgo.Hash][]*Event
+	errors  map[[4]byte][]*Error
+}
+
+func newSelectors() *selectors {
+	return &selectors{
+		methods: map[[4]byte][]*Method{},
+		events:  map[ethgo.Hash][]*Event{},
+		errors:  map[[4]byte][]*Error{},
+	}
+}
+
+func (s *selectors) add(a *ABI) {
+	for _, m := range a.Methods {
+		var id [4]byte
+		copy(id[:], m.ID())
+
+		if !containsSig(len(s.methods[id]), func(i int) string { return s.methods[id][i].Sig() }, m.Sig()) {
+			s.methods[id] = append(s.methods[id], m)
+		}
+	}
+	for _, e := range a.Events {
+		if e.Anonymous {
+			continue
+		}
+		id := e.ID()
+		if !containsSig(len(s.events[id]), func(i int) string { return indexedSignature(s.events[id][i]) }, indexedSignature(e)) {
+			s.events[id] = append(s.events[id], e)
+		}
+	}
+	for _, e := range a.Errors {
+		var id [4]byte
+		copy(id[:], e.ID())
+
+		if !containsSig(len(s.errors[id]), func(i int) string { return s.errors[id][i].Sig() }, e.Sig()) {
+			s.errors[id] = append(s.errors[id], e)
+		}
+	}
+}
+
+func containsSig(n int, sig func(i int) string, target string) bool {
+	for i := 0; i < n; i++ {
+		if sig(i) == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,260 @@
+package abi
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	erc20Abi, _ = NewABIFromList([]string{
+		"function transfer(address to, uint256 amount) returns (bool)",
+		"event Transfer(address indexed from, address indexed to, uint256 value)",
+		"function totalSupply() returns (uint256)",
+		"error InsufficientBalance(uint256 available, uint256 required)",
+		"error Paused()",
+	})
+	erc721Abi, _ = NewABIFromList([]string{
+		"function safeTransferFrom(address from, address to, uint256 tokenId, bytes data)",
+		"event Transfer(address indexed from, address indexed to, uint256 indexed tokenId)",
+	})
+)
+
+func TestRegistry_DecodeInput(t *testing.T) {
+	r := NewRegistry()
+	r.Register(erc20Abi, erc721Abi)
+
+	to := ethgo.HexToAddress("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed")
+
+	input, err := erc20Abi.GetMethod("transfer").Encode(map[string]interface{}{
+		"to":     to,
+		"amount": new(big.Int).Lsh(big.NewInt(1), 100),
+	})
+	require.NoError(t, err)
+
+	res, err := r.DecodeInput(ethgo.Address{0x1}, input)
+	require.NoError(t, err)
+	require.Equal(t, "transfer", res.Name)
+	require.Equal(t, "transfer(address,uint256)", res.Signature)
+
+	val, ok := res.Arg("to")
+	require.True(t, ok)
+	require.Equal(t, "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", val)
+
+	val, _ = res.Arg("amount")
+	require.Equal(t, "1267650600228229401496703205376", val)
+
+	input, err = erc721Abi.GetMethod("safeTransferFrom").Encode(map[string]interface{}{
+		"from":    ethgo.Address{},
+		"to":      to,
+		"tokenId": big.NewInt(1),
+		"data":    []byte{0x1, 0x2},
+	})
+	require.NoError(t, err)
+
+	res, err = r.DecodeInput(ethgo.Address{0x1}, input)
+	require.NoError(t, err)
+
+	data, err := json.Marshal(res.Args)
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+		{"name": "from", "type": "address", "value": "0x0000000000000000000000000000000000000000"},
+		{"name": "to", "type": "address", "value": "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed"},
+		{"name": "tokenId", "type": "uint256", "value": "1"},
+		{"name": "data", "type": "bytes", "value": "0x0102"}
+	]`, string(data))
+
+	// unknown selector
+	_, err = r.DecodeInput(ethgo.Address{0x1}, []byte{0x1, 0x2, 0x3, 0x4})
+	require.Error(t, err)
+
+	// invalid arguments
+	_, err = r.DecodeInput(ethgo.Address{0x1}, input[:40])
+	require.Error(t, err)
+
+	// method without arguments
+	method := erc20Abi.GetMethod("totalSupply")
+
+	res, err = r.DecodeInput(ethgo.Address{0x1}, method.ID())
+	require.NoError(t, err)
+	require.Equal(t, "totalSupply()", res.Signature)
+	require.Empty(t, res.Args)
+
+	_, err = r.DecodeInput(ethgo.Address{0x1}, append(method.ID(), make([]byte, 32)...))
+	require.Error(t, err)
+}
+
+func TestRegistry_Contract(t *testing.T) {
+	r := NewRegistry()
+	r.Register(erc20Abi)
+
+	addr := ethgo.Address{0x1}
+	r.RegisterContract(addr, MustNewABI(`[{"type": "function", "name": "transfer", "inputs": [{"name": "dst", "type": "address"}, {"name": "wad", "type": "uint256"}]}]`))
+
+	input, err := erc20Abi.GetMethod("transfer").Encode([]interface{}{ethgo.Address{0x2}, big.NewInt(1)})
+	require.NoError(t, err)
+
+	// the abi of the contract is used first
+	res, err := r.DecodeInput(addr, input)
+	require.NoError(t, err)
+	require.Equal(t, "dst", res.Args[0].Name)
+
+	// and the global selectors for the rest of contracts
+	res, err = r.DecodeInput(ethgo.Address{0x2}, input)
+	require.NoError(t, err)
+	require.Equal(t, "to", res.Args[0].Name)
+}
+
+func TestRegistry_DecodeLog(t *testing.T) {
+	r := NewRegistry()
+	r.Register(erc20Abi, erc721Abi)
+
+	from, to := ethgo.Address{0x1}, ethgo.Address{0x2}
+
+	value := make([]byte, 32)
+	value[31] = 0xa
+
+	// erc20 transfer with the value in the data
+	log := &ethgo.Log{
+		Topics: []ethgo.Hash{
+			erc20Abi.Events["Transfer"].ID(),
+			ethgo.BytesToHash(from.Bytes()),
+			ethgo.BytesToHash(to.Bytes()),
+		},
+		Data: value,
+	}
+	res, err := r.DecodeLog(log)
+	require.NoError(t, err)
+	require.Equal(t, "Transfer", res.Name)
+	require.Empty(t, res.Alternatives)
+
+	val, _ := res.Arg("value")
+	require.Equal(t, "10", val)
+	val, _ = res.Arg("from")
+	require.Equal(t, from.String(), val)
+
+	// erc721 transfer with the token id in the topics
+	log.Topics = append(log.Topics, ethgo.BytesToHash(value))
+	log.Data = nil
+
+	res, err = r.DecodeLog(log)
+	require.NoError(t, err)
+	val, _ = res.Arg("tokenId")
+	require.Equal(t, "10", val)
+
+	// indexed dynamic values are returned as the hash
+	evnt := MustNewEvent("event Named(string indexed name, uint8 kind, bytes4 id)")
+	r.Register(&ABI{Events: map[string]*Event{"Named": evnt}})
+
+	data, err := Encode([]interface{}{uint8(1), [4]byte{0x1}}, MustNewType("tuple(uint8, bytes4)"))
+	require.NoError(t, err)
+
+	res, err = r.DecodeLog(&ethgo.Log{
+		Topics: []ethgo.Hash{evnt.ID(), {0x3}},
+		Data:   data,
+	})
+	require.NoError(t, err)
+
+	raw, err := json.Marshal(res.Args)
+	require.NoError(t, err)
+	require.JSONEq(t, `[
+		{"name": "name", "type": "string", "value": "0x0300000000000000000000000000000000000000000000000000000000000000"},
+		{"name": "kind", "type": "uint8", "value": 1},
+		{"name": "id", "type": "bytes4", "value": "0x01000000"}
+	]`, string(raw))
+
+	_, err = r.DecodeLog(&ethgo.Log{Topics: []ethgo.Hash{{0x1}}})
+	require.Error(t, err)
+}
+
+func TestRegistry_DecodeRevert(t *testing.T) {
+	r := NewRegistry()
+
+	addr := ethgo.Address{0x1}
+	r.RegisterContract(addr, erc20Abi)
+
+	errObj := erc20Abi.Errors["InsufficientBalance"]
+	data, err := Encode([]interface{}{big.NewInt(1), big.NewInt(2)}, errObj.Inputs)
+	require.NoError(t, err)
+
+	res, err := r.DecodeRevert(addr, append(errObj.ID(), data...))
+	require.NoError(t, err)
+	require.Equal(t, "InsufficientBalance", res.Name)
+	require.Equal(t, "2", res.Args[1].Value)
+
+	// error without arguments
+	res, err = r.DecodeRevert(addr, erc20Abi.Errors["Paused"].ID())
+	require.NoError(t, err)
+	require.Equal(t, "Paused()", res.Signature)
+	require.Empty(t, res.Args)
+
+	// not available for other contracts
+	_, err = r.DecodeRevert(ethgo.Address{0x2}, append(errObj.ID(), data...))
+	require.Error(t, err)
+
+	// builtin errors
+	data, err = Encode([]interface{}{"revert reason"}, MustNewType("tuple(string)"))
+	require.NoError(t, err)
+
+	res, err = r.DecodeRevert(ethgo.Address{0x2}, append(revertId, data...))
+	require.NoError(t, err)
+	require.Equal(t, "Error", res.Name)
+	require.Equal(t, "revert reason", res.Args[0].Value)
+
+	data, err = Encode([]interface{}{big.NewInt(0x11)}, MustNewType("tuple(uint256)"))
+	require.NoError(t, err)
+
+	res, err = r.DecodeRevert(ethgo.Address{0x2}, append([]byte{0x4e, 0x48, 0x7b, 0x71}, data...))
+	require.NoError(t, err)
+	require.Equal(t, "Panic(uint256)", res.Signature)
+	require.Equal(t, "17", res.Args[0].Value)
+}
+
+func TestRegistry_Collisions(t *testing.T) {
+	r := NewRegistry()
+	r.Register(erc20Abi, erc721Abi)
+
+	// the same abi twice is not a collision
+	r.Register(erc20Abi)
+
+	// transfer(address,uint256) and many_msg_babbage(bytes1) share the selector 0xa9059cbb
+	r.Register(MustNewABI(`[{"type": "function", "name": "many_msg_babbage", "inputs": [{"name": "a", "type": "bytes1"}]}]`))
+
+	collisions := r.Collisions()
+	require.Len(t, collisions, 2)
+
+	require.Equal(t, &Collision{
+		Kind:     "event",
+		Selector: erc20Abi.Events["Transfer"].ID().String(),
+		Signatures: []string{
+			"Transfer(address indexed,address indexed,uint256 indexed)",
+			"Transfer(address indexed,address indexed,uint256)",
+		},
+	}, collisions[0])
+
+	require.Equal(t, &Collision{
+		Kind:       "function",
+		Selector:   "0xa9059cbb",
+		Signatures: []string{"many_msg_babbage(bytes1)", "transfer(address,uint256)"},
+	}, collisions[1])
+
+	// the input is decoded with the candidates that accept it
+	input, err := erc20Abi.GetMethod("transfer").Encode([]interface{}{ethgo.Address{0x2}, big.NewInt(1)})
+	require.NoError(t, err)
+
+	res, err := r.DecodeInput(ethgo.Address{}, input)
+	require.NoError(t, err)
+	require.Equal(t, "transfer", res.Name)
+	require.Empty(t, res.Alternatives)
+
+	input, err = MustNewMethod("function many_msg_babbage(bytes1)").Encode([]interface{}{[1]byte{0x1}})
+	require.NoError(t, err)
+
+	res, err = r.DecodeInput(ethgo.Address{}, input)
+	require.NoError(t, err)
+	require.Equal(t, "many_msg_babbage", res.Name)
+	require.Empty(t, res.Alternatives)
+}
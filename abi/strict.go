@@ -0,0 +1,233 @@
+package abi
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Errors returned by the strict decoder wrapped in a DecodeError
+var (
+	// ErrDirtyPadding is returned when the padding bytes of a value are not zero
+	// (or not sign-extended for signed numbers)
+	ErrDirtyPadding = fmt.Errorf("non-canonical padding")
+
+	// ErrInvalidBool is returned when a boolean is not 0 or 1
+	ErrInvalidBool = fmt.Errorf("invalid boolean")
+
+	// ErrOutOfBounds is returned when an offset or a length points outside of the input
+	ErrOutOfBounds = fmt.Errorf("out of bounds")
+
+	// ErrInvalidOffset is returned when the offset of a dynamic value does not point
+	// right after the previous value (i.e. overlapping or unordered offsets)
+	ErrInvalidOffset = fmt.Errorf("non-canonical offset")
+
+	// ErrTrailingBytes is returned when the input has bytes after the encoded value
+	ErrTrailingBytes = fmt.Errorf("trailing bytes")
+)
+
+// DecodeError is a violation of the canonical encoding found by the strict decoder
+type DecodeError struct {
+	// Offset is the position in the input of the invalid data
+	Offset int
+
+	// Type is the abi type being decoded
+	Type *Type
+
+	Err error
+}
+
+func (d *DecodeError) Error() string {
+	return fmt.Sprintf("%v for %s at offset %d", d.Err, d.Type, d.Offset)
+}
+
+func (d *DecodeError) Unwrap() error {
+	return d.Err
+}
+
+// DecodeOption is an option for the decoding functions
+type DecodeOption func(*decodeOpts)
+
+type decodeOpts struct {
+	strict bool
+}
+
+// WithStrict only accepts inputs with the canonical encoding produced by Encode (and Solidity):
+// zero (or sign-extended) padding, booleans as 0 or 1, offsets of dynamic values in order
+// and without gaps or overlaps and no trailing bytes. The violations are returned as
+// a *DecodeError with the offset in the input.
+func WithStrict() DecodeOption {
+	return func(o *decodeOpts) {
+		o.strict = true
+	}
+}
+
+func newDecodeOpts(opts []DecodeOption) *decodeOpts {
+	o := &decodeOpts{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// validateCanonical checks that the input is the canonical encoding of the type
+func validateCanonical(t *Type, input []byte) error {
+	consumed, err := validateType(t, input, 0)
+	if err != nil {
+		return err
+	}
+	if consumed != len(input) {
+		return &DecodeError{Offset: consumed, Type: t, Err: ErrTrailingBytes}
+	}
+	return nil
+}
+
+// validateType validates the encoding of the type at the start of data and returns
+// the number of bytes it takes. Base is the position of data in the input.
+func validateType(t *Type, data []byte, base int) (int, error) {
+	decodeErr := func(offset int, err error) error {
+		return &DecodeError{Offset: base + offset, Type: t, Err: err}
+	}
+
+	if len(data) < storageSlotSize {
+		return 0, decodeErr(len(data), ErrOutOfBounds)
+	}
+
+	switch t.kind {
+	case KindTuple:
+		elems := make([]*Type, len(t.tuple))
+		for i, elem := range t.tuple {
+			elems[i] = elem.Elem
+		}
+		return validateElems(t, elems, data, base)
+
+	case KindArray:
+		return validateArray(t, t.size, data, base)
+
+	case KindSlice:
+		size, ok := readUint64(data)
+		if !ok || size > uint64(len(data)) {
+			return 0, decodeErr(0, ErrOutOfBounds)
+		}
+		consumed, err := validateArray(t, int(size), data[storageSlotSize:], base+storageSlotSize)
+		if err != nil {
+			return 0, err
+		}
+		return storageSlotSize + consumed, nil
+
+	case KindBytes, KindString:
+		length, ok := readUint64(data)
+		if !ok || length > uint64(len(data)-storageSlotSize) {
+			return 0, decodeErr(0, ErrOutOfBounds)
+		}
+		size := (int(length) + 31) / 32 * 32
+		if size > len(data)-storageSlotSize {
+			return 0, decodeErr(len(data), ErrOutOfBounds)
+		}
+		padding := data[storageSlotSize+int(length) : storageSlotSize+size]
+		if !allZeros(padding) {
+			return 0, decodeErr(storageSlotSize+int(length), ErrDirtyPadding)
+		}
+		return storageSlotSize + size, nil
+	}
+
+	// static values in a single word
+	word := data[:storageSlotSize]
+
+	switch t.kind {
+	case KindBool:
+		if !allZeros(word[:31]) || word[31] > 1 {
+			return 0, decodeErr(0, ErrInvalidBool)
+		}
+
+	case KindAddress:
+		if !allZeros(word[:12]) {
+			return 0, decodeErr(0, ErrDirtyPadding)
+		}
+
+	case KindFixedBytes:
+		if !allZeros(word[t.size:]) {
+			return 0, decodeErr(t.size, ErrDirtyPadding)
+		}
+
+	case KindFunction:
+		if !allZeros(word[24:]) {
+			return 0, decodeErr(24, ErrDirtyPadding)
+		}
+
+	case KindUInt, KindInt, KindFixedPoint:
+		signed := t.kind == KindInt || (t.kind == KindFixedPoint && t.signed)
+
+		size := t.size / 8
+		pad := byte(0)
+		if signed && word[32-size]&0x80 != 0 {
+			pad = 0xff
+		}
+		for i := 0; i < 32-size; i++ {
+			if word[i] != pad {
+				return 0, decodeErr(0, ErrDirtyPadding)
+			}
+		}
+	}
+	return storageSlotSize, nil
+}
+
+func validateArray(t *Type, size int, data []byte, base int) (int, error) {
+	// every element takes at least one word
+	if size*storageSlotSize > len(data) {
+		return 0, &DecodeError{Offset: base, Type: t, Err: ErrOutOfBounds}
+	}
+	elems := make([]*Type, size)
+	for i := range elems {
+		elems[i] = t.elem
+	}
+	return validateElems(t, elems, data, base)
+}
+
+// validateElems validates the encoding of a list of values (tuples and arrays). The static
+// values and the offsets of the dynamic values are in the head and the dynamic values
+// follow in the same order.
+func validateElems(t *Type, elems []*Type, data []byte, base int) (int, error) {
+	headSize := 0
+	for _, elem := range elems {
+		headSize += getTypeSize(elem)
+	}
+	if headSize > len(data) {
+		return 0, &DecodeError{Offset: base + len(data), Type: t, Err: ErrOutOfBounds}
+	}
+
+	pos, next := 0, headSize
+	for _, elem := range elems {
+		if !elem.isDynamicType() {
+			consumed, err := validateType(elem, data[pos:], base+pos)
+			if err != nil {
+				return 0, err
+			}
+			pos += consumed
+			continue
+		}
+
+		offset, ok := readUint64(data[pos:])
+		if !ok || offset > uint64(len(data)) {
+			return 0, &DecodeError{Offset: base + pos, Type: elem, Err: ErrOutOfBounds}
+		}
+		if int(offset) != next {
+			return 0, &DecodeError{Offset: base + pos, Type: elem, Err: ErrInvalidOffset}
+		}
+
+		consumed, err := validateType(elem, data[offset:], base+int(offset))
+		if err != nil {
+			return 0, err
+		}
+		next += consumed
+		pos += storageSlotSize
+	}
+	return next, nil
+}
+
+// readUint64 reads a word as an uint64 that fits in an int
+func readUint64(word []byte) (uint64, bool) {
+	if !allZeros(word[:24]) || word[24]&0x80 != 0 {
+		return 0, false
+	}
+	return binary.BigEndian.Uint64(word[24:storageSlotSize]), true
+}
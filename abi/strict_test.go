@@ -0,0 +1,166 @@
+package abi
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeStrict_Canonical(t *testing.T) {
+	cases := []struct {
+		typ string
+		val interface{}
+	}{
+		{"uint8", uint8(255)},
+		{"int16", int16(-2)},
+		{"tuple(address a, bool b, bytes3 c)", map[string]interface{}{"a": ethgo.Address{0x1}, "b": true, "c": [3]byte{0x1}}},
+		{"tuple(string a, bytes b, uint256[] c)", map[string]interface{}{"a": "hello", "b": []byte{}, "c": []*big.Int{big.NewInt(1)}}},
+		{"string[][2]", [2][]string{{"a", "b"}, {}}},
+		{"fixed8x1", "-0.1"},
+	}
+
+	for _, c := range cases {
+		typ := MustNewType(c.typ)
+
+		data, err := Encode(c.val, typ)
+		require.NoError(t, err)
+
+		_, err = Decode(typ, data, WithStrict())
+		require.NoError(t, err, c.typ)
+	}
+
+	data, err := EncodeFrom(typedObjType, newTypedObj())
+	require.NoError(t, err)
+
+	var obj typedObj
+	require.NoError(t, DecodeInto(typedObjType, data, &obj, WithStrict()))
+}
+
+func TestDecodeStrict_Violations(t *testing.T) {
+	word := func(last ...byte) []byte {
+		w := make([]byte, 32)
+		copy(w[32-len(last):], last)
+		return w
+	}
+	concat := func(words ...[]byte) []byte {
+		res := []byte{}
+		for _, w := range words {
+			res = append(res, w...)
+		}
+		return res
+	}
+	dirty := func(w []byte, indx int) []byte {
+		w[indx] = 0x1
+		return w
+	}
+
+	cases := []struct {
+		typ    string
+		input  []byte
+		err    error
+		offset int
+	}{
+		{
+			"tuple(uint256,address)",
+			concat(word(1), dirty(word(1), 0)),
+			ErrDirtyPadding,
+			32,
+		},
+		{
+			"uint8",
+			dirty(word(1), 30),
+			ErrDirtyPadding,
+			0,
+		},
+		{
+			// not sign-extended
+			"int8",
+			word(0xff),
+			ErrDirtyPadding,
+			0,
+		},
+		{
+			"bool",
+			word(2),
+			ErrInvalidBool,
+			0,
+		},
+		{
+			"bytes2",
+			dirty(word(), 2),
+			ErrDirtyPadding,
+			2,
+		},
+		{
+			// dirty padding after the data of bytes
+			"tuple(bytes)",
+			concat(word(0x20), word(1), dirty(word(), 31)),
+			ErrDirtyPadding,
+			65,
+		},
+		{
+			"uint256",
+			concat(word(1), word(2)),
+			ErrTrailingBytes,
+			32,
+		},
+		{
+			// the offset skips a word
+			"tuple(bytes)",
+			concat(word(0x40), word(), word()),
+			ErrInvalidOffset,
+			0,
+		},
+		{
+			// both offsets point to the same data
+			"tuple(string,string)",
+			concat(word(0x40), word(0x40), word()),
+			ErrInvalidOffset,
+			32,
+		},
+		{
+			"tuple(bytes)",
+			concat(word(0x60)),
+			ErrOutOfBounds,
+			0,
+		},
+		{
+			// length larger than the input
+			"bytes",
+			concat(word(0x40), word()),
+			ErrOutOfBounds,
+			0,
+		},
+		{
+			"uint8[]",
+			concat(word(2), word(1)),
+			ErrOutOfBounds,
+			32,
+		},
+	}
+
+	for _, c := range cases {
+		typ := MustNewType(c.typ)
+
+		_, err := Decode(typ, c.input, WithStrict())
+		require.Error(t, err, c.typ)
+		require.True(t, errors.Is(err, c.err), "%s: %v", c.typ, err)
+
+		var decodeErr *DecodeError
+		require.True(t, errors.As(err, &decodeErr))
+		require.Equal(t, c.offset, decodeErr.Offset, c.typ)
+	}
+}
+
+func TestDecodeStrict_Lenient(t *testing.T) {
+	// the default decoder accepts the non-canonical inputs
+	input := make([]byte, 64)
+	input[0] = 0x1
+
+	val, err := Decode(MustNewType("address"), input)
+	require.NoError(t, err)
+	require.Equal(t, ethgo.Address{}, val)
+}
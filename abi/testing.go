@@ -99,6 +99,26 @@ func generateNumber(t *Type) interface{} {
 	return num
 }
 
+// randomFixedPointType returns a fixed point type. They are not part of the
+// random types since solc does not implement fixed point numbers yet.
+func randomFixedPointType() string {
+	t := "fixed"
+	if randomInt(0, 2) == 1 {
+		t = "ufixed"
+	}
+	return fmt.Sprintf("%s%dx%d", t, randomNumberBits(), randomInt(1, 80))
+}
+
+func generateFixedPoint(t *Type) interface{} {
+	b := make([]byte, t.size/8)
+	if t.signed {
+		rand.Read(b[1:])
+	} else {
+		rand.Read(b)
+	}
+	return ethgo.NewDecimal(new(big.Int).SetBytes(b), t.decimals)
+}
+
 func generateRandomType(t *Type) interface{} {
 
 	switch t.kind {
@@ -107,6 +127,9 @@ func generateRandomType(t *Type) interface{} {
 	case KindUInt:
 		return generateNumber(t)
 
+	case KindFixedPoint:
+		return generateFixedPoint(t)
+
 	case KindBool:
 		if randomInt(0, 1) == 1 {
 			return true
@@ -87,6 +87,9 @@ func ParseTopic(t *Type, topic ethgo.Hash) (interface{}, error) {
 	case KindInt, KindUInt:
 		return readInteger(t, topic[:]), nil
 
+	case KindFixedPoint:
+		return readFixedPoint(t, topic[:]), nil
+
 	case KindAddress:
 		return readAddr(topic[:])
 
@@ -114,6 +117,9 @@ func encodeTopic(t *Type, val reflect.Value) (ethgo.Hash, error) {
 	case KindAddress:
 		return encodeTopicAddress(val)
 
+	case KindFixedPoint:
+		return encodeTopicFixedPoint(t, val)
+
 	}
 	return ethgo.Hash{}, fmt.Errorf("not found")
 }
@@ -144,6 +150,16 @@ func encodeTopicNum(val reflect.Value) (res ethgo.Hash, err error) {
 	return
 }
 
+func encodeTopicFixedPoint(t *Type, val reflect.Value) (res ethgo.Hash, err error) {
+	var b []byte
+	b, err = encodeFixedPoint(val, t)
+	if err != nil {
+		return
+	}
+	copy(res[:], b[:])
+	return
+}
+
 func encodeTopicBool(v reflect.Value) (res ethgo.Hash, err error) {
 	if v.Kind() != reflect.Bool {
 		return ethgo.Hash{}, encodeErr(v, "bool")
@@ -37,6 +37,10 @@ func TestTopicEncoding(t *testing.T) {
 			Type: "address",
 			Val:  ethgo.Address{0x1},
 		},
+		{
+			Type: "fixed128x2",
+			Val:  ethgo.NewDecimal(big.NewInt(-1050), 2),
+		},
 	}
 
 	for _, c := range cases {
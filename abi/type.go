@@ -29,6 +29,7 @@ var (
 	functionT     = reflect.ArrayOf(24, reflect.TypeOf(byte(0)))
 	tupleT        = reflect.TypeOf(map[string]interface{}{})
 	bigIntT       = reflect.TypeOf(new(big.Int))
+	decimalT      = reflect.TypeOf(new(ethgo.Decimal))
 )
 
 // Kind represents the kind of abi type
@@ -106,6 +107,10 @@ type Type struct {
 	tuple []*TupleElem
 	t     reflect.Type
 	itype string
+
+	// decimals and signed are only set for fixed point types
+	decimals int
+	signed   bool
 }
 
 func NewTupleType(inputs []*TupleElem) *Type {
@@ -138,13 +143,13 @@ func (t *Type) ParseLog(log *ethgo.Log) (map[string]interface{}, error) {
 }
 
 // Decode decodes an object using this type
-func (t *Type) Decode(input []byte) (interface{}, error) {
-	return Decode(t, input)
+func (t *Type) Decode(input []byte, opts ...DecodeOption) (interface{}, error) {
+	return Decode(t, input, opts...)
 }
 
 // DecodeStruct decodes an object using this type to the out param
-func (t *Type) DecodeStruct(input []byte, out interface{}) error {
-	return DecodeStruct(t, input, out)
+func (t *Type) DecodeStruct(input []byte, out interface{}, opts ...DecodeOption) error {
+	return DecodeStruct(t, input, out, opts...)
 }
 
 // InternalType returns the internal type
@@ -210,6 +215,12 @@ func (t *Type) Format(includeArgs bool) string {
 	case KindInt:
 		return fmt.Sprintf("int%d", t.size)
 
+	case KindFixedPoint:
+		if t.signed {
+			return fmt.Sprintf("fixed%dx%d", t.size, t.decimals)
+		}
+		return fmt.Sprintf("ufixed%dx%d", t.size, t.decimals)
+
 	default:
 		panic(fmt.Errorf("BUG: abi type not found %s", t.kind.String()))
 	}
@@ -225,6 +236,11 @@ func (t *Type) Size() int {
 	return t.size
 }
 
+// Decimals returns the number of decimals of a fixed point type
+func (t *Type) Decimals() int {
+	return t.decimals
+}
+
 // TupleElems returns the elems of the tuple
 func (t *Type) TupleElems() []*TupleElem {
 	return t.tuple
@@ -372,6 +388,8 @@ func getTypeSize(t *Type) int {
 
 var typeRegexp = regexp.MustCompile("^([[:alpha:]]+)([[:digit:]]*)$")
 
+var fixedPointRegexp = regexp.MustCompile("^(u?fixed)(?:([[:digit:]]+)x([[:digit:]]+))?$")
+
 func expectedToken(t tokenType) error {
 	return fmt.Errorf("expected token %s", t.String())
 }
@@ -487,6 +505,10 @@ func readType(l *lexer) (*Type, error) {
 }
 
 func decodeSimpleType(str string) (*Type, error) {
+	if match := fixedPointRegexp.FindStringSubmatch(str); len(match) != 0 {
+		return decodeFixedPointType(match[1], match[2], match[3])
+	}
+
 	match := typeRegexp.FindStringSubmatch(str)
 	if len(match) == 0 {
 		return nil, fmt.Errorf("type format is incorrect. Expected 'type''bytes' but found '%s'", str)
@@ -583,6 +605,27 @@ func decodeSimpleType(str string) (*Type, error) {
 	}
 }
 
+func decodeFixedPointType(t, bitsStr, decimalsStr string) (*Type, error) {
+	// fixed and ufixed are aliases for fixed128x18 and ufixed128x18
+	bits, decimals := 128, 18
+	if bitsStr != "" {
+		var err error
+		if bits, err = strconv.Atoi(bitsStr); err != nil {
+			return nil, fmt.Errorf("failed to parse bits '%s': %v", bitsStr, err)
+		}
+		if decimals, err = strconv.Atoi(decimalsStr); err != nil {
+			return nil, fmt.Errorf("failed to parse decimals '%s': %v", decimalsStr, err)
+		}
+	}
+	if bits < 8 || bits > 256 || bits%8 != 0 {
+		return nil, fmt.Errorf("number of bits of %s has to be a multiple of 8 between 8 and 256 but found %d", t, bits)
+	}
+	if decimals < 1 || decimals > 80 {
+		return nil, fmt.Errorf("number of decimals of %s has to be between 1 and 80 but found %d", t, decimals)
+	}
+	return &Type{kind: KindFixedPoint, size: bits, decimals: decimals, signed: t == "fixed", t: decimalT}, nil
+}
+
 type tokenType int
 
 const (
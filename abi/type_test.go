@@ -305,6 +305,42 @@ func TestType(t *testing.T) {
 			},
 			r: "tuple(tuple(int32))",
 		},
+		{
+			s: "fixed128x18",
+			a: simpleType("fixed128x18"),
+			t: &Type{kind: KindFixedPoint, size: 128, decimals: 18, signed: true, t: decimalT},
+		},
+		{
+			s: "ufixed",
+			a: simpleType("ufixed"),
+			t: &Type{kind: KindFixedPoint, size: 128, decimals: 18, t: decimalT},
+			r: "ufixed128x18",
+		},
+		{
+			s: "ufixed256x80[]",
+			a: simpleType("ufixed256x80[]"),
+			t: &Type{kind: KindSlice, t: reflect.SliceOf(decimalT), elem: &Type{kind: KindFixedPoint, size: 256, decimals: 80, t: decimalT}},
+		},
+		{
+			s:   "fixed7x18",
+			err: true,
+		},
+		{
+			s:   "fixed264x18",
+			err: true,
+		},
+		{
+			s:   "ufixed128x81",
+			err: true,
+		},
+		{
+			s:   "fixed128x0",
+			err: true,
+		},
+		{
+			s:   "fixed128",
+			err: true,
+		},
 		{
 			s:   "int[[",
 			err: true,
@@ -0,0 +1,604 @@
+package abi
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DecodeInto decodes the input with the type into out. Unlike DecodeStruct, the
+// decoding does not build an intermediate map[string]interface{} tree. Instead,
+// a plan is compiled once for each pair of abi type and Go type and the values
+// are set directly with reflection.
+//
+// The tuples are decoded into structs, the fields are matched with the `abi` tag
+// or (case insensitive) with the name of the field. Numbers are decoded into any
+// Go integer type (if the value fits), *big.Int or big.Int.
+func DecodeInto[T any](t *Type, input []byte, out *T, opts ...DecodeOption) error {
+	if len(input) == 0 {
+		return fmt.Errorf("empty input")
+	}
+	if out == nil {
+		return fmt.Errorf("nil output")
+	}
+	if newDecodeOpts(opts).strict {
+		if err := validateCanonical(t, input); err != nil {
+			return err
+		}
+	}
+	plan, err := getTypedPlan(t, reflect.TypeOf(out).Elem())
+	if err != nil {
+		return err
+	}
+	_, err = plan.decode(input, reflect.ValueOf(out).Elem())
+	return err
+}
+
+// EncodeFrom encodes the value with the type using a plan compiled once for
+// each pair of abi type and Go type. The values accepted are the same
+// that DecodeInto decodes into.
+func EncodeFrom[T any](t *Type, v T) ([]byte, error) {
+	plan, err := getTypedPlan(t, reflect.TypeOf(&v).Elem())
+	if err != nil {
+		return nil, err
+	}
+	return plan.encode(reflect.ValueOf(&v).Elem())
+}
+
+type (
+	typedDecoder func(input []byte, dst reflect.Value) ([]byte, error)
+	typedEncoder func(v reflect.Value) ([]byte, error)
+)
+
+type typedPlan struct {
+	decode typedDecoder
+	encode typedEncoder
+}
+
+// typedPlanKey is the canonical abi type (with the names of the tuple elements
+// used to match the fields) and the Go type, so the types built on each call
+// share the plans and the cache is bounded by the number of distinct types
+type typedPlanKey struct {
+	t  string
+	rt reflect.Type
+}
+
+var typedPlans sync.Map
+
+func getTypedPlan(t *Type, rt reflect.Type) (*typedPlan, error) {
+	key := typedPlanKey{t: t.Format(true), rt: rt}
+	if plan, ok := typedPlans.Load(key); ok {
+		return plan.(*typedPlan), nil
+	}
+	plan, err := compileTypedPlan(t, rt)
+	if err != nil {
+		return nil, err
+	}
+	typedPlans.Store(key, plan)
+	return plan, nil
+}
+
+func compileTypedPlan(t *Type, rt reflect.Type) (*typedPlan, error) {
+	// generic values use the untyped encoder and decoder
+	if rt.Kind() == reflect.Interface {
+		return &typedPlan{
+			decode: func(input []byte, dst reflect.Value) ([]byte, error) {
+				val, tail, err := decode(t, input)
+				if err != nil {
+					return nil, err
+				}
+				dst.Set(reflect.ValueOf(val))
+				return tail, nil
+			},
+			encode: func(v reflect.Value) ([]byte, error) {
+				return encode(v, t)
+			},
+		}, nil
+	}
+
+	// pointers are followed except for the types decoded as pointers
+	if rt.Kind() == reflect.Ptr && rt != bigIntT && rt != decimalT {
+		elem, err := compileTypedPlan(t, rt.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &typedPlan{
+			decode: func(input []byte, dst reflect.Value) ([]byte, error) {
+				if dst.IsNil() {
+					dst.Set(reflect.New(rt.Elem()))
+				}
+				return elem.decode(input, dst.Elem())
+			},
+			encode: func(v reflect.Value) ([]byte, error) {
+				if v.IsNil() {
+					return nil, fmt.Errorf("failed to encode nil %s", rt)
+				}
+				return elem.encode(v.Elem())
+			},
+		}, nil
+	}
+
+	mismatch := func() error {
+		return fmt.Errorf("abi type %s is not compatible with go type %s", t, rt)
+	}
+
+	switch t.kind {
+	case KindTuple:
+		if rt.Kind() != reflect.Struct {
+			return nil, mismatch()
+		}
+		return compileTypedTuple(t, rt)
+
+	case KindSlice, KindArray:
+		if rt.Kind() != reflect.Slice && rt.Kind() != reflect.Array {
+			return nil, mismatch()
+		}
+		if rt.Kind() == reflect.Array && (t.kind != KindArray || rt.Len() != t.size) {
+			return nil, mismatch()
+		}
+		return compileTypedArraySlice(t, rt)
+
+	case KindInt, KindUInt:
+		switch {
+		case rt == bigIntT || rt == bigIntT.Elem():
+		case rt.Kind() >= reflect.Int && rt.Kind() <= reflect.Uint64:
+		default:
+			return nil, mismatch()
+		}
+		return &typedPlan{
+			decode: typedStatic(func(word []byte, dst reflect.Value) error {
+				return decodeTypedNum(t, word, dst)
+			}),
+			encode: encodeTypedNum,
+		}, nil
+
+	case KindBool:
+		if rt.Kind() != reflect.Bool {
+			return nil, mismatch()
+		}
+		return &typedPlan{
+			decode: typedStatic(func(word []byte, dst reflect.Value) error {
+				val, err := decodeBool(word)
+				if err != nil {
+					return err
+				}
+				dst.SetBool(val.(bool))
+				return nil
+			}),
+			encode: encodeBool,
+		}, nil
+
+	case KindAddress, KindFixedBytes, KindFunction:
+		isBytes := rt.Kind() == reflect.Slice && rt.Elem().Kind() == reflect.Uint8
+		isArray := rt.Kind() == reflect.Array && rt.Elem().Kind() == reflect.Uint8 && rt.Len() == t.size
+		if !isBytes && !isArray {
+			return nil, mismatch()
+		}
+		return compileTypedFixedBytes(t, isBytes), nil
+
+	case KindString:
+		if rt.Kind() != reflect.String {
+			return nil, mismatch()
+		}
+		return &typedPlan{
+			decode: typedVariable(func(data []byte, dst reflect.Value) {
+				dst.SetString(string(data))
+			}),
+			encode: func(v reflect.Value) ([]byte, error) {
+				return packBytesSlice([]byte(v.String()), v.Len())
+			},
+		}, nil
+
+	case KindBytes:
+		if rt.Kind() != reflect.Slice || rt.Elem().Kind() != reflect.Uint8 {
+			return nil, mismatch()
+		}
+		return &typedPlan{
+			decode: typedVariable(func(data []byte, dst reflect.Value) {
+				dst.SetBytes(append([]byte{}, data...))
+			}),
+			encode: func(v reflect.Value) ([]byte, error) {
+				return packBytesSlice(v.Bytes(), v.Len())
+			},
+		}, nil
+
+	case KindFixedPoint:
+		if rt != decimalT && rt != decimalT.Elem() {
+			return nil, mismatch()
+		}
+		return &typedPlan{
+			decode: typedStatic(func(word []byte, dst reflect.Value) error {
+				val := readFixedPoint(t, word)
+				if rt == decimalT {
+					dst.Set(reflect.ValueOf(val))
+				} else {
+					dst.Set(reflect.ValueOf(*val))
+				}
+				return nil
+			}),
+			encode: func(v reflect.Value) ([]byte, error) {
+				return encodeFixedPoint(v, t)
+			},
+		}, nil
+
+	default:
+		return nil, fmt.Errorf("typed encoding not available for type '%s'", t.kind)
+	}
+}
+
+// typedStatic decodes a type that takes a single word
+func typedStatic(f func(word []byte, dst reflect.Value) error) typedDecoder {
+	return func(input []byte, dst reflect.Value) ([]byte, error) {
+		if len(input) < storageSlotSize {
+			return nil, fmt.Errorf("incorrect length")
+		}
+		if err := f(input[:storageSlotSize], dst); err != nil {
+			return nil, err
+		}
+		return input[storageSlotSize:], nil
+	}
+}
+
+// typedVariable decodes a type with the length prefix (bytes and string)
+func typedVariable(f func(data []byte, dst reflect.Value)) typedDecoder {
+	return func(input []byte, dst reflect.Value) ([]byte, error) {
+		if len(input) < storageSlotSize {
+			return nil, fmt.Errorf("incorrect length")
+		}
+		length, err := readLength(input)
+		if err != nil {
+			return nil, err
+		}
+		f(input[storageSlotSize:storageSlotSize+length], dst)
+		return input[storageSlotSize:], nil
+	}
+}
+
+func decodeTypedNum(t *Type, word []byte, dst reflect.Value) error {
+	switch kind := dst.Kind(); {
+	case kind >= reflect.Int && kind <= reflect.Int64:
+		if !fitsInt64(word, t.kind == KindInt) {
+			return fmt.Errorf("value does not fit in %s", dst.Type())
+		}
+		val := int64(binary.BigEndian.Uint64(word[24:]))
+		if dst.OverflowInt(val) {
+			return fmt.Errorf("value %d does not fit in %s", val, dst.Type())
+		}
+		dst.SetInt(val)
+
+	case kind >= reflect.Uint && kind <= reflect.Uint64:
+		if !allZeros(word[:24]) || (t.kind == KindInt && word[0]&0x80 != 0) {
+			return fmt.Errorf("value does not fit in %s", dst.Type())
+		}
+		val := binary.BigEndian.Uint64(word[24:])
+		if dst.OverflowUint(val) {
+			return fmt.Errorf("value %d does not fit in %s", val, dst.Type())
+		}
+		dst.SetUint(val)
+
+	case kind == reflect.Ptr:
+		dst.Set(reflect.ValueOf(readBigInt(word, t.kind == KindInt)))
+
+	default:
+		dst.Set(reflect.ValueOf(*readBigInt(word, t.kind == KindInt)))
+	}
+	return nil
+}
+
+// fitsInt64 checks whether the word is a (sign-extended) int64
+func fitsInt64(word []byte, signed bool) bool {
+	pad := byte(0)
+	if signed && word[0]&0x80 != 0 {
+		pad = 0xff
+	}
+	for _, b := range word[:24] {
+		if b != pad {
+			return false
+		}
+	}
+	// the sign of the int64 has to match the padding
+	return (word[24]&0x80 != 0) == (pad == 0xff)
+}
+
+func encodeTypedNum(v reflect.Value) ([]byte, error) {
+	word := make([]byte, storageSlotSize)
+
+	switch kind := v.Kind(); {
+	case kind >= reflect.Int && kind <= reflect.Int64:
+		val := v.Int()
+		if val < 0 {
+			for i := 0; i < 24; i++ {
+				word[i] = 0xff
+			}
+		}
+		binary.BigEndian.PutUint64(word[24:], uint64(val))
+
+	case kind >= reflect.Uint && kind <= reflect.Uint64:
+		binary.BigEndian.PutUint64(word[24:], v.Uint())
+
+	case kind == reflect.Ptr:
+		if v.IsNil() {
+			return nil, fmt.Errorf("failed to encode nil big.Int")
+		}
+		return toU256(v.Interface().(*big.Int)), nil
+
+	default:
+		val := v.Interface().(big.Int)
+		return toU256(&val), nil
+	}
+	return word, nil
+}
+
+func compileTypedFixedBytes(t *Type, isBytes bool) *typedPlan {
+	// addresses are left padded and the rest right padded
+	start := 0
+	if t.kind == KindAddress {
+		start = storageSlotSize - t.size
+	}
+
+	return &typedPlan{
+		decode: typedStatic(func(word []byte, dst reflect.Value) error {
+			if t.kind == KindFunction && !allZeros(word[24:]) {
+				return fmt.Errorf("function type expects the last 8 bytes to be empty but found: %b", word[24:])
+			}
+			data := word[start : start+t.size]
+			if isBytes {
+				dst.SetBytes(append([]byte{}, data...))
+			} else {
+				reflect.Copy(dst, reflect.ValueOf(data))
+			}
+			return nil
+		}),
+		encode: func(v reflect.Value) ([]byte, error) {
+			if v.Len() > t.size {
+				return nil, fmt.Errorf("%d bytes do not fit in %s", v.Len(), t)
+			}
+			word := make([]byte, storageSlotSize)
+			if isBytes {
+				copy(word[start:], v.Bytes())
+			} else {
+				reflect.Copy(reflect.ValueOf(word[start:]), v)
+			}
+			return word, nil
+		},
+	}
+}
+
+func compileTypedArraySlice(t *Type, rt reflect.Type) (*typedPlan, error) {
+	elem, err := compileTypedPlan(t.elem, rt.Elem())
+	if err != nil {
+		return nil, err
+	}
+	isDynamic := t.elem.isDynamicType()
+
+	decodeElems := func(data []byte, dst reflect.Value, size int) ([]byte, error) {
+		if size < 0 {
+			return nil, fmt.Errorf("size is lower than zero")
+		}
+		if storageSlotSize*size > len(data) {
+			return nil, fmt.Errorf("size is too big")
+		}
+		if rt.Kind() == reflect.Slice {
+			if dst.Cap() >= size {
+				dst.SetLen(size)
+			} else {
+				dst.Set(reflect.MakeSlice(rt, size, size))
+			}
+		}
+
+		orig := data
+		origLen := len(orig)
+		for indx := 0; indx < size; indx++ {
+			if len(data) < storageSlotSize {
+				return nil, fmt.Errorf("incorrect length")
+			}
+
+			entry := data
+			if isDynamic {
+				offset, err := readOffset(data, origLen)
+				if err != nil {
+					return nil, err
+				}
+				entry = orig[offset:]
+			}
+
+			tail, err := elem.decode(entry, dst.Index(indx))
+			if err != nil {
+				return nil, err
+			}
+
+			if !isDynamic {
+				data = tail
+			} else {
+				data = data[storageSlotSize:]
+			}
+		}
+		return data, nil
+	}
+
+	plan := &typedPlan{}
+	if t.kind == KindSlice {
+		plan.decode = func(input []byte, dst reflect.Value) ([]byte, error) {
+			if len(input) < storageSlotSize {
+				return nil, fmt.Errorf("incorrect length")
+			}
+			length, err := readLength(input)
+			if err != nil {
+				return nil, err
+			}
+			return decodeElems(input[storageSlotSize:], dst, length)
+		}
+	} else {
+		plan.decode = func(input []byte, dst reflect.Value) ([]byte, error) {
+			if len(input) < storageSlotSize {
+				return nil, fmt.Errorf("incorrect length")
+			}
+			return decodeElems(input, dst, t.size)
+		}
+	}
+
+	plan.encode = func(v reflect.Value) ([]byte, error) {
+		if t.kind == KindArray && v.Len() != t.size {
+			return nil, fmt.Errorf("array len incompatible")
+		}
+
+		var ret, tail []byte
+		if t.kind == KindSlice {
+			ret = append(ret, packNum(v.Len())...)
+		}
+
+		offset := 0
+		if isDynamic {
+			offset = getTypeSize(t.elem) * v.Len()
+		}
+		for i := 0; i < v.Len(); i++ {
+			val, err := elem.encode(v.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			if !isDynamic {
+				ret = append(ret, val...)
+			} else {
+				ret = append(ret, packNum(offset)...)
+				offset += len(val)
+				tail = append(tail, val...)
+			}
+		}
+		return append(ret, tail...), nil
+	}
+	return plan, nil
+}
+
+type typedField struct {
+	// index of the struct field or -1 if the element is not decoded
+	index     int
+	name      string
+	plan      *typedPlan
+	isDynamic bool
+	size      int
+	elem      *Type
+}
+
+// structFieldIndex returns the field of the struct for the tuple element
+func structFieldIndex(rt reflect.Type, name string) int {
+	found := -1
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		if f.PkgPath != "" {
+			continue
+		}
+		tagValue := f.Tag.Get("abi")
+		if tagValue == "-" {
+			continue
+		}
+		if tagValue == name {
+			return i
+		}
+		if tagValue == "" && found == -1 && strings.EqualFold(f.Name, name) {
+			found = i
+		}
+	}
+	return found
+}
+
+func compileTypedTuple(t *Type, rt reflect.Type) (*typedPlan, error) {
+	fields := make([]*typedField, len(t.tuple))
+
+	headSize := 0
+	for indx, elem := range t.tuple {
+		name := elem.Name
+		if name == "" {
+			name = strconv.Itoa(indx)
+		}
+		field := &typedField{
+			index:     structFieldIndex(rt, name),
+			name:      name,
+			isDynamic: elem.Elem.isDynamicType(),
+			size:      getTypeSize(elem.Elem),
+			elem:      elem.Elem,
+		}
+		if field.index != -1 {
+			plan, err := compileTypedPlan(elem.Elem, rt.Field(field.index).Type)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %v", rt.Field(field.index).Name, err)
+			}
+			field.plan = plan
+		}
+		fields[indx] = field
+		headSize += field.size
+	}
+
+	decodeFn := func(input []byte, dst reflect.Value) ([]byte, error) {
+		if len(input) < storageSlotSize {
+			return nil, fmt.Errorf("incorrect length")
+		}
+
+		data := input
+		origLen := len(input)
+		for _, field := range fields {
+			if len(data) < storageSlotSize {
+				return nil, fmt.Errorf("incorrect length")
+			}
+
+			entry := data
+			if field.isDynamic {
+				offset, err := readOffset(data, origLen)
+				if err != nil {
+					return nil, err
+				}
+				entry = input[offset:]
+			}
+
+			var (
+				tail []byte
+				err  error
+			)
+			if field.plan != nil {
+				tail, err = field.plan.decode(entry, dst.Field(field.index))
+			} else {
+				// there is no field for the element but it has to be read
+				// to know where the next element starts
+				_, tail, err = decode(field.elem, entry)
+			}
+			if err != nil {
+				return nil, err
+			}
+
+			if !field.isDynamic {
+				data = tail
+			} else {
+				data = data[storageSlotSize:]
+			}
+		}
+		return data, nil
+	}
+
+	encodeFn := func(v reflect.Value) ([]byte, error) {
+		offset := headSize
+
+		var ret, tail []byte
+		for _, field := range fields {
+			if field.plan == nil {
+				return nil, fmt.Errorf("cannot get key %s", field.name)
+			}
+			val, err := field.plan.encode(v.Field(field.index))
+			if err != nil {
+				return nil, err
+			}
+			if field.isDynamic {
+				ret = append(ret, packNum(offset)...)
+				tail = append(tail, val...)
+				offset += len(val)
+			} else {
+				ret = append(ret, val...)
+			}
+		}
+		return append(ret, tail...), nil
+	}
+
+	return &typedPlan{decode: decodeFn, encode: encodeFn}, nil
+}
@@ -0,0 +1,304 @@
+package abi
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/stretchr/testify/require"
+)
+
+type typedInner struct {
+	Num  uint8
+	Data []byte
+}
+
+type typedObj struct {
+	From   ethgo.Address
+	Value  *big.Int
+	Amount int64 `abi:"amt"`
+	Name   string
+	Hash   ethgo.Hash
+	Ok     bool
+	Ids    []uint64
+	Matrix [][]*big.Int
+	Inner  *typedInner
+	Inners []typedInner
+	Price  *ethgo.Decimal
+}
+
+var typedObjType = MustNewType(`tuple(
+	address from,
+	uint256 value,
+	int64 amt,
+	string name,
+	bytes32 hash,
+	bool ok,
+	uint64[] ids,
+	uint256[][] matrix,
+	tuple(uint8 num, bytes data) inner,
+	tuple(uint8 num, bytes data)[] inners,
+	ufixed128x18 price
+)`)
+
+func newTypedObj() *typedObj {
+	return &typedObj{
+		From:   ethgo.Address{0x1},
+		Value:  big.NewInt(1000),
+		Amount: -10,
+		Name:   "name",
+		Hash:   ethgo.Hash{0x2},
+		Ok:     true,
+		Ids:    []uint64{1, 2, 3},
+		Matrix: [][]*big.Int{{big.NewInt(1)}, {big.NewInt(2), big.NewInt(3)}},
+		Inner:  &typedInner{Num: 1, Data: []byte{0x1, 0x2}},
+		Inners: []typedInner{{Num: 2, Data: []byte{}}, {Num: 3, Data: []byte{0x3}}},
+		Price:  ethgo.NewDecimal(big.NewInt(15), 1),
+	}
+}
+
+func TestTyped_EncodeDecode(t *testing.T) {
+	obj := newTypedObj()
+
+	data, err := EncodeFrom(typedObjType, obj)
+	require.NoError(t, err)
+
+	// the encoding matches the untyped encoder
+	expected, err := Encode(obj, typedObjType)
+	require.NoError(t, err)
+	require.Equal(t, expected, data)
+
+	var found typedObj
+	require.NoError(t, DecodeInto(typedObjType, data, &found))
+
+	require.Equal(t, obj.From, found.From)
+	require.Equal(t, obj.Value, found.Value)
+	require.Equal(t, obj.Amount, found.Amount)
+	require.Equal(t, obj.Name, found.Name)
+	require.Equal(t, obj.Hash, found.Hash)
+	require.Equal(t, obj.Ok, found.Ok)
+	require.Equal(t, obj.Ids, found.Ids)
+	require.Equal(t, obj.Matrix, found.Matrix)
+	require.Equal(t, obj.Inner, found.Inner)
+	require.Equal(t, obj.Inners, found.Inners)
+	require.Equal(t, "1.5", found.Price.String())
+}
+
+func TestTyped_PartialStruct(t *testing.T) {
+	data, err := EncodeFrom(typedObjType, newTypedObj())
+	require.NoError(t, err)
+
+	// the elements without field are skipped when decoding
+	var obj struct {
+		Name  string
+		Price ethgo.Decimal
+		Rest  interface{} `abi:"inners"`
+	}
+	require.NoError(t, DecodeInto(typedObjType, data, &obj))
+	require.Equal(t, "name", obj.Name)
+	require.Equal(t, "1.5", obj.Price.String())
+	require.Len(t, obj.Rest, 2)
+
+	// but they are required to encode
+	_, err = EncodeFrom(typedObjType, obj)
+	require.Error(t, err)
+}
+
+func TestTyped_Numbers(t *testing.T) {
+	typ := MustNewType("tuple(int256 a, uint256 b)")
+
+	type obj struct {
+		A int8
+		B uint16
+	}
+
+	data, err := EncodeFrom(typ, obj{A: -5, B: 300})
+	require.NoError(t, err)
+
+	var res obj
+	require.NoError(t, DecodeInto(typ, data, &res))
+	require.Equal(t, obj{A: -5, B: 300}, res)
+
+	type bigObj struct {
+		A big.Int
+		B *big.Int
+	}
+
+	var resBig bigObj
+	require.NoError(t, DecodeInto(typ, data, &resBig))
+	require.Equal(t, int64(-5), resBig.A.Int64())
+	require.Equal(t, int64(300), resBig.B.Int64())
+
+	// values that do not fit in the fields
+	cases := []interface{}{
+		map[string]interface{}{"a": big.NewInt(128), "b": big.NewInt(0)},
+		map[string]interface{}{"a": big.NewInt(-129), "b": big.NewInt(0)},
+		map[string]interface{}{"a": big.NewInt(0), "b": big.NewInt(65536)},
+		map[string]interface{}{"a": new(big.Int).Lsh(big.NewInt(1), 64), "b": big.NewInt(0)},
+	}
+	for _, c := range cases {
+		data, err := Encode(c, typ)
+		require.NoError(t, err)
+
+		require.Error(t, DecodeInto(typ, data, &res))
+	}
+}
+
+func TestTyped_Arrays(t *testing.T) {
+	typ := MustNewType("tuple(uint8[2] a, bytes4[2][] b)")
+
+	type obj struct {
+		A [2]uint8
+		B [][2][4]byte
+	}
+	val := obj{A: [2]uint8{1, 2}, B: [][2][4]byte{{{0x1}, {0x2}}}}
+
+	data, err := EncodeFrom(typ, val)
+	require.NoError(t, err)
+
+	var res obj
+	require.NoError(t, DecodeInto(typ, data, &res))
+	require.Equal(t, val, res)
+
+	// fixed size arrays can be decoded into slices
+	var res2 struct {
+		A []uint8
+		B [][][]byte
+	}
+	require.NoError(t, DecodeInto(typ, data, &res2))
+	require.Equal(t, []uint8{1, 2}, res2.A)
+	require.Equal(t, [][][]byte{{{0x1, 0, 0, 0}, {0x2, 0, 0, 0}}}, res2.B)
+}
+
+func TestTyped_Errors(t *testing.T) {
+	// go types that are not compatible
+	var a struct {
+		A string
+	}
+	require.Error(t, DecodeInto(MustNewType("tuple(uint256 a)"), make([]byte, 32), &a))
+
+	var b [3]uint8
+	require.Error(t, DecodeInto(MustNewType("uint8[2]"), make([]byte, 64), &b))
+
+	var c [31]byte
+	require.Error(t, DecodeInto(MustNewType("bytes32"), make([]byte, 32), &c))
+
+	// invalid inputs
+	var d struct {
+		A []byte
+	}
+	require.Error(t, DecodeInto(MustNewType("tuple(bytes a)"), nil, &d))
+	outOfBounds := make([]byte, 32)
+	outOfBounds[31] = 0x40
+	require.Error(t, DecodeInto(MustNewType("tuple(bytes a)"), outOfBounds, &d))
+
+	// the same inputs that do not panic the untyped decoder
+	data, err := EncodeFrom(typedObjType, newTypedObj())
+	require.NoError(t, err)
+
+	buf := make([]byte, len(data))
+	for i := 0; i < len(data); i++ {
+		copy(buf, data)
+		buf[i] = 0xff
+
+		var obj typedObj
+		_ = DecodeInto(typedObjType, buf, &obj)
+	}
+}
+
+func BenchmarkDecode_Untyped(b *testing.B) {
+	data, err := EncodeFrom(typedObjType, newTypedObj())
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Decode(typedObjType, data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecode_Struct(b *testing.B) {
+	data, err := EncodeFrom(typedObjType, newTypedObj())
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var obj typedObj
+		if err := DecodeStruct(typedObjType, data, &obj); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDecode_Typed(b *testing.B) {
+	data, err := EncodeFrom(typedObjType, newTypedObj())
+	require.NoError(b, err)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var obj typedObj
+		if err := DecodeInto(typedObjType, data, &obj); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncode_Untyped(b *testing.B) {
+	obj := newTypedObj()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Encode(obj, typedObjType); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkEncode_Typed(b *testing.B) {
+	obj := newTypedObj()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := EncodeFrom(typedObjType, obj); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestTyped_PlanCache(t *testing.T) {
+	countPlans := func() (n int) {
+		typedPlans.Range(func(_, _ interface{}) bool {
+			n++
+			return true
+		})
+		return
+	}
+
+	type obj struct {
+		A uint64
+		B uint64
+	}
+	data, err := EncodeFrom(MustNewType("tuple(uint64 a, uint64 b)"), obj{A: 1, B: 2})
+	require.NoError(t, err)
+	plans := countPlans()
+
+	// the types built on each call share the plan of the encoding
+	for i := 0; i < 10; i++ {
+		var out obj
+		require.NoError(t, DecodeInto(MustNewType("tuple(uint64 a, uint64 b)"), data, &out))
+		require.Equal(t, obj{A: 1, B: 2}, out)
+	}
+	require.Equal(t, plans, countPlans())
+
+	// the names of the elements match different fields
+	var out obj
+	require.NoError(t, DecodeInto(MustNewType("tuple(uint64 b, uint64 a)"), data, &out))
+	require.Equal(t, obj{A: 2, B: 1}, out)
+	require.Equal(t, plans+1, countPlans())
+}
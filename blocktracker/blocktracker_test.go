@@ -79,6 +79,47 @@ func TestBlockTracker_Listener_Websocket(t *testing.T) {
 	testListener(t, s, tracker)
 }
 
+func TestBlockTracker_Listener_MockServer(t *testing.T) {
+	s := testutil.NewMockServer(t)
+
+	c, err := jsonrpc.NewClient(s.WSAddr())
+	require.NoError(t, err)
+	defer c.Close()
+
+	tracker, err := NewSubscriptionBlockTracker(c)
+	require.NoError(t, err)
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	blocks := make(chan *ethgo.Block, 10)
+	go tracker.Track(ctx, func(block *ethgo.Block) error {
+		blocks <- block
+		return nil
+	})
+
+	// wait for the subscription
+	require.Eventually(t, func() bool {
+		return s.Calls("eth_subscribe") == 1
+	}, 2*time.Second, 10*time.Millisecond)
+
+	l := testutil.MockList{}
+	l.Create(0, 3, func(b *testutil.MockBlock) {})
+	s.AddScenario(l)
+
+	// the notifications are handled concurrently by the client
+	received := map[uint64]struct{}{}
+	for i := 0; i < 3; i++ {
+		select {
+		case block := <-blocks:
+			received[block.Number] = struct{}{}
+		case <-time.After(2 * time.Second):
+			t.Fatal("timeout to receive block tracker block")
+		}
+	}
+	require.Len(t, received, 3)
+}
+
 func TestBlockTracker_Lifecycle(t *testing.T) {
 	t.Skip()
 	s := testutil.NewTestServer(t)
@@ -0,0 +1,110 @@
+package erc1155
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/builtin/metadata"
+	"github.com/Ethernal-Tech/ethgo/contract"
+)
+
+// TokenURI returns the uri of the token with the {id} placeholder
+// replaced by the hex encoded id of the token
+func (e *ERC1155) TokenURI(id *big.Int, block ...ethgo.BlockNumber) (string, error) {
+	uri, err := e.Uri(id, block...)
+	if err != nil {
+		return "", err
+	}
+	return metadata.ReplaceID(uri, id), nil
+}
+
+// TokenMetadata retrieves the metadata of the token from its uri
+func (e *ERC1155) TokenMetadata(id *big.Int, block ...ethgo.BlockNumber) (*metadata.Metadata, error) {
+	uri, err := e.TokenURI(id, block...)
+	if err != nil {
+		return nil, err
+	}
+	if uri == "" {
+		return nil, fmt.Errorf("token %s does not have a uri", id)
+	}
+	return metadata.Fetch(uri)
+}
+
+// SafeTransfer transfers an amount of a token without any data
+func (e *ERC1155) SafeTransfer(from, to ethgo.Address, id, value *big.Int) (contract.Txn, error) {
+	if to == ethgo.ZeroAddress {
+		return nil, fmt.Errorf("transfer to the zero address")
+	}
+	return e.SafeTransferFrom(from, to, id, value, []byte{})
+}
+
+// SafeBatchTransfer transfers amounts of several tokens
+func (e *ERC1155) SafeBatchTransfer(from, to ethgo.Address, ids, values []*big.Int, data []byte) (contract.Txn, error) {
+	if to == ethgo.ZeroAddress {
+		return nil, fmt.Errorf("transfer to the zero address")
+	}
+	if len(ids) != len(values) {
+		return nil, fmt.Errorf("ids and values length mismatch: %d != %d", len(ids), len(values))
+	}
+	if data == nil {
+		data = []byte{}
+	}
+	return e.SafeBatchTransferFrom(from, to, ids, values, data)
+}
+
+// TransferSingleEvent is a decoded TransferSingle event
+type TransferSingleEvent struct {
+	Operator ethgo.Address
+	From     ethgo.Address
+	To       ethgo.Address
+	Id       *big.Int
+	Value    *big.Int
+}
+
+// TransferBatchEvent is a decoded TransferBatch event
+type TransferBatchEvent struct {
+	Operator ethgo.Address
+	From     ethgo.Address
+	To       ethgo.Address
+	Ids      []*big.Int
+	Values   []*big.Int
+}
+
+func parseLog(name string, log *ethgo.Log) (map[string]interface{}, error) {
+	event := abiERC1155.Events[name]
+	if !event.Match(log) {
+		return nil, fmt.Errorf("log is not a %s event", name)
+	}
+	return event.ParseLog(log)
+}
+
+// ParseTransferSingleLog decodes a TransferSingle log
+func ParseTransferSingleLog(log *ethgo.Log) (*TransferSingleEvent, error) {
+	vals, err := parseLog("TransferSingle", log)
+	if err != nil {
+		return nil, err
+	}
+	return &TransferSingleEvent{
+		Operator: vals["operator"].(ethgo.Address),
+		From:     vals["from"].(ethgo.Address),
+		To:       vals["to"].(ethgo.Address),
+		Id:       vals["id"].(*big.Int),
+		Value:    vals["value"].(*big.Int),
+	}, nil
+}
+
+// ParseTransferBatchLog decodes a TransferBatch log
+func ParseTransferBatchLog(log *ethgo.Log) (*TransferBatchEvent, error) {
+	vals, err := parseLog("TransferBatch", log)
+	if err != nil {
+		return nil, err
+	}
+	return &TransferBatchEvent{
+		Operator: vals["operator"].(ethgo.Address),
+		From:     vals["from"].(ethgo.Address),
+		To:       vals["to"].(ethgo.Address),
+		Ids:      vals["ids"].([]*big.Int),
+		Values:   vals["values"].([]*big.Int),
+	}, nil
+}
@@ -0,0 +1,123 @@
+package erc1155
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/abi"
+	"github.com/Ethernal-Tech/ethgo/contract"
+	"github.com/Ethernal-Tech/ethgo/wallet"
+	"github.com/stretchr/testify/require"
+)
+
+type mockProvider struct {
+	outputs map[string][]byte
+	input   []byte
+}
+
+func (m *mockProvider) Call(addr ethgo.Address, input []byte, opts *contract.CallOpts) ([]byte, error) {
+	return m.outputs[hex.EncodeToString(input[:4])], nil
+}
+
+func (m *mockProvider) Txn(addr ethgo.Address, key ethgo.Key, input []byte) (contract.Txn, error) {
+	m.input = input
+	return nil, nil
+}
+
+func (m *mockProvider) setOutput(t *testing.T, method string, val interface{}) {
+	m2 := abiERC1155.GetMethod(method)
+
+	output, err := m2.Outputs.Encode(val)
+	require.NoError(t, err)
+
+	if m.outputs == nil {
+		m.outputs = map[string][]byte{}
+	}
+	m.outputs[hex.EncodeToString(m2.ID())] = output
+}
+
+func TestTokenURI(t *testing.T) {
+	provider := &mockProvider{}
+	provider.setOutput(t, "uri", map[string]interface{}{
+		"0": "https://token-cdn-domain/{id}.json",
+	})
+
+	erc1155 := NewERC1155(ethgo.Address{0x1}, contract.WithProvider(provider))
+
+	uri, err := erc1155.TokenURI(big.NewInt(314592))
+	require.NoError(t, err)
+	require.Equal(t, "https://token-cdn-domain/000000000000000000000000000000000000000000000000000000000004cce0.json", uri)
+}
+
+func TestSafeBatchTransfer(t *testing.T) {
+	key, err := wallet.GenerateKey()
+	require.NoError(t, err)
+
+	provider := &mockProvider{}
+	erc1155 := NewERC1155(ethgo.Address{0x1}, contract.WithProvider(provider), contract.WithSender(key))
+
+	ids := []*big.Int{big.NewInt(1), big.NewInt(2)}
+
+	_, err = erc1155.SafeBatchTransfer(key.Address(), ethgo.Address{0x2}, ids, []*big.Int{big.NewInt(1)}, nil)
+	require.Error(t, err)
+
+	_, err = erc1155.SafeBatchTransfer(key.Address(), ethgo.ZeroAddress, ids, ids, nil)
+	require.Error(t, err)
+
+	_, err = erc1155.SafeBatchTransfer(key.Address(), ethgo.Address{0x2}, ids, ids, nil)
+	require.NoError(t, err)
+	require.Equal(t, abiERC1155.GetMethod("safeBatchTransferFrom").ID(), provider.input[:4])
+}
+
+func topic(t *testing.T, typ string, val interface{}) ethgo.Hash {
+	h, err := abi.EncodeTopic(abi.MustNewType(typ), val)
+	require.NoError(t, err)
+	return h
+}
+
+func TestParseTransferLogs(t *testing.T) {
+	operator, from, to := ethgo.Address{0x1}, ethgo.Address{0x2}, ethgo.Address{0x3}
+
+	topics := []ethgo.Hash{
+		abiERC1155.Events["TransferSingle"].ID(),
+		topic(t, "address", operator),
+		topic(t, "address", from),
+		topic(t, "address", to),
+	}
+
+	data, err := abi.Encode([]interface{}{big.NewInt(1), big.NewInt(100)}, abi.MustNewType("tuple(uint256,uint256)"))
+	require.NoError(t, err)
+
+	single, err := ParseTransferSingleLog(&ethgo.Log{Topics: topics, Data: data})
+	require.NoError(t, err)
+	require.Equal(t, &TransferSingleEvent{
+		Operator: operator,
+		From:     from,
+		To:       to,
+		Id:       big.NewInt(1),
+		Value:    big.NewInt(100),
+	}, single)
+
+	ids := []*big.Int{big.NewInt(1), big.NewInt(2)}
+	values := []*big.Int{big.NewInt(10), big.NewInt(20)}
+
+	data, err = abi.Encode([]interface{}{ids, values}, abi.MustNewType("tuple(uint256[],uint256[])"))
+	require.NoError(t, err)
+
+	_, err = ParseTransferBatchLog(&ethgo.Log{Topics: topics, Data: data})
+	require.Error(t, err)
+
+	topics[0] = abiERC1155.Events["TransferBatch"].ID()
+
+	batch, err := ParseTransferBatchLog(&ethgo.Log{Topics: topics, Data: data})
+	require.NoError(t, err)
+	require.Equal(t, &TransferBatchEvent{
+		Operator: operator,
+		From:     from,
+		To:       to,
+		Ids:      ids,
+		Values:   values,
+	}, batch)
+}
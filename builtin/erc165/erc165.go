@@ -0,0 +1,54 @@
+// Code generated by ethgo/abigen. DO NOT EDIT.
+// Hash: 5d17d8cbcfb2db51ec7c68f75c9f61a98c1861e81ad013373b638e891365b1a7
+// Version: 0.1.1
+package erc165
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/contract"
+	"github.com/Ethernal-Tech/ethgo/jsonrpc"
+)
+
+var (
+	_ = big.NewInt
+	_ = jsonrpc.NewClient
+)
+
+// ERC165 is a solidity contract
+type ERC165 struct {
+	c *contract.Contract
+}
+
+// NewERC165 creates a new instance of the contract at a specific address
+func NewERC165(addr ethgo.Address, opts ...contract.ContractOption) *ERC165 {
+	return &ERC165{c: contract.NewContract(addr, abiERC165, opts...)}
+}
+
+// calls
+
+// SupportsInterface calls the supportsInterface method in the solidity contract
+func (e *ERC165) SupportsInterface(interfaceId [4]byte, block ...ethgo.BlockNumber) (retval0 bool, err error) {
+	var out map[string]interface{}
+	var ok bool
+
+	out, err = e.c.Call("supportsInterface", ethgo.EncodeBlock(block...), interfaceId)
+	if err != nil {
+		return
+	}
+
+	// decode outputs
+	retval0, ok = out["0"].(bool)
+	if !ok {
+		err = fmt.Errorf("failed to encode output at index 0")
+		return
+	}
+	
+	return
+}
+
+// txns
+
+// events
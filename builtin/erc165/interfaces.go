@@ -0,0 +1,114 @@
+package erc165
+
+import (
+	"errors"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/abi"
+	"github.com/Ethernal-Tech/ethgo/jsonrpc"
+)
+
+// Interface identifiers defined in the EIP-165 and the token standards
+var (
+	InterfaceERC165             = [4]byte{0x01, 0xff, 0xc9, 0xa7}
+	InterfaceERC721             = [4]byte{0x80, 0xac, 0x58, 0xcd}
+	InterfaceERC721Metadata     = [4]byte{0x5b, 0x5e, 0x13, 0x9f}
+	InterfaceERC721Enumerable   = [4]byte{0x78, 0x0e, 0x9d, 0x63}
+	InterfaceERC1155            = [4]byte{0xd9, 0xb6, 0x7a, 0x26}
+	InterfaceERC1155MetadataURI = [4]byte{0x0e, 0x89, 0x34, 0x1c}
+
+	// interfaceInvalid must not be supported by any ERC165 contract
+	interfaceInvalid = [4]byte{0xff, 0xff, 0xff, 0xff}
+)
+
+// NewInterfaceID returns the interface identifier of a set of methods
+// which is the XOR of all the method selectors
+func NewInterfaceID(methods ...*abi.Method) (id [4]byte) {
+	for _, m := range methods {
+		sel := m.ID()
+		for i := 0; i < 4; i++ {
+			id[i] ^= sel[i]
+		}
+	}
+	return
+}
+
+// Standard is a token standard detected with ERC165
+type Standard int
+
+const (
+	// StandardUnknown is a contract that does not implement any known standard
+	StandardUnknown Standard = iota
+
+	// StandardERC721 is a non-fungible token contract
+	StandardERC721
+
+	// StandardERC1155 is a multi token contract
+	StandardERC1155
+)
+
+func (s Standard) String() string {
+	names := [...]string{
+		"Unknown",
+		"ERC721",
+		"ERC1155",
+	}
+	return names[s]
+}
+
+// SupportsERC165 checks whether the contract implements ERC165 following the
+// detection procedure of the EIP. A call that reverts or does not return a boolean
+// (i.e. a contract without the method) means that the interface is not supported.
+// Any other error of the call is returned.
+func (e *ERC165) SupportsERC165(block ...ethgo.BlockNumber) (bool, error) {
+	if ok, err := e.supportsInterface(InterfaceERC165, block...); err != nil || !ok {
+		return false, err
+	}
+	if ok, err := e.supportsInterface(interfaceInvalid, block...); err != nil || ok {
+		return false, err
+	}
+	return true, nil
+}
+
+// Supports checks whether the contract implements ERC165 and the interface
+func (e *ERC165) Supports(interfaceId [4]byte, block ...ethgo.BlockNumber) (bool, error) {
+	ok, err := e.SupportsERC165(block...)
+	if err != nil || !ok {
+		return false, err
+	}
+	return e.supportsInterface(interfaceId, block...)
+}
+
+// DetectStandard returns the token standard implemented by the contract
+func (e *ERC165) DetectStandard(block ...ethgo.BlockNumber) (Standard, error) {
+	ok, err := e.SupportsERC165(block...)
+	if err != nil || !ok {
+		return StandardUnknown, err
+	}
+	if ok, err := e.supportsInterface(InterfaceERC721, block...); err != nil || ok {
+		return StandardERC721, err
+	}
+	if ok, err := e.supportsInterface(InterfaceERC1155, block...); err != nil || ok {
+		return StandardERC1155, err
+	}
+	return StandardUnknown, nil
+}
+
+// supportsInterface calls supportsInterface like SupportsInterface but the call
+// is not supported if it reverts or the return data is not a boolean
+func (e *ERC165) supportsInterface(interfaceId [4]byte, block ...ethgo.BlockNumber) (bool, error) {
+	method := abiERC165.GetMethod("supportsInterface")
+
+	data, err := e.c.CallInternal(method, ethgo.EncodeBlock(block...), interfaceId)
+	if errors.Is(err, jsonrpc.ErrExecutionReverted) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	out, err := method.Decode(data)
+	if err != nil {
+		return false, nil
+	}
+	ok, _ := out["0"].(bool)
+	return ok, nil
+}
@@ -0,0 +1,131 @@
+package erc165
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/abi"
+	"github.com/Ethernal-Tech/ethgo/contract"
+	"github.com/Ethernal-Tech/ethgo/jsonrpc/codec"
+	"github.com/stretchr/testify/require"
+)
+
+type mockProvider struct {
+	interfaces [][4]byte
+	err        error
+}
+
+func (m *mockProvider) Call(addr ethgo.Address, input []byte, opts *contract.CallOpts) ([]byte, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.interfaces == nil {
+		// contract without the supportsInterface method
+		return []byte{}, nil
+	}
+
+	var interfaceId [4]byte
+	copy(interfaceId[:], input[4:8])
+
+	found := false
+	for _, i := range m.interfaces {
+		if i == interfaceId {
+			found = true
+		}
+	}
+	return abiERC165.GetMethod("supportsInterface").Outputs.Encode([]interface{}{found})
+}
+
+func (m *mockProvider) Txn(addr ethgo.Address, key ethgo.Key, input []byte) (contract.Txn, error) {
+	return nil, nil
+}
+
+func TestNewInterfaceID(t *testing.T) {
+	erc721, err := abi.NewABIFromList([]string{
+		"function balanceOf(address owner) view returns (uint256)",
+		"function ownerOf(uint256 tokenId) view returns (address)",
+		"function safeTransferFrom(address from, address to, uint256 tokenId, bytes data)",
+		"function safeTransferFrom(address from, address to, uint256 tokenId)",
+		"function transferFrom(address from, address to, uint256 tokenId)",
+		"function approve(address to, uint256 tokenId)",
+		"function setApprovalForAll(address operator, bool approved)",
+		"function getApproved(uint256 tokenId) view returns (address)",
+		"function isApprovedForAll(address owner, address operator) view returns (bool)",
+	})
+	require.NoError(t, err)
+
+	methods := []*abi.Method{}
+	for _, m := range erc721.Methods {
+		methods = append(methods, m)
+	}
+	require.Equal(t, InterfaceERC721, NewInterfaceID(methods...))
+	require.Equal(t, InterfaceERC165, NewInterfaceID(abiERC165.GetMethod("supportsInterface")))
+}
+
+func TestDetectStandard(t *testing.T) {
+	cases := []struct {
+		interfaces [][4]byte
+		standard   Standard
+	}{
+		{
+			nil,
+			StandardUnknown,
+		},
+		{
+			[][4]byte{InterfaceERC165},
+			StandardUnknown,
+		},
+		{
+			// it does not follow the ERC165 detection procedure
+			[][4]byte{InterfaceERC165, interfaceInvalid, InterfaceERC721},
+			StandardUnknown,
+		},
+		{
+			[][4]byte{InterfaceERC165, InterfaceERC721, InterfaceERC721Metadata},
+			StandardERC721,
+		},
+		{
+			[][4]byte{InterfaceERC165, InterfaceERC1155},
+			StandardERC1155,
+		},
+	}
+
+	for _, c := range cases {
+		e := NewERC165(ethgo.Address{0x1}, contract.WithProvider(&mockProvider{interfaces: c.interfaces}))
+
+		standard, err := e.DetectStandard()
+		require.NoError(t, err)
+		require.Equal(t, c.standard, standard)
+	}
+
+	e := NewERC165(ethgo.Address{0x1}, contract.WithProvider(&mockProvider{interfaces: [][4]byte{InterfaceERC165, InterfaceERC721}}))
+
+	ok, err := e.Supports(InterfaceERC721)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = e.Supports(InterfaceERC721Metadata)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestDetectStandard_Errors(t *testing.T) {
+	// a contract that reverts does not implement ERC165
+	e := NewERC165(ethgo.Address{0x1}, contract.WithProvider(&mockProvider{
+		err: &codec.ErrorObject{Code: 3, Message: "execution reverted"},
+	}))
+	standard, err := e.DetectStandard()
+	require.NoError(t, err)
+	require.Equal(t, StandardUnknown, standard)
+
+	// the other errors are returned
+	transportErr := fmt.Errorf("connection refused")
+	e = NewERC165(ethgo.Address{0x1}, contract.WithProvider(&mockProvider{err: transportErr}))
+
+	_, err = e.DetectStandard()
+	require.ErrorIs(t, err, transportErr)
+
+	_, err = e.Supports(InterfaceERC721)
+	require.ErrorIs(t, err, transportErr)
+}
@@ -29,6 +29,26 @@ func NewERC20(addr ethgo.Address, opts ...contract.ContractOption) *ERC20 {
 
 // calls
 
+// DOMAINSEPARATOR calls the DOMAIN_SEPARATOR method in the solidity contract
+func (e *ERC20) DOMAINSEPARATOR(block ...ethgo.BlockNumber) (retval0 [32]byte, err error) {
+	var out map[string]interface{}
+	var ok bool
+
+	out, err = e.c.Call("DOMAIN_SEPARATOR", ethgo.EncodeBlock(block...))
+	if err != nil {
+		return
+	}
+
+	// decode outputs
+	retval0, ok = out["0"].([32]byte)
+	if !ok {
+		err = fmt.Errorf("failed to encode output at index 0")
+		return
+	}
+	
+	return
+}
+
 // Allowance calls the allowance method in the solidity contract
 func (e *ERC20) Allowance(owner ethgo.Address, spender ethgo.Address, block ...ethgo.BlockNumber) (retval0 *big.Int, err error) {
 	var out map[string]interface{}
@@ -109,6 +129,26 @@ func (e *ERC20) Name(block ...ethgo.BlockNumber) (retval0 string, err error) {
 	return
 }
 
+// Nonces calls the nonces method in the solidity contract
+func (e *ERC20) Nonces(owner ethgo.Address, block ...ethgo.BlockNumber) (retval0 *big.Int, err error) {
+	var out map[string]interface{}
+	var ok bool
+
+	out, err = e.c.Call("nonces", ethgo.EncodeBlock(block...), owner)
+	if err != nil {
+		return
+	}
+
+	// decode outputs
+	retval0, ok = out["0"].(*big.Int)
+	if !ok {
+		err = fmt.Errorf("failed to encode output at index 0")
+		return
+	}
+	
+	return
+}
+
 // Symbol calls the symbol method in the solidity contract
 func (e *ERC20) Symbol(block ...ethgo.BlockNumber) (retval0 string, err error) {
 	var out map[string]interface{}
@@ -156,6 +196,11 @@ func (e *ERC20) Approve(spender ethgo.Address, value *big.Int) (contract.Txn, er
 	return e.c.Txn("approve", spender, value)
 }
 
+// Permit sends a permit transaction in the solidity contract
+func (e *ERC20) Permit(owner ethgo.Address, spender ethgo.Address, value *big.Int, deadline *big.Int, v uint8, r [32]byte, s [32]byte) (contract.Txn, error) {
+	return e.c.Txn("permit", owner, spender, value, deadline, v, r, s)
+}
+
 // Transfer sends a transfer transaction in the solidity contract
 func (e *ERC20) Transfer(to ethgo.Address, value *big.Int) (contract.Txn, error) {
 	return e.c.Txn("transfer", to, value)
@@ -0,0 +1,113 @@
+package erc20
+
+import (
+	"bytes"
+	"fmt"
+	"math/big"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/contract"
+	"github.com/Ethernal-Tech/ethgo/signing"
+)
+
+// Permit is the EIP-2612 message signed by the owner of the tokens
+// to approve a spender without sending a transaction
+type Permit struct {
+	Owner    ethgo.Address `eip712:"owner"`
+	Spender  ethgo.Address `eip712:"spender"`
+	Value    *big.Int      `eip712:"value"`
+	Nonce    *big.Int      `eip712:"nonce"`
+	Deadline *big.Int      `eip712:"deadline"`
+}
+
+// PermitSignature is a signature in the format expected by the permit method
+type PermitSignature struct {
+	V uint8
+	R [32]byte
+	S [32]byte
+}
+
+// NewPermitDomain returns the EIP-712 domain of an EIP-2612 token
+func NewPermitDomain(name, version string, chainID *big.Int, token ethgo.Address) *signing.EIP712Domain {
+	return &signing.EIP712Domain{
+		Name:              name,
+		Version:           version,
+		ChainId:           chainID,
+		VerifyingContract: token.String(),
+	}
+}
+
+// BuildPermit returns the typed data of the permit for the given domain
+func BuildPermit(domain *signing.EIP712Domain, permit *Permit) *signing.EIP712TypedData {
+	return signing.NewEIP712MessageBuilder[Permit](domain).Build(permit)
+}
+
+// SignPermit signs the permit with the key of the owner
+func SignPermit(key ethgo.Key, domain *signing.EIP712Domain, permit *Permit) (*PermitSignature, error) {
+	if key.Address() != permit.Owner {
+		return nil, fmt.Errorf("key %s is not the owner of the permit %s", key.Address(), permit.Owner)
+	}
+
+	sig, err := BuildPermit(domain, permit).SignTypedData(key)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &PermitSignature{
+		V: sig[64],
+	}
+	copy(res.R[:], sig[:32])
+	copy(res.S[:], sig[32:64])
+	return res, nil
+}
+
+// PermitDomain returns the EIP-712 domain of the token. The domain is built with the name
+// of the token and checked against the DOMAIN_SEPARATOR of the contract. The version
+// is usually "1" but some tokens use a different one (i.e. USDC uses "2").
+func (e *ERC20) PermitDomain(version string, chainID *big.Int, block ...ethgo.BlockNumber) (*signing.EIP712Domain, error) {
+	name, err := e.Name(block...)
+	if err != nil {
+		return nil, err
+	}
+	domain := NewPermitDomain(name, version, chainID, e.c.Addr())
+
+	expected, err := e.DOMAINSEPARATOR(block...)
+	if err != nil {
+		return nil, err
+	}
+	found, err := domain.Hash()
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(expected[:], found) {
+		return nil, fmt.Errorf("domain separator mismatch: expected 0x%x but found 0x%x", expected, found)
+	}
+	return domain, nil
+}
+
+// SignPermit builds a permit for the spender with the current nonce of the owner
+// and signs it with its key
+func (e *ERC20) SignPermit(key ethgo.Key, domain *signing.EIP712Domain, spender ethgo.Address, value *big.Int, deadline *big.Int) (*Permit, *PermitSignature, error) {
+	nonce, err := e.Nonces(key.Address())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	permit := &Permit{
+		Owner:    key.Address(),
+		Spender:  spender,
+		Value:    value,
+		Nonce:    nonce,
+		Deadline: deadline,
+	}
+	sig, err := SignPermit(key, domain, permit)
+	if err != nil {
+		return nil, nil, err
+	}
+	return permit, sig, nil
+}
+
+// PermitWithSignature sends a permit transaction with a signed permit
+func (e *ERC20) PermitWithSignature(permit *Permit, sig *PermitSignature) (contract.Txn, error) {
+	return e.Permit(permit.Owner, permit.Spender, permit.Value, permit.Deadline, sig.V, sig.R, sig.S)
+}
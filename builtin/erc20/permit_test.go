@@ -0,0 +1,115 @@
+package erc20
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/contract"
+	"github.com/Ethernal-Tech/ethgo/signing"
+	"github.com/Ethernal-Tech/ethgo/wallet"
+	"github.com/stretchr/testify/require"
+)
+
+type mockProvider struct {
+	outputs map[string][]byte
+	input   []byte
+}
+
+func (m *mockProvider) Call(addr ethgo.Address, input []byte, opts *contract.CallOpts) ([]byte, error) {
+	return m.outputs[hex.EncodeToString(input[:4])], nil
+}
+
+func (m *mockProvider) Txn(addr ethgo.Address, key ethgo.Key, input []byte) (contract.Txn, error) {
+	m.input = input
+	return nil, nil
+}
+
+func (m *mockProvider) setOutput(t *testing.T, method string, val interface{}) {
+	m2 := abiERC20.GetMethod(method)
+
+	output, err := m2.Outputs.Encode(val)
+	require.NoError(t, err)
+
+	if m.outputs == nil {
+		m.outputs = map[string][]byte{}
+	}
+	m.outputs[hex.EncodeToString(m2.ID())] = output
+}
+
+func TestPermit_EncodeType(t *testing.T) {
+	b := signing.NewEIP712MessageBuilder[Permit](nil)
+	require.Equal(t, "Permit(address owner,address spender,uint256 value,uint256 nonce,uint256 deadline)", b.GetEncodedType())
+
+	// PERMIT_TYPEHASH in the EIP-2612 specification
+	require.Equal(t, "6e71edae12b1b97f4d1f60370fef10105fa2faae0126114a169c64845d6126c9", hex.EncodeToString(ethgo.Keccak256([]byte(b.GetEncodedType()))))
+}
+
+func TestPermit_SignAndSubmit(t *testing.T) {
+	key, err := wallet.GenerateKey()
+	require.NoError(t, err)
+
+	token := ethgo.Address{0x1}
+	spender := ethgo.Address{0x2}
+	chainID := big.NewInt(1337)
+
+	provider := &mockProvider{}
+	erc20 := NewERC20(token, contract.WithProvider(provider), contract.WithSender(key))
+
+	domain := NewPermitDomain("Token", "1", chainID, token)
+	domainSeparator, err := domain.Hash()
+	require.NoError(t, err)
+
+	provider.setOutput(t, "name", []interface{}{"Token"})
+	provider.setOutput(t, "DOMAIN_SEPARATOR", []interface{}{ethgo.BytesToHash(domainSeparator)})
+	provider.setOutput(t, "nonces", []interface{}{big.NewInt(5)})
+
+	// the domain does not match with a different version
+	_, err = erc20.PermitDomain("2", chainID)
+	require.ErrorContains(t, err, "domain separator mismatch")
+
+	found, err := erc20.PermitDomain("1", chainID)
+	require.NoError(t, err)
+	require.Equal(t, domain, found)
+
+	permit, sig, err := erc20.SignPermit(key, found, spender, big.NewInt(100), big.NewInt(1000))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(5), permit.Nonce)
+	require.Equal(t, key.Address(), permit.Owner)
+
+	// recover the owner from the signature
+	rawSig := append(append(append([]byte{}, sig.R[:]...), sig.S[:]...), sig.V)
+	addr, err := signing.RecoverTypedDataSigner(BuildPermit(domain, permit), rawSig)
+	require.NoError(t, err)
+	require.Equal(t, key.Address(), addr)
+
+	// submit the permit
+	_, err = erc20.PermitWithSignature(permit, sig)
+	require.NoError(t, err)
+
+	method := abiERC20.GetMethod("permit")
+	require.Equal(t, method.ID(), provider.input[:4])
+
+	args, err := method.Inputs.Decode(provider.input[4:])
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]interface{}{
+		"owner":    key.Address(),
+		"spender":  spender,
+		"value":    big.NewInt(100),
+		"deadline": big.NewInt(1000),
+		"v":        sig.V,
+		"r":        sig.R,
+		"s":        sig.S,
+	}, args)
+}
+
+func TestPermit_WrongOwner(t *testing.T) {
+	key, err := wallet.GenerateKey()
+	require.NoError(t, err)
+
+	domain := NewPermitDomain("Token", "1", big.NewInt(1), ethgo.Address{0x1})
+	_, err = SignPermit(key, domain, &Permit{Owner: ethgo.Address{0x2}})
+	require.Error(t, err)
+}
@@ -0,0 +1,263 @@
+package erc20
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/contract"
+)
+
+// DefaultChunkSize is the default number of blocks queried
+// in each eth_getLogs request of the transfer history
+var DefaultChunkSize = uint64(1000)
+
+// LogProvider returns the logs that match a filter
+type LogProvider interface {
+	GetLogs(filter *ethgo.LogFilter) ([]*ethgo.Log, error)
+}
+
+// TokenInfo is the metadata of the token
+type TokenInfo struct {
+	Name     string
+	Symbol   string
+	Decimals uint8
+}
+
+// Token is an ERC20 contract that caches the metadata of the token
+// and works with amounts in the units of the token
+type Token struct {
+	*ERC20
+
+	addr ethgo.Address
+	lock sync.Mutex
+	info *TokenInfo
+}
+
+// NewToken creates a new token helper for the contract at the address
+func NewToken(addr ethgo.Address, opts ...contract.ContractOption) *Token {
+	return &Token{
+		ERC20: NewERC20(addr, opts...),
+		addr:  addr,
+	}
+}
+
+// Address returns the address of the token
+func (t *Token) Address() ethgo.Address {
+	return t.addr
+}
+
+// Info returns the name, symbol and decimals of the token. The values
+// are queried only once and cached afterwards.
+func (t *Token) Info() (*TokenInfo, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if t.info != nil {
+		return t.info, nil
+	}
+
+	name, err := t.Name()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query name: %v", err)
+	}
+	symbol, err := t.Symbol()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query symbol: %v", err)
+	}
+	decimals, err := t.Decimals()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query decimals: %v", err)
+	}
+
+	t.info = &TokenInfo{
+		Name:     name,
+		Symbol:   symbol,
+		Decimals: decimals,
+	}
+	return t.info, nil
+}
+
+// ParseAmount converts an amount in the units of the token (i.e. "12.5")
+// to the raw value used by the contract
+func (t *Token) ParseAmount(amount string) (*big.Int, error) {
+	info, err := t.Info()
+	if err != nil {
+		return nil, err
+	}
+	return ParseAmount(amount, info.Decimals)
+}
+
+// FormatAmount converts a raw value of the contract to an amount in the units of the token
+func (t *Token) FormatAmount(value *big.Int) (string, error) {
+	info, err := t.Info()
+	if err != nil {
+		return "", err
+	}
+	return FormatAmount(value, info.Decimals), nil
+}
+
+// ParseAmount converts a decimal amount (i.e. "12.5") to an integer value
+// with the given number of decimals. It fails if the amount is negative or
+// has more fractional digits than decimals.
+func ParseAmount(amount string, decimals uint8) (*big.Int, error) {
+	value, err := ethgo.ParseUnits(amount, ethgo.Unit(decimals))
+	if err != nil {
+		return nil, err
+	}
+	if value.Sign() < 0 {
+		return nil, fmt.Errorf("negative amount '%s'", amount)
+	}
+	return value, nil
+}
+
+// FormatAmount converts an integer value with the given number of decimals
+// to its decimal representation without trailing zeros
+func FormatAmount(value *big.Int, decimals uint8) string {
+	return ethgo.FormatUnits(value, ethgo.Unit(decimals), -1)
+}
+
+func addressesToTopics(addrs []ethgo.Address) []*ethgo.Hash {
+	if len(addrs) == 0 {
+		return nil
+	}
+	topics := make([]*ethgo.Hash, len(addrs))
+	for i, addr := range addrs {
+		topic := ethgo.BytesToHash(addr.Bytes())
+		topics[i] = &topic
+	}
+	return topics
+}
+
+// TransferFilter returns a filter for the Transfer logs of the token. An empty
+// list of senders or receivers matches any address.
+func (t *Token) TransferFilter(from, to []ethgo.Address) *ethgo.LogFilter {
+	id := t.TransferEventSig()
+	return &ethgo.LogFilter{
+		Address: []ethgo.Address{t.addr},
+		Topics: [][]*ethgo.Hash{
+			{&id},
+			addressesToTopics(from),
+			addressesToTopics(to),
+		},
+	}
+}
+
+// ApprovalFilter returns a filter for the Approval logs of the token. An empty
+// list of owners or spenders matches any address.
+func (t *Token) ApprovalFilter(owner, spender []ethgo.Address) *ethgo.LogFilter {
+	id := t.ApprovalEventSig()
+	return &ethgo.LogFilter{
+		Address: []ethgo.Address{t.addr},
+		Topics: [][]*ethgo.Hash{
+			{&id},
+			addressesToTopics(owner),
+			addressesToTopics(spender),
+		},
+	}
+}
+
+// TransferEvent is a decoded Transfer event
+type TransferEvent struct {
+	From  ethgo.Address
+	To    ethgo.Address
+	Value *big.Int
+	Log   *ethgo.Log
+}
+
+// ParseTransferLog decodes a Transfer log. The ERC721 Transfer event has the same
+// signature but four topics, those logs are rejected.
+func ParseTransferLog(log *ethgo.Log) (*TransferEvent, error) {
+	event := abiERC20.Events["Transfer"]
+	if !event.Match(log) {
+		return nil, fmt.Errorf("log is not a Transfer event")
+	}
+	if len(log.Topics) != 3 {
+		return nil, fmt.Errorf("expected 3 topics but found %d", len(log.Topics))
+	}
+	vals, err := event.ParseLog(log)
+	if err != nil {
+		return nil, err
+	}
+	return &TransferEvent{
+		From:  vals["from"].(ethgo.Address),
+		To:    vals["to"].(ethgo.Address),
+		Value: vals["value"].(*big.Int),
+		Log:   log,
+	}, nil
+}
+
+// Transfers returns the decoded Transfer logs that match any of the filters between the
+// from and to blocks (inclusive). The logs are queried in ranges of chunkSize blocks
+// (DefaultChunkSize if zero) and returned sorted by block and log index.
+func (t *Token) Transfers(provider LogProvider, filters []*ethgo.LogFilter, from, to uint64, chunkSize uint64) ([]*TransferEvent, error) {
+	if from > to {
+		return nil, fmt.Errorf("from block %d is greater than to block %d", from, to)
+	}
+	if chunkSize == 0 {
+		chunkSize = DefaultChunkSize
+	}
+
+	type logKey struct {
+		hash  ethgo.Hash
+		index uint64
+	}
+	seen := map[logKey]struct{}{}
+
+	res := []*TransferEvent{}
+	for start := from; start <= to; start += chunkSize {
+		end := start + chunkSize - 1
+		if end > to || end < start {
+			end = to
+		}
+
+		for _, f := range filters {
+			filter := *f
+			filter.SetFromUint64(start)
+			filter.SetToUint64(end)
+
+			logs, err := provider.GetLogs(&filter)
+			if err != nil {
+				return nil, err
+			}
+			for _, log := range logs {
+				// the same log can match several filters (i.e. self transfers)
+				key := logKey{log.BlockHash, log.LogIndex}
+				if _, ok := seen[key]; ok {
+					continue
+				}
+				seen[key] = struct{}{}
+
+				event, err := ParseTransferLog(log)
+				if err != nil {
+					return nil, err
+				}
+				res = append(res, event)
+			}
+		}
+
+		if end == to {
+			break
+		}
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].Log.BlockNumber != res[j].Log.BlockNumber {
+			return res[i].Log.BlockNumber < res[j].Log.BlockNumber
+		}
+		return res[i].Log.LogIndex < res[j].Log.LogIndex
+	})
+	return res, nil
+}
+
+// TransferHistory returns the transfers sent or received by the account between
+// the from and to blocks (inclusive)
+func (t *Token) TransferHistory(provider LogProvider, account ethgo.Address, from, to uint64, chunkSize uint64) ([]*TransferEvent, error) {
+	filters := []*ethgo.LogFilter{
+		t.TransferFilter([]ethgo.Address{account}, nil),
+		t.TransferFilter(nil, []ethgo.Address{account}),
+	}
+	return t.Transfers(provider, filters, from, to, chunkSize)
+}
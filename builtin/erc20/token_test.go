@@ -0,0 +1,186 @@
+package erc20
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/abi"
+	"github.com/Ethernal-Tech/ethgo/contract"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAndFormatAmount(t *testing.T) {
+	cases := []struct {
+		amount   string
+		decimals uint8
+		value    string
+		format   string
+	}{
+		{"12.5", 6, "12500000", "12.5"},
+		{"1", 18, "1000000000000000000", "1"},
+		{"0.000001", 6, "1", "0.000001"},
+		{".5", 2, "50", "0.5"},
+		{"100.", 2, "10000", "100"},
+		{"007", 0, "7", "7"},
+		{"1.10", 2, "110", "1.1"},
+		{"1.5e3", 0, "1500", "1500"},
+	}
+
+	for _, c := range cases {
+		value, err := ParseAmount(c.amount, c.decimals)
+		require.NoError(t, err)
+		require.Equal(t, c.value, value.String())
+		require.Equal(t, c.format, FormatAmount(value, c.decimals))
+	}
+
+	for _, amount := range []string{"", ".", "1.0000001", "-1", "1.2.3", "abc"} {
+		_, err := ParseAmount(amount, 6)
+		require.Error(t, err, amount)
+	}
+
+	require.Equal(t, "-0.05", FormatAmount(big.NewInt(-5), 2))
+}
+
+type countProvider struct {
+	mockProvider
+	calls int
+}
+
+func (c *countProvider) Call(addr ethgo.Address, input []byte, opts *contract.CallOpts) ([]byte, error) {
+	c.calls++
+	return c.mockProvider.Call(addr, input, opts)
+}
+
+func TestToken_Info(t *testing.T) {
+	provider := &countProvider{}
+	provider.setOutput(t, "name", map[string]interface{}{"0": "Token"})
+	provider.setOutput(t, "symbol", map[string]interface{}{"0": "TKN"})
+	provider.setOutput(t, "decimals", map[string]interface{}{"0": uint8(6)})
+
+	token := NewToken(ethgo.Address{0x1}, contract.WithProvider(provider))
+
+	for i := 0; i < 2; i++ {
+		info, err := token.Info()
+		require.NoError(t, err)
+		require.Equal(t, &TokenInfo{Name: "Token", Symbol: "TKN", Decimals: 6}, info)
+	}
+	require.Equal(t, 3, provider.calls)
+
+	value, err := token.ParseAmount("12.5")
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(12500000), value)
+
+	amount, err := token.FormatAmount(value)
+	require.NoError(t, err)
+	require.Equal(t, "12.5", amount)
+}
+
+type mockLogProvider struct {
+	logs    []*ethgo.Log
+	filters []*ethgo.LogFilter
+}
+
+func (m *mockLogProvider) GetLogs(filter *ethgo.LogFilter) ([]*ethgo.Log, error) {
+	m.filters = append(m.filters, filter)
+
+	res := []*ethgo.Log{}
+	for _, log := range m.logs {
+		if log.BlockNumber < uint64(*filter.From) || log.BlockNumber > uint64(*filter.To) {
+			continue
+		}
+		if log.Address != filter.Address[0] {
+			continue
+		}
+		if !matchTopics(log.Topics, filter.Topics) {
+			continue
+		}
+		res = append(res, log)
+	}
+	return res, nil
+}
+
+func matchTopics(topics []ethgo.Hash, filter [][]*ethgo.Hash) bool {
+	for i, set := range filter {
+		if set == nil {
+			continue
+		}
+		if i >= len(topics) {
+			return false
+		}
+		found := false
+		for _, topic := range set {
+			if *topic == topics[i] {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func TestToken_TransferHistory(t *testing.T) {
+	tokenAddr := ethgo.Address{0x1}
+	account := ethgo.Address{0x2}
+	other := ethgo.Address{0x3}
+
+	token := NewToken(tokenAddr, contract.WithProvider(&mockProvider{}))
+
+	transfer := func(num, index uint64, from, to ethgo.Address, value int64) *ethgo.Log {
+		data, err := abi.Encode([]interface{}{big.NewInt(value)}, abi.MustNewType("tuple(uint256)"))
+		require.NoError(t, err)
+
+		return &ethgo.Log{
+			Address:     tokenAddr,
+			BlockNumber: num,
+			BlockHash:   ethgo.Hash{byte(num)},
+			LogIndex:    index,
+			Topics: []ethgo.Hash{
+				token.TransferEventSig(),
+				ethgo.BytesToHash(from.Bytes()),
+				ethgo.BytesToHash(to.Bytes()),
+			},
+			Data: data,
+		}
+	}
+
+	provider := &mockLogProvider{
+		logs: []*ethgo.Log{
+			transfer(1, 0, other, account, 1),
+			transfer(5, 1, account, other, 2),
+			transfer(5, 0, other, other, 3),
+			transfer(12, 0, account, account, 4),
+			transfer(25, 0, other, account, 5),
+		},
+	}
+
+	events, err := token.TransferHistory(provider, account, 0, 20, 10)
+	require.NoError(t, err)
+
+	// 3 chunks with two filters each
+	require.Len(t, provider.filters, 6)
+
+	values := []string{}
+	for _, event := range events {
+		values = append(values, event.Value.String())
+	}
+	require.Equal(t, []string{"1", "2", "4"}, values)
+	require.Equal(t, account, events[0].To)
+	require.Equal(t, account, events[1].From)
+
+	_, err = token.TransferHistory(provider, account, 10, 5, 0)
+	require.Error(t, err)
+}
+
+func TestToken_Filters(t *testing.T) {
+	token := NewToken(ethgo.Address{0x1}, contract.WithProvider(&mockProvider{}))
+
+	filter := token.ApprovalFilter([]ethgo.Address{{0x2}}, nil)
+	require.Equal(t, []ethgo.Address{{0x1}}, filter.Address)
+	require.Equal(t, token.ApprovalEventSig(), *filter.Topics[0][0])
+	require.Equal(t, "0000000000000000000000000200000000000000000000000000000000000000", hex.EncodeToString(filter.Topics[1][0][:]))
+	require.Nil(t, filter.Topics[2])
+}
@@ -0,0 +1,62 @@
+package erc721
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/builtin/metadata"
+	"github.com/Ethernal-Tech/ethgo/contract"
+)
+
+// SafeTransfer transfers the token with the safeTransferFrom method. The
+// overload with the data argument is only used if any data is given.
+func (e *ERC721) SafeTransfer(from, to ethgo.Address, tokenId *big.Int, data []byte) (contract.Txn, error) {
+	if to == ethgo.ZeroAddress {
+		return nil, fmt.Errorf("transfer to the zero address")
+	}
+	if len(data) == 0 {
+		return e.SafeTransferFrom(from, to, tokenId)
+	}
+	return e.SafeTransferFrom0(from, to, tokenId, data)
+}
+
+// TokenMetadata retrieves the metadata of the token from its tokenURI
+func (e *ERC721) TokenMetadata(tokenId *big.Int, block ...ethgo.BlockNumber) (*metadata.Metadata, error) {
+	uri, err := e.TokenURI(tokenId, block...)
+	if err != nil {
+		return nil, err
+	}
+	if uri == "" {
+		return nil, fmt.Errorf("token %s does not have a uri", tokenId)
+	}
+	return metadata.Fetch(uri)
+}
+
+// TransferEvent is a decoded Transfer event
+type TransferEvent struct {
+	From    ethgo.Address
+	To      ethgo.Address
+	TokenId *big.Int
+}
+
+// ParseTransferLog decodes a Transfer log. The ERC20 Transfer event has the same
+// signature but only three topics, those logs are rejected.
+func ParseTransferLog(log *ethgo.Log) (*TransferEvent, error) {
+	event := abiERC721.Events["Transfer"]
+	if !event.Match(log) {
+		return nil, fmt.Errorf("log is not a Transfer event")
+	}
+	if len(log.Topics) != 4 {
+		return nil, fmt.Errorf("expected 4 topics but found %d", len(log.Topics))
+	}
+	vals, err := event.ParseLog(log)
+	if err != nil {
+		return nil, err
+	}
+	return &TransferEvent{
+		From:    vals["from"].(ethgo.Address),
+		To:      vals["to"].(ethgo.Address),
+		TokenId: vals["tokenId"].(*big.Int),
+	}, nil
+}
@@ -0,0 +1,110 @@
+package erc721
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/abi"
+	"github.com/Ethernal-Tech/ethgo/contract"
+	"github.com/Ethernal-Tech/ethgo/wallet"
+	"github.com/stretchr/testify/require"
+)
+
+type mockProvider struct {
+	outputs map[string][]byte
+	input   []byte
+}
+
+func (m *mockProvider) Call(addr ethgo.Address, input []byte, opts *contract.CallOpts) ([]byte, error) {
+	return m.outputs[hex.EncodeToString(input[:4])], nil
+}
+
+func (m *mockProvider) Txn(addr ethgo.Address, key ethgo.Key, input []byte) (contract.Txn, error) {
+	m.input = input
+	return nil, nil
+}
+
+func (m *mockProvider) setOutput(t *testing.T, method string, val interface{}) {
+	m2 := abiERC721.GetMethod(method)
+
+	output, err := m2.Outputs.Encode(val)
+	require.NoError(t, err)
+
+	if m.outputs == nil {
+		m.outputs = map[string][]byte{}
+	}
+	m.outputs[hex.EncodeToString(m2.ID())] = output
+}
+
+func TestSafeTransfer(t *testing.T) {
+	key, err := wallet.GenerateKey()
+	require.NoError(t, err)
+
+	provider := &mockProvider{}
+	erc721 := NewERC721(ethgo.Address{0x1}, contract.WithProvider(provider), contract.WithSender(key))
+
+	_, err = erc721.SafeTransfer(key.Address(), ethgo.ZeroAddress, big.NewInt(1), nil)
+	require.Error(t, err)
+
+	_, err = erc721.SafeTransfer(key.Address(), ethgo.Address{0x2}, big.NewInt(1), nil)
+	require.NoError(t, err)
+	require.Equal(t, "42842e0e", hex.EncodeToString(provider.input[:4]))
+
+	_, err = erc721.SafeTransfer(key.Address(), ethgo.Address{0x2}, big.NewInt(1), []byte{0x1})
+	require.NoError(t, err)
+	require.Equal(t, "b88d4fde", hex.EncodeToString(provider.input[:4]))
+}
+
+func TestTokenMetadata(t *testing.T) {
+	provider := &mockProvider{}
+	provider.setOutput(t, "tokenURI", map[string]interface{}{
+		"0": `data:application/json,{"name":"Token #1","image":"ipfs://image"}`,
+	})
+
+	erc721 := NewERC721(ethgo.Address{0x1}, contract.WithProvider(provider))
+
+	metadata, err := erc721.TokenMetadata(big.NewInt(1))
+	require.NoError(t, err)
+	require.Equal(t, "Token #1", metadata.Name)
+	require.Equal(t, "ipfs://image", metadata.Image)
+}
+
+func TestParseTransferLog(t *testing.T) {
+	from, to := ethgo.Address{0x1}, ethgo.Address{0x2}
+
+	topic := func(typ string, val interface{}) ethgo.Hash {
+		h, err := abi.EncodeTopic(abi.MustNewType(typ), val)
+		require.NoError(t, err)
+		return h
+	}
+
+	log := &ethgo.Log{
+		Topics: []ethgo.Hash{
+			abiERC721.Events["Transfer"].ID(),
+			topic("address", from),
+			topic("address", to),
+			topic("uint256", big.NewInt(10)),
+		},
+	}
+
+	event, err := ParseTransferLog(log)
+	require.NoError(t, err)
+	require.Equal(t, from, event.From)
+	require.Equal(t, to, event.To)
+	require.Equal(t, big.NewInt(10), event.TokenId)
+
+	// erc20 transfer log with the value in the data
+	log.Topics = log.Topics[:3]
+	log.Data = make([]byte, 32)
+
+	_, err = ParseTransferLog(log)
+	require.Error(t, err)
+
+	// another event
+	log.Topics[0] = abiERC721.Events["Approval"].ID()
+
+	_, err = ParseTransferLog(log)
+	require.Error(t, err)
+}
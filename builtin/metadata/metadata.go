@@ -0,0 +1,162 @@
+package metadata
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const (
+	// DefaultIPFSGateway is the gateway used to resolve ipfs:// uris
+	DefaultIPFSGateway = "https://ipfs.io/ipfs/"
+)
+
+// Metadata is the JSON metadata of a token as defined
+// by the ERC721 and ERC1155 metadata extensions
+type Metadata struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Image       string                 `json:"image"`
+	ExternalURL string                 `json:"external_url"`
+	Decimals    uint64                 `json:"decimals"`
+	Attributes  []*Attribute           `json:"attributes"`
+	Properties  map[string]interface{} `json:"properties"`
+
+	// Raw is the raw JSON document
+	Raw json.RawMessage `json:"-"`
+}
+
+// Attribute is a trait of the token
+type Attribute struct {
+	TraitType   string      `json:"trait_type"`
+	Value       interface{} `json:"value"`
+	DisplayType string      `json:"display_type,omitempty"`
+}
+
+// ReplaceID replaces the {id} placeholder in an ERC1155 uri with the id of the token
+// in lowercase hex format padded to 64 characters
+func ReplaceID(uri string, id *big.Int) string {
+	return strings.ReplaceAll(uri, "{id}", fmt.Sprintf("%064x", id))
+}
+
+// Fetcher retrieves the metadata of the tokens
+type Fetcher struct {
+	client      *http.Client
+	ipfsGateway string
+}
+
+// Option is an option to configure the fetcher
+type Option func(*Fetcher)
+
+// WithHTTPClient sets the http client used to fetch the metadata
+func WithHTTPClient(client *http.Client) Option {
+	return func(f *Fetcher) {
+		f.client = client
+	}
+}
+
+// WithIPFSGateway sets the gateway used to resolve ipfs:// uris
+func WithIPFSGateway(gateway string) Option {
+	return func(f *Fetcher) {
+		f.ipfsGateway = gateway
+	}
+}
+
+// NewFetcher creates a new metadata fetcher
+func NewFetcher(opts ...Option) *Fetcher {
+	f := &Fetcher{
+		client:      http.DefaultClient,
+		ipfsGateway: DefaultIPFSGateway,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	if !strings.HasSuffix(f.ipfsGateway, "/") {
+		f.ipfsGateway += "/"
+	}
+	return f
+}
+
+var defaultFetcher = NewFetcher()
+
+// Fetch retrieves the metadata from the uri with the default fetcher
+func Fetch(uri string) (*Metadata, error) {
+	return defaultFetcher.Fetch(uri)
+}
+
+// ResolveURI returns the http url of the uri. The ipfs:// uris
+// are resolved with the gateway of the fetcher.
+func (f *Fetcher) ResolveURI(uri string) string {
+	if !strings.HasPrefix(uri, "ipfs://") {
+		return uri
+	}
+	path := strings.TrimPrefix(uri, "ipfs://")
+	path = strings.TrimPrefix(path, "ipfs/")
+	return f.ipfsGateway + path
+}
+
+// Fetch retrieves the metadata from the uri. The uri can be either an http(s), ipfs or data uri.
+func (f *Fetcher) Fetch(uri string) (*Metadata, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if strings.HasPrefix(uri, "data:") {
+		data, err = decodeDataURI(uri)
+	} else {
+		data, err = f.get(f.ResolveURI(uri))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var metadata *Metadata
+	if err := json.Unmarshal(data, &metadata); err != nil {
+		return nil, fmt.Errorf("failed to decode metadata: %v", err)
+	}
+	if metadata == nil {
+		return nil, fmt.Errorf("empty metadata")
+	}
+	metadata.Raw = data
+	return metadata, nil
+}
+
+func (f *Fetcher) get(uri string) ([]byte, error) {
+	if !strings.HasPrefix(uri, "http://") && !strings.HasPrefix(uri, "https://") {
+		return nil, fmt.Errorf("uri scheme not supported: '%s'", uri)
+	}
+
+	resp, err := f.client.Get(uri)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch '%s': status code %d", uri, resp.StatusCode)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// decodeDataURI decodes a data uri (RFC 2397) with either base64 or url encoded data
+func decodeDataURI(uri string) ([]byte, error) {
+	indx := strings.Index(uri, ",")
+	if indx == -1 {
+		return nil, fmt.Errorf("data uri without data")
+	}
+	header, data := strings.TrimPrefix(uri[:indx], "data:"), uri[indx+1:]
+
+	if strings.HasSuffix(header, ";base64") {
+		return base64.StdEncoding.DecodeString(data)
+	}
+	unescaped, err := url.PathUnescape(data)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(unescaped), nil
+}
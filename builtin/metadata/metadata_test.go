@@ -0,0 +1,73 @@
+package metadata
+
+import (
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const exampleMetadata = `{"name": "Token", "description": "A token", "image": "ipfs://image", "attributes": [{"trait_type": "color", "value": "red"}]}`
+
+func TestReplaceID(t *testing.T) {
+	uri := ReplaceID("https://token-cdn-domain/{id}.json", big.NewInt(314592))
+	require.Equal(t, "https://token-cdn-domain/000000000000000000000000000000000000000000000000000000000004cce0.json", uri)
+
+	// uris without the placeholder do not change
+	require.Equal(t, "https://token-cdn-domain/1.json", ReplaceID("https://token-cdn-domain/1.json", big.NewInt(1)))
+}
+
+func TestResolveURI(t *testing.T) {
+	f := NewFetcher(WithIPFSGateway("https://gateway"))
+
+	require.Equal(t, "https://gateway/Qm/1.json", f.ResolveURI("ipfs://Qm/1.json"))
+	require.Equal(t, "https://gateway/Qm/1.json", f.ResolveURI("ipfs://ipfs/Qm/1.json"))
+	require.Equal(t, "https://a/1.json", f.ResolveURI("https://a/1.json"))
+}
+
+func TestFetch_DataURI(t *testing.T) {
+	cases := []string{
+		"data:application/json;base64," + base64.StdEncoding.EncodeToString([]byte(exampleMetadata)),
+		"data:application/json;utf8," + exampleMetadata,
+		"data:application/json," + "%7B%22name%22%3A%20%22Token%22%7D",
+	}
+	for _, c := range cases {
+		metadata, err := Fetch(c)
+		require.NoError(t, err)
+		require.Equal(t, "Token", metadata.Name)
+	}
+
+	_, err := Fetch("data:application/json;base64")
+	require.Error(t, err)
+}
+
+func TestFetch_HTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ipfs/Qm/1.json" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(exampleMetadata))
+	}))
+	defer srv.Close()
+
+	f := NewFetcher(WithIPFSGateway(srv.URL + "/ipfs"))
+
+	metadata, err := f.Fetch("ipfs://Qm/1.json")
+	require.NoError(t, err)
+
+	require.Equal(t, "Token", metadata.Name)
+	require.Equal(t, "A token", metadata.Description)
+	require.Equal(t, "ipfs://image", metadata.Image)
+	require.Equal(t, []*Attribute{{TraitType: "color", Value: "red"}}, metadata.Attributes)
+	require.JSONEq(t, exampleMetadata, string(metadata.Raw))
+
+	_, err = f.Fetch(srv.URL + "/2.json")
+	require.Error(t, err)
+
+	_, err = f.Fetch("ftp://a/1.json")
+	require.Error(t, err)
+}
@@ -0,0 +1,419 @@
+package permit2
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/contract"
+	"github.com/Ethernal-Tech/ethgo/signing"
+)
+
+// Address is the address of the Uniswap Permit2 contract. It is deployed
+// with the same address on all the chains.
+var Address = ethgo.HexToAddress("0x000000000022D473030F116dDEE9F6B43aC78BA3")
+
+// names of the overloaded methods in the abi
+const (
+	methodPermitBatch             = "permit"
+	methodPermitSingle            = "permit0"
+	methodPermitTransferFrom      = "permitTransferFrom"
+	methodPermitBatchTransferFrom = "permitTransferFrom0"
+	methodBatchTransferFrom       = "transferFrom"
+	methodTransferFrom            = "transferFrom0"
+)
+
+// Permit2 is a client for the Uniswap Permit2 contract
+type Permit2 struct {
+	c *contract.Contract
+}
+
+// NewPermit2 creates a new instance of the contract at a specific address
+func NewPermit2(addr ethgo.Address, opts ...contract.ContractOption) *Permit2 {
+	return &Permit2{c: contract.NewContract(addr, abiPermit2, opts...)}
+}
+
+// Domain returns the EIP-712 domain of the contract in the given chain
+func (p *Permit2) Domain(chainID *big.Int) *signing.EIP712Domain {
+	return NewDomain(chainID, p.c.Addr())
+}
+
+// NewDomain returns the EIP-712 domain of a Permit2 contract
+func NewDomain(chainID *big.Int, addr ethgo.Address) *signing.EIP712Domain {
+	return &signing.EIP712Domain{
+		Name:              "Permit2",
+		ChainId:           chainID,
+		VerifyingContract: addr.String(),
+	}
+}
+
+// DomainSeparator calls the DOMAIN_SEPARATOR method in the solidity contract
+func (p *Permit2) DomainSeparator(block ...ethgo.BlockNumber) (retval0 [32]byte, err error) {
+	var out map[string]interface{}
+	var ok bool
+
+	out, err = p.c.Call("DOMAIN_SEPARATOR", ethgo.EncodeBlock(block...))
+	if err != nil {
+		return
+	}
+
+	retval0, ok = out["0"].([32]byte)
+	if !ok {
+		err = fmt.Errorf("failed to encode output at index 0")
+		return
+	}
+	return
+}
+
+var (
+	tokenPermissionsType = []*signing.EIP712Type{
+		{Name: "token", Type: "address"},
+		{Name: "amount", Type: "uint256"},
+	}
+	permitDetailsType = []*signing.EIP712Type{
+		{Name: "token", Type: "address"},
+		{Name: "amount", Type: "uint160"},
+		{Name: "expiration", Type: "uint48"},
+		{Name: "nonce", Type: "uint48"},
+	}
+)
+
+// signature transfer
+
+// TokenPermissions is the token and amount allowed to be transferred
+type TokenPermissions struct {
+	Token  ethgo.Address `abi:"token"`
+	Amount *big.Int      `abi:"amount"`
+}
+
+func (t *TokenPermissions) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"token":  t.Token,
+		"amount": t.Amount,
+	}
+}
+
+// PermitTransferFrom is the signed message to transfer a single token with a signature
+type PermitTransferFrom struct {
+	Permitted *TokenPermissions `abi:"permitted"`
+	// Spender is the address allowed to use the signature. It is
+	// not part of the abi struct but it is included in the signed message.
+	Spender  ethgo.Address `abi:"-"`
+	Nonce    *big.Int      `abi:"nonce"`
+	Deadline *big.Int      `abi:"deadline"`
+}
+
+// TypedData returns the EIP-712 typed data of the message
+func (p *PermitTransferFrom) TypedData(domain *signing.EIP712Domain) *signing.EIP712TypedData {
+	return &signing.EIP712TypedData{
+		Types: map[string][]*signing.EIP712Type{
+			"PermitTransferFrom": {
+				{Name: "permitted", Type: "TokenPermissions"},
+				{Name: "spender", Type: "address"},
+				{Name: "nonce", Type: "uint256"},
+				{Name: "deadline", Type: "uint256"},
+			},
+			"TokenPermissions": tokenPermissionsType,
+		},
+		PrimaryType: "PermitTransferFrom",
+		Domain:      domain,
+		Message: map[string]interface{}{
+			"permitted": p.Permitted.toMap(),
+			"spender":   p.Spender,
+			"nonce":     p.Nonce,
+			"deadline":  p.Deadline,
+		},
+	}
+}
+
+// Sign signs the message with the key of the owner of the tokens
+func (p *PermitTransferFrom) Sign(key ethgo.Key, domain *signing.EIP712Domain) ([]byte, error) {
+	return p.TypedData(domain).SignTypedData(key)
+}
+
+// PermitBatchTransferFrom is the signed message to transfer multiple tokens with a signature
+type PermitBatchTransferFrom struct {
+	Permitted []*TokenPermissions `abi:"permitted"`
+	// Spender is the address allowed to use the signature. It is
+	// not part of the abi struct but it is included in the signed message.
+	Spender  ethgo.Address `abi:"-"`
+	Nonce    *big.Int      `abi:"nonce"`
+	Deadline *big.Int      `abi:"deadline"`
+}
+
+// TypedData returns the EIP-712 typed data of the message
+func (p *PermitBatchTransferFrom) TypedData(domain *signing.EIP712Domain) *signing.EIP712TypedData {
+	permitted := []interface{}{}
+	for _, i := range p.Permitted {
+		permitted = append(permitted, i.toMap())
+	}
+	return &signing.EIP712TypedData{
+		Types: map[string][]*signing.EIP712Type{
+			"PermitBatchTransferFrom": {
+				{Name: "permitted", Type: "TokenPermissions[]"},
+				{Name: "spender", Type: "address"},
+				{Name: "nonce", Type: "uint256"},
+				{Name: "deadline", Type: "uint256"},
+			},
+			"TokenPermissions": tokenPermissionsType,
+		},
+		PrimaryType: "PermitBatchTransferFrom",
+		Domain:      domain,
+		Message: map[string]interface{}{
+			"permitted": permitted,
+			"spender":   p.Spender,
+			"nonce":     p.Nonce,
+			"deadline":  p.Deadline,
+		},
+	}
+}
+
+// Sign signs the message with the key of the owner of the tokens
+func (p *PermitBatchTransferFrom) Sign(key ethgo.Key, domain *signing.EIP712Domain) ([]byte, error) {
+	return p.TypedData(domain).SignTypedData(key)
+}
+
+// SignatureTransferDetails is the receiver and amount of a signature transfer
+type SignatureTransferDetails struct {
+	To              ethgo.Address `abi:"to"`
+	RequestedAmount *big.Int      `abi:"requestedAmount"`
+}
+
+// PermitTransferFrom sends a permitTransferFrom transaction for a single token in the solidity contract
+func (p *Permit2) PermitTransferFrom(permit *PermitTransferFrom, transferDetails *SignatureTransferDetails, owner ethgo.Address, signature []byte) (contract.Txn, error) {
+	return p.c.Txn(methodPermitTransferFrom, permit, transferDetails, owner, signature)
+}
+
+// PermitBatchTransferFrom sends a permitTransferFrom transaction for multiple tokens in the solidity contract
+func (p *Permit2) PermitBatchTransferFrom(permit *PermitBatchTransferFrom, transferDetails []*SignatureTransferDetails, owner ethgo.Address, signature []byte) (contract.Txn, error) {
+	return p.c.Txn(methodPermitBatchTransferFrom, permit, transferDetails, owner, signature)
+}
+
+// NonceBitmap calls the nonceBitmap method in the solidity contract
+func (p *Permit2) NonceBitmap(owner ethgo.Address, wordPos *big.Int, block ...ethgo.BlockNumber) (retval0 *big.Int, err error) {
+	var out map[string]interface{}
+	var ok bool
+
+	out, err = p.c.Call("nonceBitmap", ethgo.EncodeBlock(block...), owner, wordPos)
+	if err != nil {
+		return
+	}
+
+	retval0, ok = out["0"].(*big.Int)
+	if !ok {
+		err = fmt.Errorf("failed to encode output at index 0")
+		return
+	}
+	return
+}
+
+// IsNonceUsed checks whether an unordered nonce of the owner has been used
+func (p *Permit2) IsNonceUsed(owner ethgo.Address, nonce *big.Int, block ...ethgo.BlockNumber) (bool, error) {
+	wordPos, bitPos := NoncePosition(nonce)
+
+	bitmap, err := p.NonceBitmap(owner, wordPos, block...)
+	if err != nil {
+		return false, err
+	}
+	return bitmap.Bit(int(bitPos)) == 1, nil
+}
+
+// NoncePosition returns the word and bit positions of an unordered nonce in the nonce bitmap
+func NoncePosition(nonce *big.Int) (*big.Int, uint8) {
+	wordPos := new(big.Int).Rsh(nonce, 8)
+	bitPos := uint8(new(big.Int).And(nonce, big.NewInt(0xff)).Uint64())
+	return wordPos, bitPos
+}
+
+// InvalidateUnorderedNonces sends a invalidateUnorderedNonces transaction in the solidity contract
+func (p *Permit2) InvalidateUnorderedNonces(wordPos *big.Int, mask *big.Int) (contract.Txn, error) {
+	return p.c.Txn("invalidateUnorderedNonces", wordPos, mask)
+}
+
+// allowance transfer
+
+// PermitDetails is the allowance of a token
+type PermitDetails struct {
+	Token      ethgo.Address `abi:"token"`
+	Amount     *big.Int      `abi:"amount"`
+	Expiration uint64        `abi:"expiration"`
+	Nonce      uint64        `abi:"nonce"`
+}
+
+func (p *PermitDetails) toMap() map[string]interface{} {
+	return map[string]interface{}{
+		"token":      p.Token,
+		"amount":     p.Amount,
+		"expiration": p.Expiration,
+		"nonce":      p.Nonce,
+	}
+}
+
+// PermitSingle is the signed message to set the allowance of a single token
+type PermitSingle struct {
+	Details     *PermitDetails `abi:"details"`
+	Spender     ethgo.Address  `abi:"spender"`
+	SigDeadline *big.Int       `abi:"sigDeadline"`
+}
+
+// TypedData returns the EIP-712 typed data of the message
+func (p *PermitSingle) TypedData(domain *signing.EIP712Domain) *signing.EIP712TypedData {
+	return &signing.EIP712TypedData{
+		Types: map[string][]*signing.EIP712Type{
+			"PermitSingle": {
+				{Name: "details", Type: "PermitDetails"},
+				{Name: "spender", Type: "address"},
+				{Name: "sigDeadline", Type: "uint256"},
+			},
+			"PermitDetails": permitDetailsType,
+		},
+		PrimaryType: "PermitSingle",
+		Domain:      domain,
+		Message: map[string]interface{}{
+			"details":     p.Details.toMap(),
+			"spender":     p.Spender,
+			"sigDeadline": p.SigDeadline,
+		},
+	}
+}
+
+// Sign signs the message with the key of the owner of the tokens
+func (p *PermitSingle) Sign(key ethgo.Key, domain *signing.EIP712Domain) ([]byte, error) {
+	return p.TypedData(domain).SignTypedData(key)
+}
+
+// PermitBatch is the signed message to set the allowance of multiple tokens
+type PermitBatch struct {
+	Details     []*PermitDetails `abi:"details"`
+	Spender     ethgo.Address    `abi:"spender"`
+	SigDeadline *big.Int         `abi:"sigDeadline"`
+}
+
+// TypedData returns the EIP-712 typed data of the message
+func (p *PermitBatch) TypedData(domain *signing.EIP712Domain) *signing.EIP712TypedData {
+	details := []interface{}{}
+	for _, i := range p.Details {
+		details = append(details, i.toMap())
+	}
+	return &signing.EIP712TypedData{
+		Types: map[string][]*signing.EIP712Type{
+			"PermitBatch": {
+				{Name: "details", Type: "PermitDetails[]"},
+				{Name: "spender", Type: "address"},
+				{Name: "sigDeadline", Type: "uint256"},
+			},
+			"PermitDetails": permitDetailsType,
+		},
+		PrimaryType: "PermitBatch",
+		Domain:      domain,
+		Message: map[string]interface{}{
+			"details":     details,
+			"spender":     p.Spender,
+			"sigDeadline": p.SigDeadline,
+		},
+	}
+}
+
+// Sign signs the message with the key of the owner of the tokens
+func (p *PermitBatch) Sign(key ethgo.Key, domain *signing.EIP712Domain) ([]byte, error) {
+	return p.TypedData(domain).SignTypedData(key)
+}
+
+// AllowanceTransferDetails is a transfer of tokens using an allowance
+type AllowanceTransferDetails struct {
+	From   ethgo.Address `abi:"from"`
+	To     ethgo.Address `abi:"to"`
+	Amount *big.Int      `abi:"amount"`
+	Token  ethgo.Address `abi:"token"`
+}
+
+// TokenSpenderPair is a token and spender pair to revoke an allowance
+type TokenSpenderPair struct {
+	Token   ethgo.Address `abi:"token"`
+	Spender ethgo.Address `abi:"spender"`
+}
+
+// Permit sends a permit transaction for a single token in the solidity contract
+func (p *Permit2) Permit(owner ethgo.Address, permit *PermitSingle, signature []byte) (contract.Txn, error) {
+	return p.c.Txn(methodPermitSingle, owner, permit, signature)
+}
+
+// PermitBatch sends a permit transaction for multiple tokens in the solidity contract
+func (p *Permit2) PermitBatch(owner ethgo.Address, permit *PermitBatch, signature []byte) (contract.Txn, error) {
+	return p.c.Txn(methodPermitBatch, owner, permit, signature)
+}
+
+// Allowance calls the allowance method in the solidity contract
+func (p *Permit2) Allowance(user ethgo.Address, token ethgo.Address, spender ethgo.Address, block ...ethgo.BlockNumber) (amount *big.Int, expiration uint64, nonce uint64, err error) {
+	var out map[string]interface{}
+
+	out, err = p.c.Call("allowance", ethgo.EncodeBlock(block...), user, token, spender)
+	if err != nil {
+		return
+	}
+
+	// uint48 values are decoded as big integers
+	var ok bool
+	var expirationBig, nonceBig *big.Int
+	if amount, ok = out["amount"].(*big.Int); !ok {
+		err = fmt.Errorf("failed to encode output at index 0")
+		return
+	}
+	if expirationBig, ok = out["expiration"].(*big.Int); !ok {
+		err = fmt.Errorf("failed to encode output at index 1")
+		return
+	}
+	if nonceBig, ok = out["nonce"].(*big.Int); !ok {
+		err = fmt.Errorf("failed to encode output at index 2")
+		return
+	}
+	expiration, nonce = expirationBig.Uint64(), nonceBig.Uint64()
+	return
+}
+
+// Approve sends a approve transaction in the solidity contract
+func (p *Permit2) Approve(token ethgo.Address, spender ethgo.Address, amount *big.Int, expiration uint64) (contract.Txn, error) {
+	return p.c.Txn("approve", token, spender, amount, expiration)
+}
+
+// TransferFrom sends a transferFrom transaction for a single token in the solidity contract
+func (p *Permit2) TransferFrom(from ethgo.Address, to ethgo.Address, amount *big.Int, token ethgo.Address) (contract.Txn, error) {
+	return p.c.Txn(methodTransferFrom, from, to, amount, token)
+}
+
+// BatchTransferFrom sends a transferFrom transaction for multiple tokens in the solidity contract
+func (p *Permit2) BatchTransferFrom(transferDetails []*AllowanceTransferDetails) (contract.Txn, error) {
+	return p.c.Txn(methodBatchTransferFrom, transferDetails)
+}
+
+// InvalidateNonces sends a invalidateNonces transaction in the solidity contract
+func (p *Permit2) InvalidateNonces(token ethgo.Address, spender ethgo.Address, newNonce uint64) (contract.Txn, error) {
+	return p.c.Txn("invalidateNonces", token, spender, newNonce)
+}
+
+// Lockdown sends a lockdown transaction in the solidity contract
+func (p *Permit2) Lockdown(approvals []*TokenSpenderPair) (contract.Txn, error) {
+	return p.c.Txn("lockdown", approvals)
+}
+
+// events
+
+func (p *Permit2) ApprovalEventSig() ethgo.Hash {
+	return p.c.GetABI().Events["Approval"].ID()
+}
+
+func (p *Permit2) LockdownEventSig() ethgo.Hash {
+	return p.c.GetABI().Events["Lockdown"].ID()
+}
+
+func (p *Permit2) NonceInvalidationEventSig() ethgo.Hash {
+	return p.c.GetABI().Events["NonceInvalidation"].ID()
+}
+
+func (p *Permit2) PermitEventSig() ethgo.Hash {
+	return p.c.GetABI().Events["Permit"].ID()
+}
+
+func (p *Permit2) UnorderedNonceInvalidationEventSig() ethgo.Hash {
+	return p.c.GetABI().Events["UnorderedNonceInvalidation"].ID()
+}
@@ -0,0 +1,251 @@
+package permit2
+
+import (
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/contract"
+	"github.com/Ethernal-Tech/ethgo/signing"
+	"github.com/Ethernal-Tech/ethgo/wallet"
+	"github.com/stretchr/testify/require"
+)
+
+type mockProvider struct {
+	output []byte
+	input  []byte
+}
+
+func (m *mockProvider) Call(addr ethgo.Address, input []byte, opts *contract.CallOpts) ([]byte, error) {
+	m.input = input
+	return m.output, nil
+}
+
+func (m *mockProvider) Txn(addr ethgo.Address, key ethgo.Key, input []byte) (contract.Txn, error) {
+	m.input = input
+	return nil, nil
+}
+
+func typeHash(typedData *signing.EIP712TypedData) string {
+	b := &signing.EIP712MessageBuilder[struct{}]{
+		Types:       typedData.Types,
+		PrimaryType: typedData.PrimaryType,
+	}
+	return hex.EncodeToString(ethgo.Keccak256([]byte(b.GetEncodedType())))
+}
+
+func TestPermit2_TypeHashes(t *testing.T) {
+	// type hashes defined in the PermitHash library of the Permit2 contract
+	cases := []struct {
+		typedData *signing.EIP712TypedData
+		hash      string
+	}{
+		{
+			(&PermitSingle{Details: &PermitDetails{}}).TypedData(nil),
+			"f3841cd1ff0085026a6327b620b67997ce40f282c88a8e905a7a5626e310f3d0",
+		},
+		{
+			(&PermitBatch{}).TypedData(nil),
+			"af1b0d30d2cab0380e68f0689007e3254993c596f2fdd0aaa7f4d04f79440863",
+		},
+		{
+			(&PermitTransferFrom{Permitted: &TokenPermissions{}}).TypedData(nil),
+			"939c21a48a8dbe3a9a2404a1d46691e4d39f6583d6ec6b35714604c986d80106",
+		},
+		{
+			(&PermitBatchTransferFrom{}).TypedData(nil),
+			"fcf35f5ac6a2c28868dc44c302166470266239195f02b0ee408334829333b766",
+		},
+	}
+
+	for _, c := range cases {
+		require.Equal(t, c.hash, typeHash(c.typedData))
+	}
+}
+
+func TestPermit2_OverloadedMethods(t *testing.T) {
+	sigs := map[string]string{
+		methodPermitBatch:             "permit(address,((address,uint160,uint48,uint48)[],address,uint256),bytes)",
+		methodPermitSingle:            "permit(address,((address,uint160,uint48,uint48),address,uint256),bytes)",
+		methodPermitTransferFrom:      "permitTransferFrom(((address,uint256),uint256,uint256),(address,uint256),address,bytes)",
+		methodPermitBatchTransferFrom: "permitTransferFrom(((address,uint256)[],uint256,uint256),(address,uint256)[],address,bytes)",
+		methodBatchTransferFrom:       "transferFrom((address,address,uint160,address)[])",
+		methodTransferFrom:            "transferFrom(address,address,uint160,address)",
+	}
+	for name, sig := range sigs {
+		require.Equal(t, sig, abiPermit2.GetMethod(name).Sig())
+	}
+}
+
+func TestPermit2_SignatureTransfer(t *testing.T) {
+	owner, err := wallet.GenerateKey()
+	require.NoError(t, err)
+	spender, err := wallet.GenerateKey()
+	require.NoError(t, err)
+
+	provider := &mockProvider{}
+	p := NewPermit2(Address, contract.WithProvider(provider), contract.WithSender(spender))
+
+	domain := p.Domain(big.NewInt(1))
+
+	permit := &PermitTransferFrom{
+		Permitted: &TokenPermissions{
+			Token:  ethgo.Address{0x1},
+			Amount: big.NewInt(100),
+		},
+		Spender:  spender.Address(),
+		Nonce:    big.NewInt(1),
+		Deadline: big.NewInt(1000),
+	}
+	sig, err := permit.Sign(owner, domain)
+	require.NoError(t, err)
+
+	addr, err := signing.RecoverTypedDataSigner(permit.TypedData(domain), sig)
+	require.NoError(t, err)
+	require.Equal(t, owner.Address(), addr)
+
+	details := &SignatureTransferDetails{
+		To:              ethgo.Address{0x2},
+		RequestedAmount: big.NewInt(50),
+	}
+	_, err = p.PermitTransferFrom(permit, details, owner.Address(), sig)
+	require.NoError(t, err)
+
+	method := abiPermit2.GetMethod(methodPermitTransferFrom)
+	require.Equal(t, method.ID(), provider.input[:4])
+
+	args, err := method.Inputs.Decode(provider.input[4:])
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"permit": map[string]interface{}{
+			"permitted": map[string]interface{}{
+				"token":  ethgo.Address{0x1},
+				"amount": big.NewInt(100),
+			},
+			"nonce":    big.NewInt(1),
+			"deadline": big.NewInt(1000),
+		},
+		"transferDetails": map[string]interface{}{
+			"to":              ethgo.Address{0x2},
+			"requestedAmount": big.NewInt(50),
+		},
+		"owner":     owner.Address(),
+		"signature": sig,
+	}, args)
+
+	// batch transfer
+	batch := &PermitBatchTransferFrom{
+		Permitted: []*TokenPermissions{
+			{Token: ethgo.Address{0x1}, Amount: big.NewInt(100)},
+			{Token: ethgo.Address{0x3}, Amount: big.NewInt(200)},
+		},
+		Spender:  spender.Address(),
+		Nonce:    big.NewInt(2),
+		Deadline: big.NewInt(1000),
+	}
+	sig, err = batch.Sign(owner, domain)
+	require.NoError(t, err)
+
+	addr, err = signing.RecoverTypedDataSigner(batch.TypedData(domain), sig)
+	require.NoError(t, err)
+	require.Equal(t, owner.Address(), addr)
+
+	_, err = p.PermitBatchTransferFrom(batch, []*SignatureTransferDetails{details, details}, owner.Address(), sig)
+	require.NoError(t, err)
+	require.Equal(t, abiPermit2.GetMethod(methodPermitBatchTransferFrom).ID(), provider.input[:4])
+}
+
+func TestPermit2_AllowanceTransfer(t *testing.T) {
+	owner, err := wallet.GenerateKey()
+	require.NoError(t, err)
+
+	provider := &mockProvider{}
+	p := NewPermit2(Address, contract.WithProvider(provider), contract.WithSender(owner))
+
+	domain := p.Domain(big.NewInt(1))
+
+	permit := &PermitSingle{
+		Details: &PermitDetails{
+			Token:      ethgo.Address{0x1},
+			Amount:     big.NewInt(100),
+			Expiration: 2000,
+			Nonce:      3,
+		},
+		Spender:     ethgo.Address{0x2},
+		SigDeadline: big.NewInt(1000),
+	}
+	sig, err := permit.Sign(owner, domain)
+	require.NoError(t, err)
+
+	addr, err := signing.RecoverTypedDataSigner(permit.TypedData(domain), sig)
+	require.NoError(t, err)
+	require.Equal(t, owner.Address(), addr)
+
+	_, err = p.Permit(owner.Address(), permit, sig)
+	require.NoError(t, err)
+
+	method := abiPermit2.GetMethod(methodPermitSingle)
+	require.Equal(t, method.ID(), provider.input[:4])
+
+	args, err := method.Inputs.Decode(provider.input[4:])
+	require.NoError(t, err)
+	require.Equal(t, map[string]interface{}{
+		"details": map[string]interface{}{
+			"token":      ethgo.Address{0x1},
+			"amount":     big.NewInt(100),
+			"expiration": big.NewInt(2000),
+			"nonce":      big.NewInt(3),
+		},
+		"spender":     ethgo.Address{0x2},
+		"sigDeadline": big.NewInt(1000),
+	}, args.(map[string]interface{})["permitSingle"])
+
+	// batch permit
+	batch := &PermitBatch{
+		Details:     []*PermitDetails{permit.Details, permit.Details},
+		Spender:     ethgo.Address{0x2},
+		SigDeadline: big.NewInt(1000),
+	}
+	sig, err = batch.Sign(owner, domain)
+	require.NoError(t, err)
+
+	addr, err = signing.RecoverTypedDataSigner(batch.TypedData(domain), sig)
+	require.NoError(t, err)
+	require.Equal(t, owner.Address(), addr)
+
+	_, err = p.PermitBatch(owner.Address(), batch, sig)
+	require.NoError(t, err)
+	require.Equal(t, abiPermit2.GetMethod(methodPermitBatch).ID(), provider.input[:4])
+
+	// allowance
+	provider.output, err = abiPermit2.GetMethod("allowance").Outputs.Encode([]interface{}{big.NewInt(100), 2000, 3})
+	require.NoError(t, err)
+
+	amount, expiration, nonce, err := p.Allowance(owner.Address(), ethgo.Address{0x1}, ethgo.Address{0x2})
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(100), amount)
+	require.Equal(t, uint64(2000), expiration)
+	require.Equal(t, uint64(3), nonce)
+}
+
+func TestPermit2_NonceBitmap(t *testing.T) {
+	wordPos, bitPos := NoncePosition(big.NewInt(258))
+	require.Equal(t, big.NewInt(1), wordPos)
+	require.Equal(t, uint8(2), bitPos)
+
+	provider := &mockProvider{}
+	p := NewPermit2(Address, contract.WithProvider(provider))
+
+	var err error
+	provider.output, err = abiPermit2.GetMethod("nonceBitmap").Outputs.Encode([]interface{}{big.NewInt(4)})
+	require.NoError(t, err)
+
+	used, err := p.IsNonceUsed(ethgo.Address{0x1}, big.NewInt(258))
+	require.NoError(t, err)
+	require.True(t, used)
+
+	used, err = p.IsNonceUsed(ethgo.Address{0x1}, big.NewInt(259))
+	require.NoError(t, err)
+	require.False(t, used)
+}
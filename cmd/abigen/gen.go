@@ -64,6 +64,9 @@ func encodeSimpleArg(typ *abi.Type) string {
 	case abi.KindUInt:
 		return typ.GoType().String()
 
+	case abi.KindFixedPoint:
+		return "*ethgo.Decimal"
+
 	case abi.KindFixedBytes:
 		return fmt.Sprintf("[%d]byte", typ.Size())
 
@@ -249,7 +252,7 @@ import (
 	"encoding/hex"
 	"fmt"
 
-	"github.com/umbracle/ethgo/abi"
+	"github.com/Ethernal-Tech/ethgo/abi"
 )
 
 var abi{{.Name}} *abi.ABI
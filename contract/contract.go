@@ -2,6 +2,7 @@ package contract
 
 import (
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
 
@@ -178,7 +179,7 @@ func (j *jsonrpcTransaction) Wait() (*ethgo.Receipt, error) {
 	for {
 		receipt, err := j.client.GetTransactionReceipt(j.hash)
 		if err != nil {
-			if err.Error() != "not found" {
+			if !errors.Is(err, jsonrpc.ErrNotFound) {
 				return nil, err
 			}
 		}
@@ -283,6 +284,11 @@ func (a *Contract) GetABI() *abi.ABI {
 	return a.abi
 }
 
+// Addr returns the address of the contract
+func (a *Contract) Addr() ethgo.Address {
+	return a.addr
+}
+
 type TxnOpts struct {
 	Value    *big.Int
 	GasPrice uint64
@@ -19,6 +19,10 @@ type endpoints struct {
 
 type Config struct {
 	headers map[string]string
+
+	recordPath string
+	replayPath string
+	replayOpts []transport.ReplayOption
 }
 
 type ConfigOption func(*Config)
@@ -31,6 +35,23 @@ func WithHeaders(headers map[string]string) ConfigOption {
 	}
 }
 
+// WithRecorder records the calls of the client and writes
+// them to the fixture file when the client is closed
+func WithRecorder(path string) ConfigOption {
+	return func(c *Config) {
+		c.recordPath = path
+	}
+}
+
+// WithReplay replies to the calls of the client with the fixtures
+// of the file (written with WithRecorder) instead of connecting to the endpoint
+func WithReplay(path string, opts ...transport.ReplayOption) ConfigOption {
+	return func(c *Config) {
+		c.replayPath = path
+		c.replayOpts = opts
+	}
+}
+
 func NewClient(addr string, opts ...ConfigOption) (*Client, error) {
 	config := &Config{headers: map[string]string{}}
 	for _, opt := range opts {
@@ -43,10 +64,22 @@ func NewClient(addr string, opts ...ConfigOption) (*Client, error) {
 	c.endpoints.n = &Net{c}
 	c.endpoints.d = &Debug{c}
 
+	if config.replayPath != "" {
+		t, err := transport.NewReplayer(config.replayPath, config.replayOpts...)
+		if err != nil {
+			return nil, err
+		}
+		c.transport = t
+		return c, nil
+	}
+
 	t, err := transport.NewTransport(addr, config.headers)
 	if err != nil {
 		return nil, err
 	}
+	if config.recordPath != "" {
+		t = transport.NewRecorder(t, config.recordPath)
+	}
 	c.transport = t
 	return c, nil
 }
@@ -0,0 +1,29 @@
+package jsonrpc
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ethernal-Tech/ethgo/jsonrpc/transport"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Replay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+	require.NoError(t, transport.WriteFixtures(path, []*transport.Fixture{
+		{Method: "eth_blockNumber", Result: json.RawMessage(`"0x10"`)},
+	}))
+
+	// the endpoint is not used when the calls are replayed
+	c, err := NewClient("http://127.0.0.1:1", WithReplay(path))
+	require.NoError(t, err)
+	defer c.Close()
+
+	num, err := c.Eth().BlockNumber()
+	require.NoError(t, err)
+	require.Equal(t, uint64(16), num)
+
+	_, err = c.Eth().ChainID()
+	require.Error(t, err)
+}
@@ -35,9 +35,12 @@ type Subscription struct {
 
 // Error implements error interface
 func (e *ErrorObject) Error() string {
-	data, err := json.Marshal(e)
+	if e.Data == nil {
+		return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
+	}
+	data, err := json.Marshal(e.Data)
 	if err != nil {
-		return fmt.Sprintf("jsonrpc.internal marshal error: %v", err)
+		return fmt.Sprintf("%s (code %d)", e.Message, e.Code)
 	}
-	return string(data)
+	return fmt.Sprintf("%s (code %d, data %s)", e.Message, e.Code, data)
 }
@@ -0,0 +1,303 @@
+package codec
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Error codes of the jsonrpc specification and EIP-1474
+const (
+	CodeParseError       = -32700
+	CodeInvalidRequest   = -32600
+	CodeMethodNotFound   = -32601
+	CodeInvalidParams    = -32602
+	CodeInternalError    = -32603
+	CodeInvalidInput     = -32000
+	CodeLimitExceeded    = -32005
+	CodeVMExecutionError = -32015
+
+	// CodeExecutionReverted is returned by geth and erigon in eth_call
+	// and eth_estimateGas when the execution reverts
+	CodeExecutionReverted = 3
+)
+
+// Errors returned by the nodes. The jsonrpc errors (ErrorObject) can be matched with
+// errors.Is against them regardless of the node implementation (geth, erigon, nethermind
+// or besu) since each of them uses different codes and messages for the same error.
+var (
+	// ErrExecutionReverted is returned when the execution of a call reverts.
+	// Use errors.As with a *RevertError to get the revert data.
+	ErrExecutionReverted = fmt.Errorf("execution reverted")
+
+	// ErrNonceTooLow is returned when the nonce of the transaction is lower than the account nonce
+	ErrNonceTooLow = fmt.Errorf("nonce too low")
+
+	// ErrNonceTooHigh is returned when the nonce of the transaction is too far in the future
+	ErrNonceTooHigh = fmt.Errorf("nonce too high")
+
+	// ErrInsufficientFunds is returned when the account cannot pay for the gas and value of the transaction
+	ErrInsufficientFunds = fmt.Errorf("insufficient funds")
+
+	// ErrReplacementUnderpriced is returned when a transaction with the same nonce
+	// is replaced without increasing the gas price enough
+	ErrReplacementUnderpriced = fmt.Errorf("replacement transaction underpriced")
+
+	// ErrAlreadyKnown is returned when the transaction is already in the pool
+	ErrAlreadyKnown = fmt.Errorf("already known")
+
+	// ErrRateLimited is returned when the node (or provider) limits the number of requests
+	ErrRateLimited = fmt.Errorf("rate limited")
+
+	// ErrMethodNotFound is returned when the method is not available in the node
+	ErrMethodNotFound = fmt.Errorf("method not found")
+
+	// ErrInvalidParams is returned when the node rejects the parameters of the
+	// request (i.e. a block tag that is not supported)
+	ErrInvalidParams = fmt.Errorf("invalid params")
+
+	// ErrHeaderNotFound is returned when the requested block is not available in the node
+	ErrHeaderNotFound = fmt.Errorf("header not found")
+
+	// ErrNotFound is returned when the requested object (i.e. a transaction) is not found
+	ErrNotFound = fmt.Errorf("not found")
+
+	// ErrTooManyResults is returned by eth_getLogs when the block range or the
+	// number of logs (or the size of the response) exceeds the limits of the node
+	ErrTooManyResults = fmt.Errorf("too many results")
+)
+
+// errorRule maps the codes and the (lowercase) message patterns of the nodes
+// to an error. The patterns with a '^' prefix only match the start of the message.
+// The error does not match if the rule of any of the excluded errors matches.
+type errorRule struct {
+	err      error
+	codes    []int
+	patterns []string
+	excludes []error
+}
+
+var errorRules = []*errorRule{
+	{
+		err:   ErrExecutionReverted,
+		codes: []int{CodeExecutionReverted},
+		patterns: []string{
+			"execution reverted", // geth, erigon, besu
+			"reverted",           // nethermind
+		},
+	},
+	{
+		err: ErrNonceTooLow,
+		patterns: []string{
+			"nonce too low", // geth, erigon, besu
+			"oldnonce",      // nethermind
+			"nonce_too_low", // besu
+		},
+	},
+	{
+		err: ErrNonceTooHigh,
+		patterns: []string{
+			"nonce too high",          // geth, erigon
+			"noncegap",                // nethermind
+			"nonce_too_high",          // besu
+			"nonce too far in future", // besu
+			"nonce_too_far_in_future_for_sender",
+		},
+	},
+	{
+		err: ErrInsufficientFunds,
+		patterns: []string{
+			"insufficient funds",           // geth, erigon, nethermind
+			"insufficientfunds",            // nethermind
+			"upfront cost exceeds",         // besu
+			"upfront_cost_exceeds_balance", // besu
+			"exceeds transaction sender account balance",
+		},
+	},
+	{
+		err: ErrReplacementUnderpriced,
+		patterns: []string{
+			"replacement transaction underpriced", // geth, erigon, besu
+			"replacement underpriced",
+			"replacement_underpriced", // besu
+			"feetoolowtocompete",      // nethermind
+		},
+	},
+	{
+		err: ErrAlreadyKnown,
+		patterns: []string{
+			"already known",      // geth
+			"alreadyknown",       // nethermind
+			"^known transaction", // besu, old geth
+			"known_transaction",  // besu
+			"already_exists",     // erigon
+			"already imported",
+		},
+	},
+	{
+		err:   ErrRateLimited,
+		codes: []int{CodeLimitExceeded, 429},
+		// infura uses the same code when eth_getLogs returns too many results
+		excludes: []error{ErrTooManyResults},
+		patterns: []string{
+			"rate limit",
+			"too many requests",
+			"request limit",
+		},
+	},
+	{
+		err:   ErrMethodNotFound,
+		codes: []int{CodeMethodNotFound},
+		patterns: []string{
+			"method not found",
+			"does not exist/is not available", // geth
+			"method not supported",
+		},
+	},
+	{
+		err:   ErrInvalidParams,
+		codes: []int{CodeInvalidParams},
+		// alchemy uses the same code when eth_getLogs returns too many results
+		excludes: []error{ErrTooManyResults},
+		patterns: []string{
+			"invalid argument", // geth, erigon
+			"invalid params",
+		},
+	},
+	{
+		err: ErrHeaderNotFound,
+		patterns: []string{
+			"header not found", // geth, erigon, nethermind
+			"block not found",  // besu, nethermind
+			"unknown block",
+		},
+	},
+	{
+		err: ErrNotFound,
+		patterns: []string{
+			"transaction not found",
+			"unknown transaction",
+		},
+	},
+	{
+		err: ErrTooManyResults,
+		patterns: []string{
+			"query returned more than",         // geth, infura
+			"query exceeds max results",        // erigon
+			"response is too big",              // erigon
+			"log response size exceeded",       // alchemy
+			"response size exceeded",           // alchemy, ankr
+			"response size should not greater", // bsc
+			"is limited to a",                  // quicknode
+			"exceed maximum block range",       // geth based chains
+			"block range is too large",         // nethermind
+			"block range too large",
+			"block range is too wide",
+			"range too large",
+			"too many logs",
+			"too many results",
+		},
+	},
+}
+
+// Is implements the errors.Is interface to match the jsonrpc error with
+// the generic errors of this package (i.e. ErrNonceTooLow).
+func (e *ErrorObject) Is(target error) bool {
+	for _, rule := range errorRules {
+		if rule.err == target {
+			return e.matches(rule) && !e.excluded(rule)
+		}
+	}
+	return false
+}
+
+func (e *ErrorObject) excluded(rule *errorRule) bool {
+	for _, err := range rule.excludes {
+		if e.Is(err) {
+			return true
+		}
+	}
+	return false
+}
+
+// As implements the errors.As interface to convert the jsonrpc error of a reverted execution to a *RevertError
+func (e *ErrorObject) As(target interface{}) bool {
+	revertErr, ok := target.(**RevertError)
+	if !ok || !e.Is(ErrExecutionReverted) {
+		return false
+	}
+	*revertErr = &RevertError{
+		Code:    e.Code,
+		Message: e.Message,
+		Data:    e.revertData(),
+	}
+	return true
+}
+
+func (e *ErrorObject) matches(rule *errorRule) bool {
+	for _, code := range rule.codes {
+		if e.Code == code {
+			return true
+		}
+	}
+
+	msg := strings.ToLower(e.Message)
+	if data, ok := e.Data.(string); ok && (e.Code == CodeVMExecutionError || e.Code == CodeInvalidInput) {
+		// nethermind and besu include the reason in the data
+		msg += " " + strings.ToLower(data)
+	}
+	for _, pattern := range rule.patterns {
+		if strings.HasPrefix(pattern, "^") {
+			if strings.HasPrefix(msg, pattern[1:]) {
+				return true
+			}
+		} else if strings.Contains(msg, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// revertData returns the revert data of the error. It is either an hex string
+// (geth, erigon and besu), an hex string with the 'Reverted ' prefix (nethermind)
+// or an object with a data field (hardhat and anvil).
+func (e *ErrorObject) revertData() []byte {
+	var str string
+	switch data := e.Data.(type) {
+	case string:
+		str = data
+	case map[string]interface{}:
+		str, _ = data["data"].(string)
+	}
+
+	str = strings.TrimSpace(str)
+	str = strings.TrimPrefix(str, "Reverted ")
+	if !strings.HasPrefix(str, "0x") {
+		return nil
+	}
+	buf, err := hex.DecodeString(str[2:])
+	if err != nil {
+		return nil
+	}
+	return buf
+}
+
+// RevertError is a reverted execution with the revert data (i.e. the encoded revert reason or custom error)
+type RevertError struct {
+	Code    int
+	Message string
+	Data    []byte
+}
+
+// Error implements the error interface
+func (r *RevertError) Error() string {
+	if len(r.Data) == 0 {
+		return r.Message
+	}
+	return fmt.Sprintf("%s (data 0x%s)", r.Message, hex.EncodeToString(r.Data))
+}
+
+// Is implements the errors.Is interface
+func (r *RevertError) Is(target error) bool {
+	return target == ErrExecutionReverted
+}
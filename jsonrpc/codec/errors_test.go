@@ -0,0 +1,95 @@
+package codec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorObject_Is(t *testing.T) {
+	cases := []struct {
+		err      string
+		expected error
+	}{
+		// geth
+		{`{"code": 3, "message": "execution reverted: not owner", "data": "0x08c379a0"}`, ErrExecutionReverted},
+		{`{"code": -32000, "message": "nonce too low: next nonce 5, tx nonce 3"}`, ErrNonceTooLow},
+		{`{"code": -32000, "message": "nonce too high"}`, ErrNonceTooHigh},
+		{`{"code": -32000, "message": "insufficient funds for gas * price + value: balance 0, tx cost 21000"}`, ErrInsufficientFunds},
+		{`{"code": -32000, "message": "replacement transaction underpriced"}`, ErrReplacementUnderpriced},
+		{`{"code": -32000, "message": "already known"}`, ErrAlreadyKnown},
+		{`{"code": -32601, "message": "the method eth_foo does not exist/is not available"}`, ErrMethodNotFound},
+		{`{"code": -32000, "message": "header not found"}`, ErrHeaderNotFound},
+		{`{"code": -32602, "message": "invalid argument 0: hex string without 0x prefix"}`, ErrInvalidParams},
+		// erigon
+		{`{"code": -32000, "message": "ALREADY_EXISTS: already known"}`, ErrAlreadyKnown},
+		// nethermind
+		{`{"code": -32015, "message": "VM execution error.", "data": "Reverted 0x08c379a0"}`, ErrExecutionReverted},
+		{`{"code": -32010, "message": "OldNonce"}`, ErrNonceTooLow},
+		{`{"code": -32010, "message": "InsufficientFunds, Balance is 0 less than sending value 1"}`, ErrInsufficientFunds},
+		{`{"code": -32010, "message": "AlreadyKnown"}`, ErrAlreadyKnown},
+		{`{"code": -32001, "message": "Block not found"}`, ErrHeaderNotFound},
+		// besu
+		{`{"code": -32000, "message": "Execution reverted", "data": "0x08c379a0"}`, ErrExecutionReverted},
+		{`{"code": -32001, "message": "Nonce too low"}`, ErrNonceTooLow},
+		{`{"code": -32004, "message": "Upfront cost exceeds account balance"}`, ErrInsufficientFunds},
+		{`{"code": -32000, "message": "Replacement transaction underpriced"}`, ErrReplacementUnderpriced},
+		{`{"code": -32000, "message": "Known transaction"}`, ErrAlreadyKnown},
+		// providers
+		{`{"code": -32005, "message": "daily request count exceeded"}`, ErrRateLimited},
+		{`{"code": 429, "message": "Too Many Requests"}`, ErrRateLimited},
+		{`{"code": -32000, "message": "unknown transaction"}`, ErrNotFound},
+		{`{"code": -32005, "message": "query returned more than 10000 results"}`, ErrTooManyResults},
+		{`{"code": -32602, "message": "Log response size exceeded. this block range should work: [0x1, 0x2]"}`, ErrTooManyResults},
+		{`{"code": -32614, "message": "eth_getLogs is limited to a 10,000 range"}`, ErrTooManyResults},
+		{`{"code": -32000, "message": "query exceeds max results 20000"}`, ErrTooManyResults},
+	}
+
+	for _, c := range cases {
+		var obj *ErrorObject
+		require.NoError(t, json.Unmarshal([]byte(c.err), &obj))
+
+		var err error = fmt.Errorf("wrapped: %w", obj)
+		require.True(t, errors.Is(err, c.expected), c.err)
+
+		// it does not match any other error
+		for _, rule := range errorRules {
+			if rule.err != c.expected {
+				require.False(t, errors.Is(err, rule.err), "%s matches %v", c.err, rule.err)
+			}
+		}
+	}
+}
+
+func TestErrorObject_RevertError(t *testing.T) {
+	cases := []string{
+		`{"code": 3, "message": "execution reverted", "data": "0x01020304"}`,
+		`{"code": -32015, "message": "VM execution error.", "data": "Reverted 0x01020304"}`,
+		`{"code": -32603, "message": "reverted", "data": {"message": "reverted", "data": "0x01020304"}}`,
+	}
+
+	for _, c := range cases {
+		var obj *ErrorObject
+		require.NoError(t, json.Unmarshal([]byte(c), &obj))
+
+		var revertErr *RevertError
+		require.True(t, errors.As(fmt.Errorf("wrapped: %w", obj), &revertErr))
+		require.Equal(t, []byte{0x1, 0x2, 0x3, 0x4}, revertErr.Data)
+		require.True(t, errors.Is(revertErr, ErrExecutionReverted))
+	}
+
+	// other errors are not revert errors
+	var revertErr *RevertError
+	require.False(t, errors.As(&ErrorObject{Code: -32000, Message: "nonce too low"}, &revertErr))
+}
+
+func TestErrorObject_Error(t *testing.T) {
+	obj := &ErrorObject{Code: -32000, Message: "nonce too low"}
+	require.Equal(t, "nonce too low (code -32000)", obj.Error())
+
+	obj = &ErrorObject{Code: 3, Message: "execution reverted", Data: "0x01"}
+	require.Equal(t, `execution reverted (code 3, data "0x01")`, obj.Error())
+}
@@ -43,3 +43,30 @@ func (d *Debug) TraceTransaction(hash ethgo.Hash, opts TraceTransactionOptions)
 	err := d.c.Call("debug_traceTransaction", &res, hash, opts)
 	return res, err
 }
+
+// CallTrace is a call frame of the callTracer
+type CallTrace struct {
+	Type    string          `json:"type"`
+	From    ethgo.Address   `json:"from"`
+	To      ethgo.Address   `json:"to"`
+	Value   *ethgo.ArgBig   `json:"value"`
+	Gas     ethgo.ArgUint64 `json:"gas"`
+	GasUsed ethgo.ArgUint64 `json:"gasUsed"`
+	Input   ethgo.ArgBytes  `json:"input"`
+	Output  ethgo.ArgBytes  `json:"output"`
+	Error   string          `json:"error"`
+	Calls   []*CallTrace    `json:"calls"`
+}
+
+// BlockTrace is the call trace of a transaction of a block
+type BlockTrace struct {
+	TxHash ethgo.Hash `json:"txHash"`
+	Result *CallTrace `json:"result"`
+}
+
+// TraceBlockByHash traces the calls of all the transactions of the block with the callTracer
+func (d *Debug) TraceBlockByHash(hash ethgo.Hash) ([]*BlockTrace, error) {
+	var res []*BlockTrace
+	err := d.c.Call("debug_traceBlockByHash", &res, hash, TraceTransactionOptions{Tracer: "callTracer"})
+	return res, err
+}
@@ -27,3 +27,26 @@ func TestDebug_TraceTransaction(t *testing.T) {
 	assert.Greater(t, trace.Gas, uint64(20000))
 	assert.NotEmpty(t, trace.StructLogs)
 }
+
+func TestDebug_TraceBlockByHash(t *testing.T) {
+	s := testutil.NewTestServer(t)
+	c, _ := NewClient(s.HTTPAddr())
+
+	cc := &testutil.Contract{}
+	cc.AddEvent(testutil.NewEvent("A").Add("address", true))
+	cc.EmitEvent("setA", "A", addr0.String())
+
+	_, addr, err := s.DeployContract(cc)
+	require.NoError(t, err)
+
+	r, err := s.TxnTo(addr, "setA2")
+	require.NoError(t, err)
+
+	traces, err := c.Debug().TraceBlockByHash(r.BlockHash)
+	require.NoError(t, err)
+	require.Len(t, traces, 1)
+
+	assert.Equal(t, r.TransactionHash, traces[0].TxHash)
+	assert.Equal(t, "CALL", traces[0].Result.Type)
+	assert.Equal(t, addr, traces[0].Result.To)
+}
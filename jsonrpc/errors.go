@@ -0,0 +1,22 @@
+package jsonrpc
+
+import "github.com/Ethernal-Tech/ethgo/jsonrpc/codec"
+
+// Errors returned by the nodes to use with errors.Is. See the codec package for the details.
+var (
+	ErrExecutionReverted      = codec.ErrExecutionReverted
+	ErrNonceTooLow            = codec.ErrNonceTooLow
+	ErrNonceTooHigh           = codec.ErrNonceTooHigh
+	ErrInsufficientFunds      = codec.ErrInsufficientFunds
+	ErrReplacementUnderpriced = codec.ErrReplacementUnderpriced
+	ErrAlreadyKnown           = codec.ErrAlreadyKnown
+	ErrRateLimited            = codec.ErrRateLimited
+	ErrMethodNotFound         = codec.ErrMethodNotFound
+	ErrInvalidParams          = codec.ErrInvalidParams
+	ErrHeaderNotFound         = codec.ErrHeaderNotFound
+	ErrNotFound               = codec.ErrNotFound
+	ErrTooManyResults         = codec.ErrTooManyResults
+)
+
+// RevertError is a reverted execution with the revert data. Use errors.As to get it from the error of a call.
+type RevertError = codec.RevertError
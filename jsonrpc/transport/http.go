@@ -68,7 +68,12 @@ func (h *HTTP) Call(method string, out interface{}, params ...interface{}) error
 	}
 
 	if sc := res.StatusCode(); sc != fasthttp.StatusOK {
-		return fmt.Errorf("status code is %d. response = %s", sc, string(res.Body()))
+		// some nodes and providers return the jsonrpc error with other status codes
+		var response codec.Response
+		if err := json.Unmarshal(res.Body(), &response); err == nil && response.Error != nil {
+			return response.Error
+		}
+		return &HTTPError{StatusCode: sc, Body: string(res.Body())}
 	}
 
 	// Decode json-rpc response
@@ -90,3 +95,20 @@ func (h *HTTP) Call(method string, out interface{}, params ...interface{}) error
 func (h *HTTP) SetMaxConnsPerHost(count int) {
 	h.client.MaxConnsPerHost = count
 }
+
+// HTTPError is a response with a status code other than 200 and without a jsonrpc error
+type HTTPError struct {
+	StatusCode int
+	Body       string
+}
+
+// Error implements the error interface
+func (h *HTTPError) Error() string {
+	return fmt.Sprintf("status code is %d. response = %s", h.StatusCode, h.Body)
+}
+
+// Is implements the errors.Is interface. It matches codec.ErrRateLimited
+// if the status code is 429 (too many requests).
+func (h *HTTPError) Is(target error) bool {
+	return target == codec.ErrRateLimited && h.StatusCode == fasthttp.StatusTooManyRequests
+}
@@ -0,0 +1,48 @@
+package transport
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ethernal-Tech/ethgo/jsonrpc/codec"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTP_Errors(t *testing.T) {
+	var (
+		status int
+		body   string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	h := newHTTP(srv.URL, nil)
+
+	// jsonrpc error
+	status, body = http.StatusOK, `{"jsonrpc": "2.0", "id": 0, "error": {"code": -32000, "message": "nonce too low"}}`
+	err := h.Call("eth_sendRawTransaction", nil)
+	require.True(t, errors.Is(err, codec.ErrNonceTooLow))
+
+	// jsonrpc error with other status code
+	status, body = http.StatusBadRequest, `{"jsonrpc": "2.0", "id": 0, "error": {"code": -32601, "message": "method not found"}}`
+	err = h.Call("eth_foo", nil)
+	require.True(t, errors.Is(err, codec.ErrMethodNotFound))
+
+	// rate limited by the provider
+	status, body = http.StatusTooManyRequests, "too many requests"
+	err = h.Call("eth_blockNumber", nil)
+	require.True(t, errors.Is(err, codec.ErrRateLimited))
+
+	var httpErr *HTTPError
+	require.True(t, errors.As(err, &httpErr))
+	require.Equal(t, http.StatusTooManyRequests, httpErr.StatusCode)
+
+	status, body = http.StatusInternalServerError, "internal error"
+	err = h.Call("eth_blockNumber", nil)
+	require.False(t, errors.Is(err, codec.ErrRateLimited))
+}
@@ -0,0 +1,337 @@
+package transport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/Ethernal-Tech/ethgo/jsonrpc/codec"
+)
+
+// Fixture is a recorded jsonrpc call with either the result or the error of the response
+type Fixture struct {
+	Method string             `json:"method"`
+	Params json.RawMessage    `json:"params,omitempty"`
+	Result json.RawMessage    `json:"result,omitempty"`
+	Error  *codec.ErrorObject `json:"error,omitempty"`
+}
+
+// ReadFixtures reads the fixtures of a file written by the Recorder
+func ReadFixtures(path string) ([]*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var fixtures []*Fixture
+	if err := json.Unmarshal(data, &fixtures); err != nil {
+		return nil, fmt.Errorf("failed to decode fixtures in %s: %v", path, err)
+	}
+	return fixtures, nil
+}
+
+// WriteFixtures writes the fixtures to a file
+func WriteFixtures(path string, fixtures []*Fixture) error {
+	if fixtures == nil {
+		fixtures = []*Fixture{}
+	}
+	data, err := json.MarshalIndent(fixtures, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+func encodeParams(params []interface{}) (json.RawMessage, error) {
+	if len(params) == 0 {
+		return nil, nil
+	}
+	return json.Marshal(params)
+}
+
+// Recorder is a transport that records the calls to another transport.
+// The fixtures are written to the file when the transport is closed.
+// Only the calls are recorded, subscriptions are not available.
+type Recorder struct {
+	transport Transport
+	path      string
+
+	lock     sync.Mutex
+	fixtures []*Fixture
+}
+
+// NewRecorder creates a transport that records the calls to the transport in the fixture file
+func NewRecorder(t Transport, path string) *Recorder {
+	return &Recorder{
+		transport: t,
+		path:      path,
+	}
+}
+
+// Call implements the transport interface
+func (r *Recorder) Call(method string, out interface{}, params ...interface{}) error {
+	rawParams, err := encodeParams(params)
+	if err != nil {
+		return err
+	}
+
+	var result json.RawMessage
+	err = r.transport.Call(method, &result, params...)
+
+	var errObj *codec.ErrorObject
+	if err != nil && !errors.As(err, &errObj) {
+		// transport errors are not part of the fixtures
+		return err
+	}
+
+	r.lock.Lock()
+	r.fixtures = append(r.fixtures, &Fixture{
+		Method: method,
+		Params: rawParams,
+		Result: result,
+		Error:  errObj,
+	})
+	r.lock.Unlock()
+
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(result, out)
+}
+
+// Fixtures returns the recorded fixtures
+func (r *Recorder) Fixtures() []*Fixture {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return append([]*Fixture{}, r.fixtures...)
+}
+
+// Save writes the recorded fixtures to the file
+func (r *Recorder) Save() error {
+	return WriteFixtures(r.path, r.Fixtures())
+}
+
+// SetMaxConnsPerHost implements the transport interface
+func (r *Recorder) SetMaxConnsPerHost(count int) {
+	r.transport.SetMaxConnsPerHost(count)
+}
+
+// Close implements the transport interface. It writes the fixtures to the file.
+func (r *Recorder) Close() error {
+	if err := r.Save(); err != nil {
+		return err
+	}
+	return r.transport.Close()
+}
+
+// Matcher checks whether a fixture matches a call
+type Matcher func(fixture *Fixture, method string, params json.RawMessage) bool
+
+// MatchMethod matches the fixtures with the same method regardless of the params
+func MatchMethod(fixture *Fixture, method string, params json.RawMessage) bool {
+	return fixture.Method == method
+}
+
+// MatchMethodAndParams matches the fixtures with the same method and params.
+// The params are compared by value so the json formatting does not matter.
+func MatchMethodAndParams(fixture *Fixture, method string, params json.RawMessage) bool {
+	return fixture.Method == method && equalJSON(fixture.Params, params)
+}
+
+func equalJSON(a, b json.RawMessage) bool {
+	var aObj, bObj interface{}
+	if len(a) != 0 {
+		if err := json.Unmarshal(a, &aObj); err != nil {
+			return false
+		}
+	}
+	if len(b) != 0 {
+		if err := json.Unmarshal(b, &bObj); err != nil {
+			return false
+		}
+	}
+	// no params is the same as an empty list
+	if list, ok := aObj.([]interface{}); ok && len(list) == 0 {
+		aObj = nil
+	}
+	if list, ok := bObj.([]interface{}); ok && len(list) == 0 {
+		bObj = nil
+	}
+	return reflect.DeepEqual(aObj, bObj)
+}
+
+// UnmatchedCallError is returned by the Replayer when there is no fixture for the call
+type UnmatchedCallError struct {
+	Method string
+	Params json.RawMessage
+
+	// Candidates are the params of the fixtures for the same method
+	Candidates []json.RawMessage
+}
+
+// Error implements the error interface
+func (u *UnmatchedCallError) Error() string {
+	msg := fmt.Sprintf("no fixture for call %s with params %s", u.Method, string(u.Params))
+	if len(u.Candidates) == 0 {
+		return msg + fmt.Sprintf(" (no fixtures for %s)", u.Method)
+	}
+	candidates := make([]string, len(u.Candidates))
+	for i, c := range u.Candidates {
+		candidates[i] = string(c)
+	}
+	return msg + fmt.Sprintf(" (unused fixtures for %s have params %s)", u.Method, strings.Join(candidates, ", "))
+}
+
+// ReplayOption is an option for the Replayer
+type ReplayOption func(*Replayer)
+
+// WithMatcher sets the function to match the fixtures with the calls (MatchMethodAndParams by default)
+func WithMatcher(matcher Matcher) ReplayOption {
+	return func(r *Replayer) {
+		r.matcher = matcher
+	}
+}
+
+// WithOrdered requires the calls to happen in the same order as the fixtures
+func WithOrdered() ReplayOption {
+	return func(r *Replayer) {
+		r.ordered = true
+	}
+}
+
+// WithRepeat reuses the last matching fixture once all the matching fixtures
+// have been used (i.e. to poll the same method more times than recorded)
+func WithRepeat() ReplayOption {
+	return func(r *Replayer) {
+		r.repeat = true
+	}
+}
+
+// Replayer is a transport that replies to the calls with recorded fixtures. Each
+// fixture is used once and in the order they were recorded among the matching ones.
+type Replayer struct {
+	matcher Matcher
+	ordered bool
+	repeat  bool
+
+	lock     sync.Mutex
+	fixtures []*Fixture
+	used     []bool
+	next     int
+}
+
+// NewReplayer creates a transport that replays the fixtures of the file
+func NewReplayer(path string, opts ...ReplayOption) (*Replayer, error) {
+	fixtures, err := ReadFixtures(path)
+	if err != nil {
+		return nil, err
+	}
+	return NewReplayerFromFixtures(fixtures, opts...), nil
+}
+
+// NewReplayerFromFixtures creates a transport that replays the fixtures
+func NewReplayerFromFixtures(fixtures []*Fixture, opts ...ReplayOption) *Replayer {
+	r := &Replayer{
+		matcher:  MatchMethodAndParams,
+		fixtures: fixtures,
+		used:     make([]bool, len(fixtures)),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Call implements the transport interface
+func (r *Replayer) Call(method string, out interface{}, params ...interface{}) error {
+	rawParams, err := encodeParams(params)
+	if err != nil {
+		return err
+	}
+
+	fixture, err := r.match(method, rawParams)
+	if err != nil {
+		return err
+	}
+	if fixture.Error != nil {
+		return fixture.Error
+	}
+	return json.Unmarshal(fixture.Result, out)
+}
+
+func (r *Replayer) match(method string, params json.RawMessage) (*Fixture, error) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.ordered {
+		if r.next < len(r.fixtures) && r.matcher(r.fixtures[r.next], method, params) {
+			r.used[r.next] = true
+			r.next++
+			return r.fixtures[r.next-1], nil
+		}
+		if r.repeat && r.next > 0 && r.matcher(r.fixtures[r.next-1], method, params) {
+			return r.fixtures[r.next-1], nil
+		}
+		if r.next < len(r.fixtures) {
+			next := r.fixtures[r.next]
+			return nil, fmt.Errorf("expected call %s with params %s (fixture %d) but found %s with params %s", next.Method, string(next.Params), r.next, method, string(params))
+		}
+		return nil, r.unmatched(method, params)
+	}
+
+	last := -1
+	for indx, fixture := range r.fixtures {
+		if !r.matcher(fixture, method, params) {
+			continue
+		}
+		if !r.used[indx] {
+			r.used[indx] = true
+			return fixture, nil
+		}
+		last = indx
+	}
+	if r.repeat && last != -1 {
+		return r.fixtures[last], nil
+	}
+	return nil, r.unmatched(method, params)
+}
+
+func (r *Replayer) unmatched(method string, params json.RawMessage) error {
+	err := &UnmatchedCallError{
+		Method: method,
+		Params: params,
+	}
+	for indx, fixture := range r.fixtures {
+		if fixture.Method == method && !r.used[indx] {
+			err.Candidates = append(err.Candidates, fixture.Params)
+		}
+	}
+	return err
+}
+
+// Unused returns the fixtures that have not been used by any call
+func (r *Replayer) Unused() []*Fixture {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	res := []*Fixture{}
+	for indx, fixture := range r.fixtures {
+		if !r.used[indx] {
+			res = append(res, fixture)
+		}
+	}
+	return res
+}
+
+// SetMaxConnsPerHost implements the transport interface
+func (r *Replayer) SetMaxConnsPerHost(count int) {
+}
+
+// Close implements the transport interface
+func (r *Replayer) Close() error {
+	return nil
+}
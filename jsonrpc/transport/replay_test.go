@@ -0,0 +1,111 @@
+package transport
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ethernal-Tech/ethgo/jsonrpc/codec"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplay_RecordAndReplay(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+
+		var req codec.Request
+		require.NoError(t, json.Unmarshal(data, &req))
+
+		switch req.Method {
+		case "eth_getBalance":
+			var params []string
+			require.NoError(t, json.Unmarshal(req.Params, &params))
+			w.Write([]byte(`{"jsonrpc": "2.0", "id": 0, "result": "` + params[1] + `"}`))
+		case "eth_sendRawTransaction":
+			w.Write([]byte(`{"jsonrpc": "2.0", "id": 0, "error": {"code": -32000, "message": "nonce too low"}}`))
+		default:
+			w.Write([]byte(`{"jsonrpc": "2.0", "id": 0, "result": "0x1"}`))
+		}
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "fixtures.json")
+	recorder := NewRecorder(newHTTP(srv.URL, nil), path)
+
+	var res string
+	require.NoError(t, recorder.Call("eth_blockNumber", &res))
+	require.NoError(t, recorder.Call("eth_getBalance", &res, "0x0000000000000000000000000000000000000001", "0x2"))
+	require.NoError(t, recorder.Call("eth_getBalance", &res, "0x0000000000000000000000000000000000000001", "0x3"))
+	require.True(t, errors.Is(recorder.Call("eth_sendRawTransaction", &res, "0x00"), codec.ErrNonceTooLow))
+	require.NoError(t, recorder.Close())
+
+	// the server is not used to replay the calls
+	srv.Close()
+
+	replayer, err := NewReplayer(path)
+	require.NoError(t, err)
+
+	require.NoError(t, replayer.Call("eth_getBalance", &res, "0x0000000000000000000000000000000000000001", "0x3"))
+	require.Equal(t, "0x3", res)
+
+	require.NoError(t, replayer.Call("eth_blockNumber", &res))
+	require.Equal(t, "0x1", res)
+
+	// recorded errors are replayed
+	require.True(t, errors.Is(replayer.Call("eth_sendRawTransaction", &res, "0x00"), codec.ErrNonceTooLow))
+
+	// unmatched params
+	err = replayer.Call("eth_getBalance", &res, "0x0000000000000000000000000000000000000001", "0x4")
+
+	var unmatchedErr *UnmatchedCallError
+	require.True(t, errors.As(err, &unmatchedErr))
+	require.Equal(t, "eth_getBalance", unmatchedErr.Method)
+	require.Len(t, unmatchedErr.Candidates, 1)
+	require.Contains(t, err.Error(), "0x2")
+
+	// each fixture is used once
+	err = replayer.Call("eth_blockNumber", &res)
+	require.True(t, errors.As(err, &unmatchedErr))
+
+	require.Len(t, replayer.Unused(), 1)
+}
+
+func TestReplay_Options(t *testing.T) {
+	fixtures := []*Fixture{
+		{Method: "eth_blockNumber", Result: json.RawMessage(`"0x1"`)},
+		{Method: "eth_chainId", Params: json.RawMessage(`[]`), Result: json.RawMessage(`"0x5"`)},
+		{Method: "eth_blockNumber", Result: json.RawMessage(`"0x2"`)},
+	}
+
+	var res string
+
+	t.Run("Repeat", func(t *testing.T) {
+		r := NewReplayerFromFixtures(fixtures, WithRepeat())
+
+		for _, expected := range []string{"0x1", "0x2", "0x2"} {
+			require.NoError(t, r.Call("eth_blockNumber", &res))
+			require.Equal(t, expected, res)
+		}
+	})
+
+	t.Run("Ordered", func(t *testing.T) {
+		r := NewReplayerFromFixtures(fixtures, WithOrdered())
+
+		require.Error(t, r.Call("eth_chainId", &res))
+
+		require.NoError(t, r.Call("eth_blockNumber", &res))
+		require.NoError(t, r.Call("eth_chainId", &res))
+		require.Equal(t, "0x5", res)
+	})
+
+	t.Run("MatchMethod", func(t *testing.T) {
+		r := NewReplayerFromFixtures(fixtures, WithMatcher(MatchMethod))
+
+		require.NoError(t, r.Call("eth_chainId", &res, "0x1"))
+		require.Equal(t, "0x5", res)
+	})
+}
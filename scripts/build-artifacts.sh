@@ -13,5 +13,20 @@ echo "--> Build ERC20"
 ERC20_ARTIFACTS=../builtin/erc20/artifacts
 go run main.go abigen --source ${ERC20_ARTIFACTS}/ERC20.abi --output ../builtin/erc20 --package erc20
 
+echo "--> Build ERC165"
+
+ERC165_ARTIFACTS=../builtin/erc165/artifacts
+go run main.go abigen --source ${ERC165_ARTIFACTS}/ERC165.abi --output ../builtin/erc165 --package erc165
+
+echo "--> Build ERC721"
+
+ERC721_ARTIFACTS=../builtin/erc721/artifacts
+go run main.go abigen --source ${ERC721_ARTIFACTS}/ERC721.abi --output ../builtin/erc721 --package erc721
+
+echo "--> Build ERC1155"
+
+ERC1155_ARTIFACTS=../builtin/erc1155/artifacts
+go run main.go abigen --source ${ERC1155_ARTIFACTS}/ERC1155.abi --output ../builtin/erc1155 --package erc1155
+
 echo "--> Build Testdata"
 go run main.go abigen --source ./abigen/testdata/testdata.abi --output ./abigen/testdata --package testdata
@@ -220,8 +220,8 @@ func sliceToArray(slice interface{}) interface{} {
 }
 
 type EIP712Type struct {
-	Name string
-	Type string
+	Name string `json:"name"`
+	Type string `json:"type"`
 }
 
 type EIP712TypedData struct {
@@ -232,7 +232,21 @@ type EIP712TypedData struct {
 }
 
 func (t *EIP712TypedData) Hash() ([]byte, error) {
-	a, err := t.Domain.hashStruct()
+	if t.Domain == nil {
+		return nil, fmt.Errorf("domain not found")
+	}
+
+	var (
+		a   []byte
+		err error
+	)
+	if domainTypes, ok := t.Types["EIP712Domain"]; ok {
+		// the typed data declares the fields of the domain (i.e. eth_signTypedData_v4),
+		// use those instead of the ones inferred from the non-empty values
+		a, err = hashStruct("EIP712Domain", map[string][]*EIP712Type{"EIP712Domain": domainTypes}, t.Domain.toMap())
+	} else {
+		a, err = t.Domain.hashStruct()
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -276,6 +290,11 @@ func hashStruct(primary string, types map[string][]*EIP712Type, data map[string]
 	return result, nil
 }
 
+// Hash returns the domain separator
+func (e *EIP712Domain) Hash() ([]byte, error) {
+	return e.hashStruct()
+}
+
 func (e *EIP712Domain) hashStruct() ([]byte, error) {
 	a1, a2 := e.getObjs()
 
@@ -314,6 +333,20 @@ func (e *EIP712Domain) getObjs() ([]*EIP712Type, map[string]interface{}) {
 	return types, data
 }
 
+// toMap returns all the values of the domain that are set
+func (e *EIP712Domain) toMap() map[string]interface{} {
+	data := map[string]interface{}{
+		"name":              e.Name,
+		"version":           e.Version,
+		"verifyingContract": e.VerifyingContract,
+		"salt":              e.Salt,
+	}
+	if e.ChainId != nil {
+		data["chainId"] = e.ChainId
+	}
+	return data
+}
+
 func encodeData(primary string, types map[string][]*EIP712Type, data map[string]interface{}) ([]byte, error) {
 	fields := types[primary]
 
@@ -343,6 +376,9 @@ func encodeItem(typ string, types map[string][]*EIP712Type, val interface{}) ([]
 
 		var subElem []byte
 		v := reflect.ValueOf(val)
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			return nil, fmt.Errorf("array type '%s' not found", typ)
+		}
 
 		for i := 0; i < v.Len(); i++ {
 			elemRes, err := encodeItem(subType, types, v.Index(i).Interface())
@@ -355,8 +391,12 @@ func encodeItem(typ string, types map[string][]*EIP712Type, val interface{}) ([]
 
 	} else if _, ok := types[typ]; ok {
 		// if the item is a struct, handle it
+		obj, ok := val.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("struct type '%s' not found", typ)
+		}
 		var err error
-		if res, err = hashStruct(typ, types, val.(map[string]interface{})); err != nil {
+		if res, err = hashStruct(typ, types, obj); err != nil {
 			return nil, err
 		}
 	} else if typ == "string" {
@@ -377,6 +417,8 @@ func encodeItem(typ string, types map[string][]*EIP712Type, val interface{}) ([]
 			res = ethgo.Keccak256(valBytes)
 		} else if valBytes, ok := val.([]byte); ok {
 			res = ethgo.Keccak256(valBytes)
+		} else {
+			return nil, fmt.Errorf("bytes type not found")
 		}
 	} else {
 		// encode basic item
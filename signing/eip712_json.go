@@ -0,0 +1,168 @@
+package signing
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/wallet"
+)
+
+// NewEIP712TypedDataFromJSON parses the typed data in the format
+// used by the eth_signTypedData_v4 endpoint
+func NewEIP712TypedDataFromJSON(data []byte) (*EIP712TypedData, error) {
+	var typedData *EIP712TypedData
+	if err := json.Unmarshal(data, &typedData); err != nil {
+		return nil, err
+	}
+	if typedData == nil {
+		return nil, fmt.Errorf("empty typed data")
+	}
+	return typedData, nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface
+func (t *EIP712TypedData) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Types       map[string][]*EIP712Type `json:"types"`
+		PrimaryType string                   `json:"primaryType"`
+		Domain      *EIP712Domain            `json:"domain"`
+		Message     json.RawMessage          `json:"message"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	if raw.PrimaryType == "" {
+		return fmt.Errorf("primary type not found")
+	}
+	if _, ok := raw.Types[raw.PrimaryType]; !ok {
+		return fmt.Errorf("type for primary type '%s' not found", raw.PrimaryType)
+	}
+	if raw.Domain == nil {
+		return fmt.Errorf("domain not found")
+	}
+
+	// decode the numbers as json.Number to avoid losing precision
+	// with big integers (i.e. uint256)
+	var message map[string]interface{}
+	dec := json.NewDecoder(bytes.NewReader(raw.Message))
+	dec.UseNumber()
+	if err := dec.Decode(&message); err != nil {
+		return fmt.Errorf("failed to decode message: %v", err)
+	}
+
+	t.Types = raw.Types
+	t.PrimaryType = raw.PrimaryType
+	t.Domain = raw.Domain
+	t.Message = message
+	return nil
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface. The chain id can be either
+// a number, a decimal or hex string and the salt is an hex string.
+func (e *EIP712Domain) UnmarshalJSON(data []byte) error {
+	var raw struct {
+		Name              string          `json:"name"`
+		Version           string          `json:"version"`
+		VerifyingContract string          `json:"verifyingContract"`
+		ChainId           json.RawMessage `json:"chainId"`
+		Salt              string          `json:"salt"`
+	}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	e.Name = raw.Name
+	e.Version = raw.Version
+	e.VerifyingContract = raw.VerifyingContract
+
+	if chainIdStr := strings.Trim(string(raw.ChainId), `"`); chainIdStr != "" && chainIdStr != "null" {
+		chainId, ok := new(big.Int).SetString(chainIdStr, 0)
+		if !ok {
+			return fmt.Errorf("failed to decode chain id '%s'", chainIdStr)
+		}
+		e.ChainId = chainId
+	}
+
+	if raw.Salt != "" {
+		salt, err := decodeHexString(raw.Salt)
+		if err != nil {
+			return fmt.Errorf("failed to decode salt: %v", err)
+		}
+		if len(salt) > 32 {
+			return fmt.Errorf("salt is larger than 32 bytes")
+		}
+		e.Salt = salt
+	}
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface
+func (e *EIP712Domain) MarshalJSON() ([]byte, error) {
+	raw := map[string]interface{}{}
+
+	if len(e.Name) != 0 {
+		raw["name"] = e.Name
+	}
+	if len(e.Version) != 0 {
+		raw["version"] = e.Version
+	}
+	if e.ChainId != nil {
+		raw["chainId"] = json.Number(e.ChainId.String())
+	}
+	if len(e.VerifyingContract) != 0 {
+		raw["verifyingContract"] = e.VerifyingContract
+	}
+	if len(e.Salt) != 0 {
+		raw["salt"] = "0x" + hex.EncodeToString(e.Salt)
+	}
+	return json.Marshal(raw)
+}
+
+// SignTypedData signs the hash of the typed data with the key. It returns
+// a 65 bytes signature in the [R || S || V] format where V is 27 or 28
+// as returned by eth_signTypedData_v4.
+func (t *EIP712TypedData) SignTypedData(key ethgo.Key) ([]byte, error) {
+	hash, err := t.Hash()
+	if err != nil {
+		return nil, err
+	}
+	sig, err := key.Sign(hash)
+	if err != nil {
+		return nil, err
+	}
+	if len(sig) != 65 {
+		return nil, fmt.Errorf("incorrect signature length %d", len(sig))
+	}
+	sig[64] += 27
+	return sig, nil
+}
+
+// RecoverTypedDataSigner returns the address that signed the typed data. The recovery
+// byte of the signature can be either 0/1 or 27/28.
+func RecoverTypedDataSigner(typedData *EIP712TypedData, signature []byte) (ethgo.Address, error) {
+	if len(signature) != 65 {
+		return ethgo.Address{}, fmt.Errorf("incorrect signature length %d", len(signature))
+	}
+
+	sig := make([]byte, 65)
+	copy(sig, signature)
+
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	if sig[64] > 1 {
+		return ethgo.Address{}, fmt.Errorf("incorrect recovery id %d", signature[64])
+	}
+
+	hash, err := typedData.Hash()
+	if err != nil {
+		return ethgo.Address{}, err
+	}
+	return wallet.Ecrecover(hash, sig)
+}
@@ -1,10 +1,13 @@
 package signing
 
 import (
+	"encoding/hex"
+	"encoding/json"
 	"math/big"
 	"testing"
 
 	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/wallet"
 	"github.com/stretchr/testify/require"
 )
 
@@ -68,3 +71,256 @@ func TestBuildMessage_BasicTypes(t *testing.T) {
 	b := NewEIP712MessageBuilder[Message](domain)
 	require.Equal(t, "Message(uint64 A,uint32 B,uint16 C,uint8 D,[32]byte E,string F)", b.GetEncodedType())
 }
+
+const mailTypedDataV4 = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallets", "type": "address[]"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person[]"},
+			{"name": "contents", "type": "string"}
+		],
+		"Group": [
+			{"name": "name", "type": "string"},
+			{"name": "members", "type": "Person[]"}
+		]
+	},
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"primaryType": "Mail",
+	"message": {
+		"from": {
+			"name": "Cow",
+			"wallets": [
+				"0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826",
+				"0xDeaDbeefdEAdbeefdEadbEEFdeadbeEFdEaDbeeF"
+			]
+		},
+		"to": [
+			{
+				"name": "Bob",
+				"wallets": [
+					"0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB",
+					"0xB0BdaBea57B0BDABeA57b0bdABEA57b0BDabEa57",
+					"0xB0B0b0b0b0b0B000000000000000000000000000"
+				]
+			}
+		],
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func TestTypedDataJSON_SignAndRecover(t *testing.T) {
+	typedData, err := NewEIP712TypedDataFromJSON([]byte(mailTypedDataV4))
+	require.NoError(t, err)
+
+	hash, err := typedData.Hash()
+	require.NoError(t, err)
+	require.Equal(t, "0xa85c2e2b118698e88db68a8105b794a8cc7cec074e89ef991cb4f5f533819cc2", "0x"+hex.EncodeToString(hash))
+
+	// private key is keccak256('cow')
+	priv, err := wallet.NewWalletFromPrivKey(ethgo.Keccak256([]byte("cow")))
+	require.NoError(t, err)
+	require.Equal(t, ethgo.HexToAddress("0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"), priv.Address())
+
+	sig, err := typedData.SignTypedData(priv)
+	require.NoError(t, err)
+	require.Equal(t, "0x65cbd956f2fae28a601bebc9b906cea0191744bd4c4247bcd27cd08f8eb6b71c78efdf7a31dc9abee78f492292721f362d296cf86b4538e07b51303b67f749061b", "0x"+hex.EncodeToString(sig))
+
+	addr, err := RecoverTypedDataSigner(typedData, sig)
+	require.NoError(t, err)
+	require.Equal(t, priv.Address(), addr)
+
+	// the recovery id can also be in the 0/1 format
+	sig[64] -= 27
+	addr, err = RecoverTypedDataSigner(typedData, sig)
+	require.NoError(t, err)
+	require.Equal(t, priv.Address(), addr)
+
+	_, err = RecoverTypedDataSigner(typedData, sig[:64])
+	require.Error(t, err)
+}
+
+func TestTypedDataJSON_Mail(t *testing.T) {
+	// example from the EIP-712 specification
+	typedData, err := NewEIP712TypedDataFromJSON([]byte(`{
+		"types": {
+			"EIP712Domain": [
+				{"name": "name", "type": "string"},
+				{"name": "version", "type": "string"},
+				{"name": "chainId", "type": "uint256"},
+				{"name": "verifyingContract", "type": "address"}
+			],
+			"Person": [
+				{"name": "name", "type": "string"},
+				{"name": "wallet", "type": "address"}
+			],
+			"Mail": [
+				{"name": "from", "type": "Person"},
+				{"name": "to", "type": "Person"},
+				{"name": "contents", "type": "string"}
+			]
+		},
+		"primaryType": "Mail",
+		"domain": {
+			"name": "Ether Mail",
+			"version": "1",
+			"chainId": "0x1",
+			"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+		},
+		"message": {
+			"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+			"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+			"contents": "Hello, Bob!"
+		}
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1), typedData.Domain.ChainId)
+
+	hash, err := typedData.Hash()
+	require.NoError(t, err)
+	require.Equal(t, "0xbe609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2", "0x"+hex.EncodeToString(hash))
+}
+
+func TestTypedDataJSON_DomainSalt(t *testing.T) {
+	salt := "0xf2d857f4a3edcb9b78b4d503bfe733db1e3f6cdc2b7971ee739626c97e86a558"
+
+	typedData, err := NewEIP712TypedDataFromJSON([]byte(`{
+		"types": {
+			"EIP712Domain": [
+				{"name": "name", "type": "string"},
+				{"name": "version", "type": "string"},
+				{"name": "chainId", "type": "uint256"},
+				{"name": "verifyingContract", "type": "address"},
+				{"name": "salt", "type": "bytes32"}
+			],
+			"Message": [
+				{"name": "a", "type": "uint256"}
+			]
+		},
+		"primaryType": "Message",
+		"domain": {
+			"name": "name",
+			"version": "1",
+			"chainId": "5",
+			"verifyingContract": "0x1C56346CD2A2Bf3202F771f50d3D14a367B48070",
+			"salt": "` + salt + `"
+		},
+		"message": {
+			"a": 115792089237316195423570985008687907853269984665640564039457584007913129639935
+		}
+	}`))
+	require.NoError(t, err)
+	require.Equal(t, salt, "0x"+hex.EncodeToString(typedData.Domain.Salt))
+
+	// the domain declared in the types is the same as the one
+	// derived from the non-empty values
+	expected := &EIP712TypedData{
+		Types:       map[string][]*EIP712Type{"Message": typedData.Types["Message"]},
+		PrimaryType: typedData.PrimaryType,
+		Domain:      typedData.Domain,
+		Message: map[string]interface{}{
+			"a": new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1)),
+		},
+	}
+
+	hash1, err := typedData.Hash()
+	require.NoError(t, err)
+
+	hash2, err := expected.Hash()
+	require.NoError(t, err)
+	require.Equal(t, hash1, hash2)
+
+	// the domain can be encoded back to json
+	data, err := json.Marshal(typedData.Domain)
+	require.NoError(t, err)
+
+	domain := &EIP712Domain{}
+	require.NoError(t, json.Unmarshal(data, domain))
+	require.Equal(t, typedData.Domain, domain)
+}
+
+func TestTypedDataJSON_NestedArrays(t *testing.T) {
+	typedData, err := NewEIP712TypedDataFromJSON([]byte(`{
+		"types": {
+			"EIP712Domain": [
+				{"name": "name", "type": "string"}
+			],
+			"Message": [
+				{"name": "a", "type": "uint8[][]"}
+			]
+		},
+		"primaryType": "Message",
+		"domain": {
+			"name": "name"
+		},
+		"message": {
+			"a": [[1, 2], [3]]
+		}
+	}`))
+	require.NoError(t, err)
+
+	word := func(i byte) []byte {
+		buf := make([]byte, 32)
+		buf[31] = i
+		return buf
+	}
+
+	// each nested array is hashed as the concatenation of the hashes of its elements
+	inner1 := ethgo.Keccak256(word(1), word(2))
+	inner2 := ethgo.Keccak256(word(3))
+
+	typeHash := ethgo.Keccak256([]byte("Message(uint8[][] a)"))
+	structHash := ethgo.Keccak256(typeHash, ethgo.Keccak256(inner1, inner2))
+
+	domainHash := ethgo.Keccak256(ethgo.Keccak256([]byte("EIP712Domain(string name)")), ethgo.Keccak256([]byte("name")))
+
+	hash, err := typedData.Hash()
+	require.NoError(t, err)
+	require.Equal(t, ethgo.Keccak256([]byte{0x19, 0x1}, domainHash, structHash), hash)
+}
+
+func TestTypedDataJSON_Errors(t *testing.T) {
+	cases := []string{
+		// primary type not found
+		`{"types": {}, "primaryType": "Message", "domain": {}, "message": {}}`,
+		// domain not found
+		`{"types": {"Message": []}, "primaryType": "Message", "message": {}}`,
+		// incorrect chain id
+		`{"types": {"Message": []}, "primaryType": "Message", "domain": {"chainId": "a"}, "message": {}}`,
+		// incorrect salt
+		`{"types": {"Message": []}, "primaryType": "Message", "domain": {"salt": "abcd"}, "message": {}}`,
+	}
+	for _, c := range cases {
+		_, err := NewEIP712TypedDataFromJSON([]byte(c))
+		require.Error(t, err)
+	}
+
+	// struct type expected
+	typedData, err := NewEIP712TypedDataFromJSON([]byte(`{
+		"types": {
+			"Person": [{"name": "name", "type": "string"}],
+			"Message": [{"name": "a", "type": "Person"}]
+		},
+		"primaryType": "Message",
+		"domain": {"name": "name"},
+		"message": {"a": "b"}
+	}`))
+	require.NoError(t, err)
+
+	_, err = typedData.Hash()
+	require.Error(t, err)
+}
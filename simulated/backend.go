@@ -0,0 +1,1001 @@
+package simulated
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/jsonrpc/codec"
+	"github.com/Ethernal-Tech/ethgo/wallet"
+)
+
+const (
+	// DefaultGasLimit is the gas limit of the blocks
+	DefaultGasLimit = uint64(30000000)
+
+	// DefaultChainID is the chain id of the backend
+	DefaultChainID = uint64(1337)
+)
+
+var (
+	// DefaultBaseFee is the base fee of the blocks (1 gwei)
+	DefaultBaseFee = big.NewInt(1000000000)
+
+	// defaultPriorityFee is the tip suggested by the backend (1 gwei)
+	defaultPriorityFee = big.NewInt(1000000000)
+)
+
+// Config is the configuration of the simulated backend
+type Config struct {
+	ChainID  uint64
+	GasLimit uint64
+	BaseFee  *big.Int
+	Coinbase ethgo.Address
+	Alloc    map[ethgo.Address]*big.Int
+	AutoMine bool
+
+	// FinalityDepth is the number of blocks behind the head of the finalized block
+	FinalityDepth uint64
+}
+
+// ConfigOption is an option to modify the configuration
+type ConfigOption func(*Config)
+
+// WithChainID sets the chain id of the backend
+func WithChainID(chainID uint64) ConfigOption {
+	return func(c *Config) {
+		c.ChainID = chainID
+	}
+}
+
+// WithGasLimit sets the gas limit of the blocks
+func WithGasLimit(gasLimit uint64) ConfigOption {
+	return func(c *Config) {
+		c.GasLimit = gasLimit
+	}
+}
+
+// WithBaseFee sets the base fee of the blocks. The base fee does not change between blocks.
+func WithBaseFee(baseFee *big.Int) ConfigOption {
+	return func(c *Config) {
+		c.BaseFee = baseFee
+	}
+}
+
+// WithCoinbase sets the address that receives the fees of the blocks
+func WithCoinbase(coinbase ethgo.Address) ConfigOption {
+	return func(c *Config) {
+		c.Coinbase = coinbase
+	}
+}
+
+// WithBalance funds the account in the genesis
+func WithBalance(addr ethgo.Address, balance *big.Int) ConfigOption {
+	return func(c *Config) {
+		c.Alloc[addr] = balance
+	}
+}
+
+// WithManualMining disables the mining of a block for each transaction.
+// The transactions are kept as pending until Commit is called.
+func WithManualMining() ConfigOption {
+	return func(c *Config) {
+		c.AutoMine = false
+	}
+}
+
+// WithFinalityDepth sets the number of blocks between the head and the finalized
+// block. The safe block is half way. By default, all the blocks are final.
+func WithFinalityDepth(depth uint64) ConfigOption {
+	return func(c *Config) {
+		c.FinalityDepth = depth
+	}
+}
+
+type pendingTxn struct {
+	txn  *ethgo.Transaction
+	from ethgo.Address
+}
+
+// chain is the data of the chain. The blocks and states are not modified once they are
+// added so the snapshots of the chain only need to copy the slices and maps.
+type chain struct {
+	blocks     []*ethgo.Block
+	states     []*state
+	logs       [][]*ethgo.Log
+	hashes     map[ethgo.Hash]uint64
+	receipts   map[ethgo.Hash]*ethgo.Receipt
+	txns       map[ethgo.Hash]*ethgo.Transaction
+	pending    []*pendingTxn
+	timeOffset uint64
+}
+
+func (c *chain) copy() *chain {
+	cc := &chain{
+		blocks:     append([]*ethgo.Block{}, c.blocks...),
+		states:     append([]*state{}, c.states...),
+		logs:       append([][]*ethgo.Log{}, c.logs...),
+		hashes:     make(map[ethgo.Hash]uint64, len(c.hashes)),
+		receipts:   make(map[ethgo.Hash]*ethgo.Receipt, len(c.receipts)),
+		txns:       make(map[ethgo.Hash]*ethgo.Transaction, len(c.txns)),
+		pending:    append([]*pendingTxn{}, c.pending...),
+		timeOffset: c.timeOffset,
+	}
+	for k, v := range c.hashes {
+		cc.hashes[k] = v
+	}
+	for k, v := range c.receipts {
+		cc.receipts[k] = v
+	}
+	for k, v := range c.txns {
+		cc.txns[k] = v
+	}
+	return cc
+}
+
+func (c *chain) head() *ethgo.Block {
+	return c.blocks[len(c.blocks)-1]
+}
+
+func (c *chain) headState() *state {
+	return c.states[len(c.states)-1]
+}
+
+// Backend is an in-memory chain that executes the transactions with an evm
+// (Cancun). It implements the methods of jsonrpc.Eth used by the library so it can
+// be used as a tracker.Provider or a blocktracker.BlockProvider, and as a
+// contract.Provider with ContractProvider.
+type Backend struct {
+	config *Config
+	signer *wallet.EIP1155Signer
+
+	lock      sync.Mutex
+	chain     *chain
+	snapshots map[int]*chain
+	snapSeq   int
+	minedCh   chan struct{}
+}
+
+// NewBackend creates a new simulated backend with a genesis block
+func NewBackend(opts ...ConfigOption) *Backend {
+	config := &Config{
+		ChainID:  DefaultChainID,
+		GasLimit: DefaultGasLimit,
+		BaseFee:  DefaultBaseFee,
+		Alloc:    map[ethgo.Address]*big.Int{},
+		AutoMine: true,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	genesisState := newState()
+	for addr, balance := range config.Alloc {
+		genesisState.getOrCreate(addr).balance = new(big.Int).Set(balance)
+	}
+	genesisState.resetTx()
+
+	genesis := &ethgo.Block{
+		Number:     0,
+		Miner:      config.Coinbase,
+		Difficulty: new(big.Int),
+		GasLimit:   config.GasLimit,
+		Timestamp:  uint64(time.Now().Unix()),
+		BaseFee:    new(big.Int).Set(config.BaseFee),
+	}
+	genesis.Hash = blockHash(genesis)
+
+	b := &Backend{
+		config: config,
+		signer: wallet.NewEIP155Signer(config.ChainID),
+		chain: &chain{
+			blocks:   []*ethgo.Block{genesis},
+			states:   []*state{genesisState},
+			logs:     [][]*ethgo.Log{nil},
+			hashes:   map[ethgo.Hash]uint64{genesis.Hash: 0},
+			receipts: map[ethgo.Hash]*ethgo.Receipt{},
+			txns:     map[ethgo.Hash]*ethgo.Transaction{},
+		},
+		snapshots: map[int]*chain{},
+		minedCh:   make(chan struct{}),
+	}
+	return b
+}
+
+// blockHash returns a hash that identifies the contents of the block. It is
+// not the hash of the header since the backend does not compute the roots.
+func blockHash(b *ethgo.Block) ethgo.Hash {
+	buf := make([]byte, 16)
+	binary.BigEndian.PutUint64(buf[0:], b.Number)
+	binary.BigEndian.PutUint64(buf[8:], b.Timestamp)
+
+	data := [][]byte{b.ParentHash[:], buf, b.Miner[:]}
+	for _, txn := range b.Transactions {
+		data = append(data, txn.Hash[:])
+	}
+	return ethgo.BytesToHash(ethgo.Keccak256(data...))
+}
+
+// Snapshot saves the current state of the chain and returns an id to revert to it
+func (b *Backend) Snapshot() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.snapSeq++
+	b.snapshots[b.snapSeq] = b.chain.copy()
+	return b.snapSeq
+}
+
+// Revert reverts the chain to the snapshot. The snapshot and the ones taken after it are removed.
+func (b *Backend) Revert(id int) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	snapshot, ok := b.snapshots[id]
+	if !ok {
+		return fmt.Errorf("snapshot %d not found", id)
+	}
+	for i := range b.snapshots {
+		if i >= id {
+			delete(b.snapshots, i)
+		}
+	}
+	b.chain = snapshot
+	return nil
+}
+
+// AdjustTime moves forward the timestamp of the next blocks
+func (b *Backend) AdjustTime(d time.Duration) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.chain.timeOffset += uint64(d.Seconds())
+}
+
+// SetBalance sets the balance of the account in the head state
+func (b *Backend) SetBalance(addr ethgo.Address, balance *big.Int) {
+	b.modifyHeadState(func(s *state) {
+		s.getOrCreate(addr).balance = new(big.Int).Set(balance)
+	})
+}
+
+// SetCode sets the code of the account in the head state
+func (b *Backend) SetCode(addr ethgo.Address, code []byte) {
+	b.modifyHeadState(func(s *state) {
+		s.getOrCreate(addr).code = code
+	})
+}
+
+// SetStorageAt sets a storage slot of the account in the head state
+func (b *Backend) SetStorageAt(addr ethgo.Address, slot, val ethgo.Hash) {
+	b.modifyHeadState(func(s *state) {
+		s.getOrCreate(addr).storage[slot] = val
+	})
+}
+
+func (b *Backend) modifyHeadState(handler func(s *state)) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	// the state might be shared with the snapshots
+	s := b.chain.headState().copy()
+	handler(s)
+	s.resetTx()
+	b.chain.states[len(b.chain.states)-1] = s
+}
+
+// Commit mines a new block with the pending transactions
+func (b *Backend) Commit() *ethgo.Block {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	pending := b.chain.pending
+	b.chain.pending = nil
+
+	block := b.mineLocked(pending)
+	return block.Copy()
+}
+
+// Mine mines n blocks with the pending transactions in the first block
+func (b *Backend) Mine(n int) {
+	for i := 0; i < n; i++ {
+		b.Commit()
+	}
+}
+
+func (b *Backend) mineLocked(pending []*pendingTxn) *ethgo.Block {
+	parent := b.chain.head()
+	st := b.chain.headState().copy()
+
+	timestamp := uint64(time.Now().Unix()) + b.chain.timeOffset
+	if timestamp <= parent.Timestamp {
+		timestamp = parent.Timestamp + 1
+	}
+
+	block := &ethgo.Block{
+		Number:     parent.Number + 1,
+		ParentHash: parent.Hash,
+		Miner:      b.config.Coinbase,
+		Difficulty: new(big.Int),
+		GasLimit:   b.config.GasLimit,
+		Timestamp:  timestamp,
+		MixHash:    ethgo.BytesToHash(ethgo.Keccak256(parent.Hash[:])),
+		BaseFee:    new(big.Int).Set(b.config.BaseFee),
+	}
+	blockCtx := b.blockContext(block)
+
+	receipts := []*ethgo.Receipt{}
+	for _, p := range pending {
+		if block.GasUsed+p.txn.Gas > block.GasLimit {
+			// it does not fit in the block, keep it for the next one
+			b.chain.pending = append(b.chain.pending, p)
+			continue
+		}
+		res, err := applyMessage(st, blockCtx, b.txnToMessage(p.txn, p.from, block.BaseFee))
+		if err != nil {
+			// the transaction is not valid anymore, drop it
+			continue
+		}
+
+		txn := p.txn.Copy()
+		txn.From = p.from
+		txn.BlockNumber = block.Number
+		txn.TxnIndex = uint64(len(block.Transactions))
+		block.Transactions = append(block.Transactions, txn)
+		block.GasUsed += res.gasUsed
+
+		receipt := &ethgo.Receipt{
+			TransactionHash:   txn.Hash,
+			TransactionIndex:  txn.TxnIndex,
+			From:              p.from,
+			To:                txn.To,
+			BlockNumber:       block.Number,
+			GasUsed:           res.gasUsed,
+			CumulativeGasUsed: block.GasUsed,
+			Logs:              []*ethgo.Log{},
+		}
+		if res.err == nil {
+			receipt.Status = 1
+			receipt.Logs = res.logs
+			if txn.To == nil {
+				receipt.ContractAddress = res.contractAddress
+			}
+		}
+		receipts = append(receipts, receipt)
+	}
+
+	block.Hash = blockHash(block)
+
+	logs := []*ethgo.Log{}
+	for indx, receipt := range receipts {
+		txn := block.Transactions[indx]
+		txn.BlockHash = block.Hash
+
+		receipt.BlockHash = block.Hash
+		for _, log := range receipt.Logs {
+			log.BlockHash = block.Hash
+			log.BlockNumber = block.Number
+			log.TransactionHash = txn.Hash
+			log.TransactionIndex = txn.TxnIndex
+			log.LogIndex = uint64(len(logs))
+			logs = append(logs, log)
+		}
+		receipt.LogsBloom = logsBloom(receipt.Logs)
+
+		b.chain.receipts[txn.Hash] = receipt
+		b.chain.txns[txn.Hash] = txn
+	}
+
+	b.chain.blocks = append(b.chain.blocks, block)
+	b.chain.states = append(b.chain.states, st)
+	b.chain.logs = append(b.chain.logs, logs)
+	b.chain.hashes[block.Hash] = block.Number
+
+	close(b.minedCh)
+	b.minedCh = make(chan struct{})
+
+	return block
+}
+
+func (b *Backend) blockContext(block *ethgo.Block) *blockContext {
+	blocks := b.chain.blocks
+	return &blockContext{
+		number:     block.Number,
+		timestamp:  block.Timestamp,
+		coinbase:   block.Miner,
+		gasLimit:   block.GasLimit,
+		baseFee:    block.BaseFee,
+		prevRandao: block.MixHash,
+		chainID:    new(big.Int).SetUint64(b.config.ChainID),
+		getHash: func(num uint64) ethgo.Hash {
+			if num >= uint64(len(blocks)) {
+				return ethgo.Hash{}
+			}
+			return blocks[num].Hash
+		},
+	}
+}
+
+// pendingBlock returns the context of the next block to execute calls on top of the head
+func (b *Backend) pendingBlock(parent *ethgo.Block) *ethgo.Block {
+	return &ethgo.Block{
+		Number:    parent.Number + 1,
+		Miner:     b.config.Coinbase,
+		GasLimit:  b.config.GasLimit,
+		Timestamp: parent.Timestamp + 1,
+		MixHash:   ethgo.BytesToHash(ethgo.Keccak256(parent.Hash[:])),
+		BaseFee:   new(big.Int).Set(b.config.BaseFee),
+	}
+}
+
+func (b *Backend) txnToMessage(txn *ethgo.Transaction, from ethgo.Address, baseFee *big.Int) *message {
+	msg := &message{
+		from:       from,
+		to:         txn.To,
+		nonce:      txn.Nonce,
+		value:      txn.Value,
+		gas:        txn.Gas,
+		gasPrice:   new(big.Int).SetUint64(txn.GasPrice),
+		input:      txn.Input,
+		accessList: txn.AccessList,
+	}
+	if msg.value == nil {
+		msg.value = new(big.Int)
+	}
+	if txn.Type == ethgo.TransactionDynamicFee {
+		// min(maxFeePerGas, baseFee + maxPriorityFeePerGas)
+		price := new(big.Int).Add(baseFee, txn.MaxPriorityFeePerGas)
+		if price.Cmp(txn.MaxFeePerGas) > 0 {
+			price.Set(txn.MaxFeePerGas)
+		}
+		msg.gasPrice = price
+	}
+	return msg
+}
+
+// resolveNumber returns the number of the block
+func (b *Backend) resolveNumber(num ethgo.BlockNumber) (uint64, bool) {
+	head := b.chain.head().Number
+	if num < 0 {
+		var depth uint64
+		switch num {
+		case ethgo.Finalized:
+			depth = b.config.FinalityDepth
+		case ethgo.Safe:
+			depth = b.config.FinalityDepth / 2
+		}
+		if depth > head {
+			return 0, true
+		}
+		return head - depth, true
+	}
+	if uint64(num) > head {
+		return 0, false
+	}
+	return uint64(num), true
+}
+
+func (b *Backend) resolveBlock(block ethgo.BlockNumberOrHash) (uint64, error) {
+	switch obj := block.(type) {
+	case nil:
+		return b.chain.head().Number, nil
+	case ethgo.BlockNumber:
+		num, ok := b.resolveNumber(obj)
+		if !ok {
+			return 0, fmt.Errorf("%w: block %d", codec.ErrHeaderNotFound, obj)
+		}
+		return num, nil
+	case ethgo.Hash:
+		num, ok := b.chain.hashes[obj]
+		if !ok {
+			return 0, fmt.Errorf("%w: block %s", codec.ErrHeaderNotFound, obj)
+		}
+		return num, nil
+	}
+	return 0, fmt.Errorf("block location %s not supported", block.Location())
+}
+
+func (b *Backend) stateAt(block ethgo.BlockNumberOrHash) (*state, error) {
+	num, err := b.resolveBlock(block)
+	if err != nil {
+		return nil, err
+	}
+	return b.chain.states[num], nil
+}
+
+func exportBlock(block *ethgo.Block, full bool) *ethgo.Block {
+	res := block.Copy()
+	if !full {
+		res.TransactionsHashes = make([]ethgo.Hash, len(block.Transactions))
+		for indx, txn := range block.Transactions {
+			res.TransactionsHashes[indx] = txn.Hash
+		}
+		res.Transactions = nil
+	}
+	return res
+}
+
+// ChainID returns the id of the chain
+func (b *Backend) ChainID() (*big.Int, error) {
+	return new(big.Int).SetUint64(b.config.ChainID), nil
+}
+
+// BlockNumber returns the number of the head of the chain
+func (b *Backend) BlockNumber() (uint64, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.chain.head().Number, nil
+}
+
+// GetBlockByNumber returns the block with the number or nil if it does not exist
+func (b *Backend) GetBlockByNumber(i ethgo.BlockNumber, full bool) (*ethgo.Block, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	num, ok := b.resolveNumber(i)
+	if !ok {
+		return nil, nil
+	}
+	return exportBlock(b.chain.blocks[num], full), nil
+}
+
+// GetBlockByHash returns the block with the hash or nil if it does not exist
+func (b *Backend) GetBlockByHash(hash ethgo.Hash, full bool) (*ethgo.Block, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	num, ok := b.chain.hashes[hash]
+	if !ok {
+		return nil, nil
+	}
+	return exportBlock(b.chain.blocks[num], full), nil
+}
+
+// GetTransactionByHash returns the mined transaction or nil if it does not exist
+func (b *Backend) GetTransactionByHash(hash ethgo.Hash) (*ethgo.Transaction, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	txn, ok := b.chain.txns[hash]
+	if !ok {
+		return nil, nil
+	}
+	return txn.Copy(), nil
+}
+
+// GetTransactionReceipt returns the receipt of the transaction or nil if it has not been mined
+func (b *Backend) GetTransactionReceipt(hash ethgo.Hash) (*ethgo.Receipt, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	receipt, ok := b.chain.receipts[hash]
+	if !ok {
+		return nil, nil
+	}
+	return receipt.Copy(), nil
+}
+
+// GetLogs returns the logs that match the filter
+func (b *Backend) GetLogs(filter *ethgo.LogFilter) ([]*ethgo.Log, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var from, to uint64
+	if filter.BlockHash != nil {
+		num, ok := b.chain.hashes[*filter.BlockHash]
+		if !ok {
+			return nil, fmt.Errorf("%w: block %s", codec.ErrHeaderNotFound, *filter.BlockHash)
+		}
+		from, to = num, num
+	} else {
+		head := b.chain.head().Number
+		from, to = head, head
+		if filter.From != nil {
+			num, ok := b.resolveNumber(*filter.From)
+			if !ok {
+				// the range starts after the head
+				return []*ethgo.Log{}, nil
+			}
+			from = num
+		}
+		if filter.To != nil {
+			// the range ends at the head if it goes beyond it
+			if num, ok := b.resolveNumber(*filter.To); ok {
+				to = num
+			}
+		}
+		if from > to {
+			return nil, fmt.Errorf("invalid block range params")
+		}
+	}
+
+	res := []*ethgo.Log{}
+	for num := from; num <= to; num++ {
+		for _, log := range b.chain.logs[num] {
+			if matchLog(filter, log) {
+				res = append(res, log.Copy())
+			}
+		}
+	}
+	return res, nil
+}
+
+func matchLog(filter *ethgo.LogFilter, log *ethgo.Log) bool {
+	if len(filter.Address) != 0 {
+		found := false
+		for _, addr := range filter.Address {
+			if addr == log.Address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for indx, topics := range filter.Topics {
+		if len(topics) == 0 {
+			continue
+		}
+		if indx >= len(log.Topics) {
+			return false
+		}
+		found := false
+		for _, topic := range topics {
+			if topic == nil || *topic == log.Topics[indx] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// GetBalance returns the balance of the account at the block
+func (b *Backend) GetBalance(addr ethgo.Address, block ethgo.BlockNumberOrHash) (*big.Int, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	st, err := b.stateAt(block)
+	if err != nil {
+		return nil, err
+	}
+	return st.getBalance(addr), nil
+}
+
+// GetNonce returns the nonce of the account at the block. The pending
+// transactions of the account are included for the pending block.
+func (b *Backend) GetNonce(addr ethgo.Address, block ethgo.BlockNumberOrHash) (uint64, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	st, err := b.stateAt(block)
+	if err != nil {
+		return 0, err
+	}
+	nonce := st.getNonce(addr)
+	if num, ok := block.(ethgo.BlockNumber); ok && num == ethgo.Pending {
+		nonce = b.pendingNonceLocked(addr)
+	}
+	return nonce, nil
+}
+
+func (b *Backend) pendingNonceLocked(addr ethgo.Address) uint64 {
+	nonce := b.chain.headState().getNonce(addr)
+	for _, p := range b.chain.pending {
+		if p.from == addr && p.txn.Nonce >= nonce {
+			nonce = p.txn.Nonce + 1
+		}
+	}
+	return nonce
+}
+
+// GetCode returns the code of the account at the block as an hex string
+func (b *Backend) GetCode(addr ethgo.Address, block ethgo.BlockNumberOrHash) (string, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	st, err := b.stateAt(block)
+	if err != nil {
+		return "", err
+	}
+	return "0x" + hex.EncodeToString(st.getCode(addr)), nil
+}
+
+// GetStorageAt returns the value of the storage slot of the account at the block
+func (b *Backend) GetStorageAt(addr ethgo.Address, slot ethgo.Hash, block ethgo.BlockNumberOrHash) (ethgo.Hash, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	st, err := b.stateAt(block)
+	if err != nil {
+		return ethgo.Hash{}, err
+	}
+	return st.getStorage(addr, slot), nil
+}
+
+// GasPrice returns the base fee plus the suggested tip
+func (b *Backend) GasPrice() (uint64, error) {
+	return new(big.Int).Add(b.config.BaseFee, defaultPriorityFee).Uint64(), nil
+}
+
+// MaxPriorityFeePerGas returns the suggested tip
+func (b *Backend) MaxPriorityFeePerGas() (*big.Int, error) {
+	return new(big.Int).Set(defaultPriorityFee), nil
+}
+
+func (b *Backend) callMessage(msg *ethgo.CallMsg, gasCap uint64) *message {
+	m := &message{
+		from:       msg.From,
+		to:         msg.To,
+		value:      msg.Value,
+		gas:        gasCap,
+		gasPrice:   new(big.Int).SetUint64(msg.GasPrice),
+		input:      msg.Data,
+		skipChecks: true,
+	}
+	if m.value == nil {
+		m.value = new(big.Int)
+	}
+	if msg.Gas != nil && msg.Gas.IsUint64() && msg.Gas.Uint64() < gasCap {
+		m.gas = msg.Gas.Uint64()
+	}
+	return m
+}
+
+// callLocked executes the message on a copy of the state at the block
+func (b *Backend) callLocked(msg *message, block ethgo.BlockNumber, override *ethgo.StateOverride) (*executionResult, error) {
+	num, ok := b.resolveNumber(block)
+	if !ok {
+		return nil, fmt.Errorf("%w: block %d", codec.ErrHeaderNotFound, block)
+	}
+	st := b.chain.states[num].copy()
+	if override != nil {
+		applyOverride(st, *override)
+	}
+
+	parent := b.chain.blocks[num]
+	blockCtx := b.blockContext(b.pendingBlock(parent))
+	if msg.gasPrice.Sign() == 0 {
+		// calls without gas price do not pay the base fee
+		blockCtx.baseFee = new(big.Int)
+	}
+	return applyMessage(st, blockCtx, msg)
+}
+
+func applyOverride(st *state, override ethgo.StateOverride) {
+	for addr, obj := range override {
+		acct := st.getOrCreate(addr)
+		if obj.Nonce != nil {
+			acct.nonce = *obj.Nonce
+		}
+		if obj.Code != nil {
+			acct.code = *obj.Code
+		}
+		if obj.Balance != nil {
+			acct.balance = new(big.Int).Set(obj.Balance)
+		}
+		if obj.State != nil {
+			acct.storage = map[ethgo.Hash]ethgo.Hash{}
+			for k, v := range *obj.State {
+				acct.storage[k] = v
+			}
+		}
+		if obj.StateDiff != nil {
+			for k, v := range *obj.StateDiff {
+				acct.storage[k] = v
+			}
+		}
+	}
+	st.resetTx()
+}
+
+// executionError converts the error of the execution into the jsonrpc error returned by the nodes
+func executionError(res *executionResult) error {
+	if res.err == errReverted {
+		return &codec.ErrorObject{
+			Code:    codec.CodeExecutionReverted,
+			Message: "execution reverted",
+			Data:    "0x" + hex.EncodeToString(res.ret),
+		}
+	}
+	return &codec.ErrorObject{Code: codec.CodeInvalidInput, Message: res.err.Error()}
+}
+
+func invalidError(err error) error {
+	return &codec.ErrorObject{Code: codec.CodeInvalidInput, Message: err.Error()}
+}
+
+// Call executes the message on top of the state of the block and returns the output as an hex string
+func (b *Backend) Call(msg *ethgo.CallMsg, block ethgo.BlockNumber, override ...*ethgo.StateOverride) (string, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	var stateOverride *ethgo.StateOverride
+	if len(override) == 1 {
+		stateOverride = override[0]
+	}
+	res, err := b.callLocked(b.callMessage(msg, b.config.GasLimit), block, stateOverride)
+	if err != nil {
+		return "", invalidError(err)
+	}
+	if res.err != nil {
+		return "", executionError(res)
+	}
+	return "0x" + hex.EncodeToString(res.ret), nil
+}
+
+// EstimateGas returns the minimum gas required to execute the message
+func (b *Backend) EstimateGas(msg *ethgo.CallMsg) (uint64, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	hi := b.config.GasLimit
+	if msg.Gas != nil && msg.Gas.IsUint64() && msg.Gas.Uint64() < hi {
+		hi = msg.Gas.Uint64()
+	}
+	if msg.GasPrice != 0 {
+		// the account must be able to pay for the gas
+		balance := b.chain.headState().getBalance(msg.From)
+		if msg.Value != nil {
+			balance.Sub(balance, msg.Value)
+		}
+		allowance := balance.Div(balance, new(big.Int).SetUint64(msg.GasPrice))
+		if allowance.IsUint64() && allowance.Uint64() < hi {
+			hi = allowance.Uint64()
+		}
+	}
+
+	execute := func(gas uint64) (*executionResult, error) {
+		return b.callLocked(b.callMessage(msg, gas), ethgo.Latest, nil)
+	}
+
+	res, err := execute(hi)
+	if err != nil {
+		return 0, invalidError(err)
+	}
+	if res.err != nil {
+		return 0, executionError(res)
+	}
+
+	// the gas used is a lower bound since the refunds and the
+	// one 64th rule of the calls require more gas to be available
+	lo := res.gasUsed - 1
+	for lo+1 < hi {
+		mid := lo + (hi-lo)/2
+		res, err := execute(mid)
+		if err != nil || res.err != nil {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	return hi, nil
+}
+
+// SendRawTransaction sends a signed transaction. The transaction is mined
+// right away unless the backend was created with manual mining.
+func (b *Backend) SendRawTransaction(data []byte) (ethgo.Hash, error) {
+	txn := &ethgo.Transaction{}
+	if err := txn.UnmarshalRLP(data); err != nil {
+		return ethgo.Hash{}, invalidError(fmt.Errorf("failed to decode transaction: %v", err))
+	}
+
+	signer := b.signer
+	if txn.Type == ethgo.TransactionLegacy {
+		v := new(big.Int).SetBytes(txn.V).Uint64()
+		if v == 27 || v == 28 {
+			// not protected against replays (pre EIP-155)
+			signer = wallet.NewEIP155Signer(0)
+		} else if v < 35 || (v-35)/2 != b.config.ChainID {
+			return ethgo.Hash{}, invalidError(fmt.Errorf("invalid chain id"))
+		}
+	} else if txn.ChainID == nil || txn.ChainID.Uint64() != b.config.ChainID {
+		return ethgo.Hash{}, invalidError(fmt.Errorf("invalid chain id"))
+	}
+	from, err := signer.RecoverSender(txn)
+	if err != nil {
+		return ethgo.Hash{}, invalidError(fmt.Errorf("invalid sender: %v", err))
+	}
+	txn.From = from
+
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if err := b.validateLocked(txn, from); err != nil {
+		return ethgo.Hash{}, invalidError(err)
+	}
+
+	p := &pendingTxn{txn: txn, from: from}
+	if b.config.AutoMine {
+		b.mineLocked([]*pendingTxn{p})
+	} else {
+		b.chain.pending = append(b.chain.pending, p)
+	}
+	return txn.Hash, nil
+}
+
+func (b *Backend) validateLocked(txn *ethgo.Transaction, from ethgo.Address) error {
+	if _, ok := b.chain.txns[txn.Hash]; ok {
+		return fmt.Errorf("%w: %s", codec.ErrAlreadyKnown, txn.Hash)
+	}
+	for _, p := range b.chain.pending {
+		if p.txn.Hash == txn.Hash {
+			return fmt.Errorf("%w: %s", codec.ErrAlreadyKnown, txn.Hash)
+		}
+	}
+	if txn.Gas > b.config.GasLimit {
+		return fmt.Errorf("exceeds block gas limit")
+	}
+
+	st := b.chain.headState()
+	nonce := b.pendingNonceLocked(from)
+	if txn.Nonce < st.getNonce(from) {
+		return fmt.Errorf("%w: address %s, tx: %d state: %d", codec.ErrNonceTooLow, from, txn.Nonce, st.getNonce(from))
+	}
+	if txn.Nonce != nonce {
+		return fmt.Errorf("%w: address %s, tx: %d state: %d", codec.ErrNonceTooHigh, from, txn.Nonce, nonce)
+	}
+
+	maxPrice := new(big.Int).SetUint64(txn.GasPrice)
+	if txn.Type == ethgo.TransactionDynamicFee {
+		if txn.MaxFeePerGas == nil || txn.MaxPriorityFeePerGas == nil {
+			return fmt.Errorf("missing fee values")
+		}
+		if txn.MaxPriorityFeePerGas.Cmp(txn.MaxFeePerGas) > 0 {
+			return fmt.Errorf("max priority fee per gas higher than max fee per gas")
+		}
+		maxPrice = txn.MaxFeePerGas
+	}
+	if maxPrice.Cmp(b.config.BaseFee) < 0 {
+		return fmt.Errorf("max fee per gas less than block base fee: maxFeePerGas: %s baseFee: %s", maxPrice, b.config.BaseFee)
+	}
+
+	cost := new(big.Int).Mul(maxPrice, new(big.Int).SetUint64(txn.Gas))
+	if txn.Value != nil {
+		cost.Add(cost, txn.Value)
+	}
+	if balance := st.getBalance(from); balance.Cmp(cost) < 0 {
+		return fmt.Errorf("%w for gas * price + value: address %s have %s want %s", codec.ErrInsufficientFunds, from, balance, cost)
+	}
+	msg := b.txnToMessage(txn, from, b.config.BaseFee)
+	if intrinsic := intrinsicGas(msg); txn.Gas < intrinsic {
+		return fmt.Errorf("intrinsic gas too low: have %d, want %d", txn.Gas, intrinsic)
+	}
+	return nil
+}
+
+// waitBlock returns a channel that is closed when the next block is mined
+func (b *Backend) waitBlock() <-chan struct{} {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.minedCh
+}
+
+// logsBloom returns the bloom filter of the logs
+func logsBloom(logs []*ethgo.Log) []byte {
+	bloom := make([]byte, 256)
+	add := func(data []byte) {
+		h := ethgo.Keccak256(data)
+		for i := 0; i < 6; i += 2 {
+			bit := (uint(h[i])<<8 | uint(h[i+1])) & 2047
+			bloom[256-1-bit/8] |= 1 << (bit % 8)
+		}
+	}
+	for _, log := range logs {
+		add(log.Address[:])
+		for _, topic := range log.Topics {
+			add(topic[:])
+		}
+	}
+	return bloom
+}
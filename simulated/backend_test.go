@@ -0,0 +1,328 @@
+package simulated
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/blocktracker"
+	"github.com/Ethernal-Tech/ethgo/builtin/ens"
+	"github.com/Ethernal-Tech/ethgo/contract"
+	"github.com/Ethernal-Tech/ethgo/jsonrpc"
+	"github.com/Ethernal-Tech/ethgo/jsonrpc/codec"
+	"github.com/Ethernal-Tech/ethgo/tracker"
+	"github.com/Ethernal-Tech/ethgo/wallet"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ tracker.Provider           = &Backend{}
+	_ blocktracker.BlockProvider = &Backend{}
+)
+
+var oneEth = new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+
+func newFundedBackend(t *testing.T, opts ...ConfigOption) (*Backend, *wallet.Key) {
+	t.Helper()
+
+	key, err := wallet.GenerateKey()
+	require.NoError(t, err)
+
+	opts = append(opts, WithBalance(key.Address(), oneEth))
+	return NewBackend(opts...), key
+}
+
+func signTxn(t *testing.T, b *Backend, key *wallet.Key, txn *ethgo.Transaction) []byte {
+	t.Helper()
+
+	if txn.GasPrice == 0 {
+		txn.GasPrice = 2 * DefaultBaseFee.Uint64()
+	}
+	if txn.Gas == 0 {
+		txn.Gas = 21000
+	}
+	txn.ChainID = new(big.Int).SetUint64(b.config.ChainID)
+
+	signedTxn, err := wallet.NewEIP155Signer(b.config.ChainID).SignTx(txn, key)
+	require.NoError(t, err)
+
+	raw, err := signedTxn.MarshalRLPTo(nil)
+	require.NoError(t, err)
+	return raw
+}
+
+func TestBackend_Genesis(t *testing.T) {
+	b, key := newFundedBackend(t)
+
+	num, err := b.BlockNumber()
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), num)
+
+	block, err := b.GetBlockByNumber(ethgo.Latest, false)
+	require.NoError(t, err)
+
+	block2, err := b.GetBlockByHash(block.Hash, false)
+	require.NoError(t, err)
+	require.Equal(t, block.Number, block2.Number)
+
+	missing, err := b.GetBlockByNumber(10, false)
+	require.NoError(t, err)
+	require.Nil(t, missing)
+
+	balance, err := b.GetBalance(key.Address(), ethgo.Latest)
+	require.NoError(t, err)
+	require.Equal(t, oneEth, balance)
+}
+
+func TestBackend_Transfer(t *testing.T) {
+	b, key := newFundedBackend(t)
+
+	to := ethgo.Address{0x1}
+	hash, err := b.SendRawTransaction(signTxn(t, b, key, &ethgo.Transaction{
+		To:    &to,
+		Value: big.NewInt(1000),
+	}))
+	require.NoError(t, err)
+
+	// the transaction is mined right away
+	receipt, err := b.GetTransactionReceipt(hash)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), receipt.Status)
+	require.Equal(t, uint64(1), receipt.BlockNumber)
+	require.Equal(t, uint64(21000), receipt.GasUsed)
+
+	balance, err := b.GetBalance(to, ethgo.Latest)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(1000), balance)
+
+	txn, err := b.GetTransactionByHash(hash)
+	require.NoError(t, err)
+	require.Equal(t, key.Address(), txn.From)
+
+	// the sender pays the value and the gas
+	balance, err = b.GetBalance(key.Address(), ethgo.Latest)
+	require.NoError(t, err)
+	spent := new(big.Int).Sub(oneEth, balance)
+	require.Equal(t, new(big.Int).SetUint64(1000+21000*2*DefaultBaseFee.Uint64()), spent)
+
+	// the nonce cannot be used twice
+	_, err = b.SendRawTransaction(signTxn(t, b, key, &ethgo.Transaction{
+		To:    &to,
+		Value: big.NewInt(2000),
+	}))
+	require.ErrorIs(t, err, jsonrpc.ErrNonceTooLow)
+
+	// not enough funds for the value
+	_, err = b.SendRawTransaction(signTxn(t, b, key, &ethgo.Transaction{
+		To:    &to,
+		Nonce: 1,
+		Value: oneEth,
+	}))
+	require.ErrorIs(t, err, jsonrpc.ErrInsufficientFunds)
+}
+
+func TestBackend_ManualMining(t *testing.T) {
+	b, key := newFundedBackend(t, WithManualMining())
+
+	to := ethgo.Address{0x1}
+	for i := uint64(0); i < 3; i++ {
+		_, err := b.SendRawTransaction(signTxn(t, b, key, &ethgo.Transaction{
+			To:    &to,
+			Nonce: i,
+			Value: big.NewInt(1),
+		}))
+		require.NoError(t, err)
+	}
+
+	nonce, err := b.GetNonce(key.Address(), ethgo.Pending)
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), nonce)
+
+	nonce, err = b.GetNonce(key.Address(), ethgo.Latest)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), nonce)
+
+	block := b.Commit()
+	require.Equal(t, uint64(1), block.Number)
+	require.Len(t, block.Transactions, 3)
+
+	b.Mine(2)
+
+	num, err := b.BlockNumber()
+	require.NoError(t, err)
+	require.Equal(t, uint64(3), num)
+}
+
+func TestBackend_Snapshot(t *testing.T) {
+	b, key := newFundedBackend(t)
+
+	id := b.Snapshot()
+
+	to := ethgo.Address{0x1}
+	hash, err := b.SendRawTransaction(signTxn(t, b, key, &ethgo.Transaction{
+		To:    &to,
+		Value: big.NewInt(1000),
+	}))
+	require.NoError(t, err)
+
+	require.NoError(t, b.Revert(id))
+
+	num, err := b.BlockNumber()
+	require.NoError(t, err)
+	require.Equal(t, uint64(0), num)
+
+	receipt, err := b.GetTransactionReceipt(hash)
+	require.NoError(t, err)
+	require.Nil(t, receipt)
+
+	balance, err := b.GetBalance(to, ethgo.Latest)
+	require.NoError(t, err)
+	require.Zero(t, balance.Sign())
+
+	// the snapshot cannot be used twice
+	require.Error(t, b.Revert(id))
+}
+
+func TestBackend_Cheats(t *testing.T) {
+	b := NewBackend()
+
+	addr := ethgo.Address{0x1}
+	b.SetBalance(addr, big.NewInt(10))
+	b.SetCode(addr, []byte{STOP})
+	b.SetStorageAt(addr, ethgo.Hash{0x1}, ethgo.Hash{0x2})
+
+	balance, err := b.GetBalance(addr, ethgo.Latest)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(10), balance)
+
+	code, err := b.GetCode(addr, ethgo.Latest)
+	require.NoError(t, err)
+	require.Equal(t, "0x00", code)
+
+	val, err := b.GetStorageAt(addr, ethgo.Hash{0x1}, ethgo.Latest)
+	require.NoError(t, err)
+	require.Equal(t, ethgo.Hash{0x2}, val)
+
+	before, err := b.GetBlockByNumber(ethgo.Latest, false)
+	require.NoError(t, err)
+
+	b.AdjustTime(time.Hour)
+	after := b.Commit()
+	require.GreaterOrEqual(t, after.Timestamp, before.Timestamp+3600)
+}
+
+func TestBackend_Contract(t *testing.T) {
+	b, key := newFundedBackend(t)
+	provider := b.ContractProvider()
+
+	txn, err := contract.DeployContract(ens.ENSAbi(), ens.ENSBin(), nil, contract.WithProvider(provider), contract.WithSender(key))
+	require.NoError(t, err)
+	require.NoError(t, txn.Do())
+
+	receipt, err := txn.Wait()
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), receipt.Status)
+
+	ensAddr := receipt.ContractAddress
+	code, err := b.GetCode(ensAddr, ethgo.Latest)
+	require.NoError(t, err)
+	require.NotEqual(t, "0x", code)
+
+	e := ens.NewENS(ensAddr, contract.WithProvider(provider), contract.WithSender(key))
+
+	// the deployer owns the root node
+	owner, err := e.Owner([32]byte{})
+	require.NoError(t, err)
+	require.Equal(t, key.Address(), owner)
+
+	label := ethgo.BytesToHash(ethgo.Keccak256([]byte("eth")))
+	newOwner := ethgo.Address{0x1}
+
+	txn, err = e.SetSubnodeOwner([32]byte{}, label, newOwner)
+	require.NoError(t, err)
+	require.NoError(t, txn.Do())
+
+	receipt, err = txn.Wait()
+	require.NoError(t, err)
+	require.Len(t, receipt.Logs, 1)
+	require.Equal(t, e.NewOwnerEventSig(), receipt.Logs[0].Topics[0])
+
+	owner, err = e.Owner(ens.NameHash("eth"))
+	require.NoError(t, err)
+	require.Equal(t, newOwner, owner)
+
+	// the same log is returned by the filter
+	logs, err := b.GetLogs(&ethgo.LogFilter{
+		Address: []ethgo.Address{ensAddr},
+		Topics:  [][]*ethgo.Hash{{hashPtr(e.NewOwnerEventSig())}},
+	})
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+	require.Equal(t, receipt.TransactionHash, logs[0].TransactionHash)
+
+	// the sender does not own the node anymore
+	txn, err = e.SetOwner(ens.NameHash("eth"), key.Address())
+	require.NoError(t, err)
+
+	var revertErr *codec.RevertError
+	require.True(t, errors.As(txn.Do(), &revertErr))
+}
+
+func TestBackend_Tracker(t *testing.T) {
+	b, key := newFundedBackend(t)
+
+	// a contract that emits a LOG0 with the calldata
+	addr := ethgo.Address{0x11}
+	b.SetCode(addr, asm(CALLDATASIZE, PUSH0, PUSH0, CALLDATACOPY, CALLDATASIZE, PUSH0, LOG0))
+
+	for i := uint64(0); i < 5; i++ {
+		_, err := b.SendRawTransaction(signTxn(t, b, key, &ethgo.Transaction{
+			To:    &addr,
+			Nonce: i,
+			Gas:   100000,
+			Input: []byte{byte(i)},
+		}))
+		require.NoError(t, err)
+	}
+
+	tt, err := tracker.NewTracker(b, tracker.WithFilter(&tracker.FilterConfig{
+		Address: []ethgo.Address{addr},
+	}))
+	require.NoError(t, err)
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	go func() {
+		for range tt.EventCh {
+		}
+	}()
+	require.NoError(t, tt.BatchSync(ctx))
+
+	last, err := tt.Entry().LastIndex()
+	require.NoError(t, err)
+	require.Equal(t, uint64(5), last)
+}
+
+func hashPtr(h ethgo.Hash) *ethgo.Hash {
+	return &h
+}
+
+func TestBackend_Finality(t *testing.T) {
+	b := NewBackend(WithFinalityDepth(10))
+	b.Mine(25)
+
+	cases := map[ethgo.BlockNumber]uint64{
+		ethgo.Latest:    25,
+		ethgo.Safe:      20,
+		ethgo.Finalized: 15,
+	}
+	for tag, num := range cases {
+		block, err := b.GetBlockByNumber(tag, false)
+		require.NoError(t, err)
+		require.Equal(t, num, block.Number)
+	}
+}
@@ -0,0 +1,133 @@
+package simulated
+
+import (
+	"encoding/hex"
+	"math/big"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/contract"
+)
+
+// ContractProvider returns a contract.Provider that sends the calls and transactions to the backend
+func (b *Backend) ContractProvider() contract.Provider {
+	return &contractProvider{backend: b}
+}
+
+type contractProvider struct {
+	backend *Backend
+}
+
+func (c *contractProvider) Call(addr ethgo.Address, input []byte, opts *contract.CallOpts) ([]byte, error) {
+	msg := &ethgo.CallMsg{
+		From: opts.From,
+		To:   &addr,
+		Data: input,
+	}
+	rawStr, err := c.backend.Call(msg, opts.Block)
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(rawStr[2:])
+}
+
+func (c *contractProvider) Txn(addr ethgo.Address, key ethgo.Key, input []byte) (contract.Txn, error) {
+	txn := &transaction{
+		backend: c.backend,
+		opts:    &contract.TxnOpts{},
+		input:   input,
+		key:     key,
+		to:      addr,
+	}
+	return txn, nil
+}
+
+type transaction struct {
+	backend *Backend
+	to      ethgo.Address
+	input   []byte
+	hash    ethgo.Hash
+	opts    *contract.TxnOpts
+	key     ethgo.Key
+}
+
+func (t *transaction) Hash() ethgo.Hash {
+	return t.hash
+}
+
+func (t *transaction) WithOpts(opts *contract.TxnOpts) {
+	t.opts = opts
+}
+
+func (t *transaction) Do() error {
+	var err error
+	from := t.key.Address()
+
+	if t.opts.GasPrice == 0 {
+		if t.opts.GasPrice, err = t.backend.GasPrice(); err != nil {
+			return err
+		}
+	}
+	if t.opts.GasLimit == 0 {
+		msg := &ethgo.CallMsg{
+			From:     from,
+			Data:     t.input,
+			Value:    t.opts.Value,
+			GasPrice: t.opts.GasPrice,
+		}
+		if t.to != ethgo.ZeroAddress {
+			msg.To = &t.to
+		}
+		if t.opts.GasLimit, err = t.backend.EstimateGas(msg); err != nil {
+			return err
+		}
+	}
+	if t.opts.Nonce == 0 {
+		if t.opts.Nonce, err = t.backend.GetNonce(from, ethgo.Pending); err != nil {
+			return err
+		}
+	}
+
+	txn := &ethgo.Transaction{
+		From:     from,
+		Input:    t.input,
+		GasPrice: t.opts.GasPrice,
+		Gas:      t.opts.GasLimit,
+		Value:    t.opts.Value,
+		Nonce:    t.opts.Nonce,
+		ChainID:  new(big.Int).SetUint64(t.backend.config.ChainID),
+	}
+	if t.to != ethgo.ZeroAddress {
+		txn.To = &t.to
+	}
+
+	signedTxn, err := t.backend.signer.SignTx(txn, t.key)
+	if err != nil {
+		return err
+	}
+	raw, err := signedTxn.MarshalRLPTo(nil)
+	if err != nil {
+		return err
+	}
+	t.hash, err = t.backend.SendRawTransaction(raw)
+	return err
+}
+
+// Wait waits until the transaction is mined (right away unless the mining is manual)
+func (t *transaction) Wait() (*ethgo.Receipt, error) {
+	if (t.hash == ethgo.Hash{}) {
+		panic("transaction not executed")
+	}
+
+	for {
+		mined := t.backend.waitBlock()
+
+		receipt, err := t.backend.GetTransactionReceipt(t.hash)
+		if err != nil {
+			return nil, err
+		}
+		if receipt != nil {
+			return receipt, nil
+		}
+		<-mined
+	}
+}
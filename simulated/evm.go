@@ -0,0 +1,1044 @@
+package simulated
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/umbracle/fastrlp"
+)
+
+var (
+	errOutOfGas              = fmt.Errorf("out of gas")
+	errStackUnderflow        = fmt.Errorf("stack underflow")
+	errStackOverflow         = fmt.Errorf("stack overflow")
+	errInvalidJump           = fmt.Errorf("invalid jump destination")
+	errInvalidOpcode         = fmt.Errorf("invalid opcode")
+	errWriteProtection       = fmt.Errorf("write protection")
+	errReturnDataOutOfBounds = fmt.Errorf("return data out of bounds")
+	errDepth                 = fmt.Errorf("max call depth exceeded")
+	errInsufficientBalance   = fmt.Errorf("insufficient balance for transfer")
+	errContractCollision     = fmt.Errorf("contract address collision")
+	errMaxCodeSize           = fmt.Errorf("max code size exceeded")
+	errInvalidCode           = fmt.Errorf("invalid code: must not begin with 0xef")
+	errNonceMax              = fmt.Errorf("nonce has max value")
+
+	// errReverted is returned when the execution reverts. The remaining gas is not consumed.
+	errReverted = fmt.Errorf("execution reverted")
+)
+
+var (
+	tt256   = new(big.Int).Lsh(big.NewInt(1), 256)
+	tt255   = new(big.Int).Lsh(big.NewInt(1), 255)
+	tt256m1 = new(big.Int).Sub(tt256, big.NewInt(1))
+)
+
+// blockContext are the values of the block available to the evm
+type blockContext struct {
+	number     uint64
+	timestamp  uint64
+	coinbase   ethgo.Address
+	gasLimit   uint64
+	baseFee    *big.Int
+	prevRandao ethgo.Hash
+	chainID    *big.Int
+	getHash    func(num uint64) ethgo.Hash
+}
+
+// evm executes the messages on top of the state
+type evm struct {
+	state  *state
+	block  *blockContext
+	origin ethgo.Address
+	price  *big.Int
+	depth  int
+}
+
+// frame is the execution context of a call
+type frame struct {
+	caller   ethgo.Address
+	address  ethgo.Address
+	codeAddr ethgo.Address
+	value    *big.Int
+	input    []byte
+	code     []byte
+	gas      uint64
+	static   bool
+
+	pc         uint64
+	stack      []*big.Int
+	memory     []byte
+	returnData []byte
+	jumpdests  []bool
+}
+
+func (f *frame) useGas(gas uint64) bool {
+	if f.gas < gas {
+		return false
+	}
+	f.gas -= gas
+	return true
+}
+
+func (f *frame) push(v *big.Int) {
+	f.stack = append(f.stack, v)
+}
+
+func (f *frame) pop() *big.Int {
+	v := f.stack[len(f.stack)-1]
+	f.stack = f.stack[:len(f.stack)-1]
+	return v
+}
+
+func (f *frame) peek(i int) *big.Int {
+	return f.stack[len(f.stack)-1-i]
+}
+
+func words(size uint64) uint64 {
+	return (size + 31) / 32
+}
+
+func memoryCost(size uint64) uint64 {
+	w := words(size)
+	return w*3 + w*w/512
+}
+
+// toUint64 returns the value as an uint64 or false if it overflows
+func toUint64(v *big.Int) (uint64, bool) {
+	if !v.IsUint64() {
+		return 0, false
+	}
+	return v.Uint64(), true
+}
+
+// expandMemory charges the gas and expands the memory to include the range. It
+// returns the offset and the size of the range as uint64 values.
+func (f *frame) expandMemory(offset, size *big.Int) (uint64, uint64, bool) {
+	if size.Sign() == 0 {
+		return 0, 0, true
+	}
+	off, ok1 := toUint64(offset)
+	sz, ok2 := toUint64(size)
+	if !ok1 || !ok2 || off > 1<<32 || sz > 1<<32 {
+		return 0, 0, false
+	}
+	if !f.resize(off + sz) {
+		return 0, 0, false
+	}
+	return off, sz, true
+}
+
+func (f *frame) resize(size uint64) bool {
+	current := uint64(len(f.memory))
+	if size <= current {
+		return true
+	}
+	if !f.useGas(memoryCost(size) - memoryCost(current)) {
+		return false
+	}
+	f.memory = append(f.memory, make([]byte, words(size)*32-current)...)
+	return true
+}
+
+// getData returns the slice of the data padded with zeros
+func getData(data []byte, offset *big.Int, size uint64) []byte {
+	res := make([]byte, size)
+	if !offset.IsUint64() || offset.Uint64() >= uint64(len(data)) {
+		return res
+	}
+	copy(res, data[offset.Uint64():])
+	return res
+}
+
+func u256(v *big.Int) *big.Int {
+	return v.And(v, tt256m1)
+}
+
+func toSigned(v *big.Int) *big.Int {
+	if v.Cmp(tt255) >= 0 {
+		return new(big.Int).Sub(v, tt256)
+	}
+	return new(big.Int).Set(v)
+}
+
+func bigToAddress(v *big.Int) ethgo.Address {
+	return ethgo.BytesToAddress(v.Bytes())
+}
+
+func bigToHash(v *big.Int) ethgo.Hash {
+	var h ethgo.Hash
+	v.FillBytes(h[:])
+	return h
+}
+
+func hashToBig(h ethgo.Hash) *big.Int {
+	return new(big.Int).SetBytes(h[:])
+}
+
+func boolToBig(b bool) *big.Int {
+	if b {
+		return big.NewInt(1)
+	}
+	return new(big.Int)
+}
+
+func jumpdests(code []byte) []bool {
+	dests := make([]bool, len(code))
+	for i := 0; i < len(code); i++ {
+		op := code[i]
+		if op == JUMPDEST {
+			dests[i] = true
+		} else if op >= PUSH1 && op <= PUSH32 {
+			i += int(op - PUSH1 + 1)
+		}
+	}
+	return dests
+}
+
+// createAddress returns the address of a contract created with CREATE
+func createAddress(addr ethgo.Address, nonce uint64) ethgo.Address {
+	a := fastrlp.DefaultArenaPool.Get()
+	defer fastrlp.DefaultArenaPool.Put(a)
+
+	v := a.NewArray()
+	v.Set(a.NewCopyBytes(addr[:]))
+	v.Set(a.NewUint(nonce))
+
+	return ethgo.BytesToAddress(ethgo.Keccak256(v.MarshalTo(nil))[12:])
+}
+
+// create2Address returns the address of a contract created with CREATE2
+func create2Address(addr ethgo.Address, salt ethgo.Hash, code []byte) ethgo.Address {
+	return ethgo.BytesToAddress(ethgo.Keccak256([]byte{0xff}, addr[:], salt[:], ethgo.Keccak256(code))[12:])
+}
+
+// call executes the code of codeAddr in the context of address. The value is transferred from
+// the caller to the address if transfer is set. It returns the output and the remaining gas.
+func (e *evm) call(caller, address, codeAddr ethgo.Address, input []byte, gas uint64, value *big.Int, transfer, static bool) ([]byte, uint64, error) {
+	if e.depth > maxCallDepth {
+		return nil, gas, errDepth
+	}
+	if transfer && value.Sign() != 0 && e.state.getBalance(caller).Cmp(value) < 0 {
+		return nil, gas, errInsufficientBalance
+	}
+
+	snapshot := e.state.snapshot()
+	if transfer && value.Sign() != 0 {
+		e.state.subBalance(caller, value)
+		e.state.addBalance(address, value)
+	}
+
+	var (
+		ret []byte
+		err error
+	)
+	if p, ok := precompiles[codeAddr]; ok {
+		cost := p.gas(input)
+		if gas < cost {
+			gas, err = 0, errOutOfGas
+		} else {
+			gas -= cost
+			ret = p.run(input)
+		}
+	} else if code := e.state.getCode(codeAddr); len(code) != 0 {
+		f := &frame{
+			caller:   caller,
+			address:  address,
+			codeAddr: codeAddr,
+			value:    value,
+			input:    input,
+			code:     code,
+			gas:      gas,
+			static:   static,
+		}
+
+		e.depth++
+		ret, err = e.run(f)
+		e.depth--
+
+		gas = f.gas
+	}
+
+	if err != nil {
+		e.state.revertTo(snapshot)
+		if err != errReverted {
+			gas = 0
+		}
+	}
+	return ret, gas, err
+}
+
+// create deploys the contract with the init code at the address
+func (e *evm) create(caller ethgo.Address, code []byte, gas uint64, value *big.Int, address ethgo.Address) ([]byte, uint64, error) {
+	if e.depth > maxCallDepth {
+		return nil, gas, errDepth
+	}
+	if e.state.getBalance(caller).Cmp(value) < 0 {
+		return nil, gas, errInsufficientBalance
+	}
+	nonce := e.state.getNonce(caller)
+	if nonce+1 < nonce {
+		return nil, gas, errNonceMax
+	}
+	e.state.setNonce(caller, nonce+1)
+
+	e.state.warmAddress(address)
+	if e.state.getNonce(address) != 0 || len(e.state.getCode(address)) != 0 {
+		return nil, 0, errContractCollision
+	}
+
+	snapshot := e.state.snapshot()
+	e.state.getOrCreate(address)
+	e.state.clearStorage(address)
+	e.state.setNonce(address, 1)
+	e.state.markCreated(address)
+	if value.Sign() != 0 {
+		e.state.subBalance(caller, value)
+		e.state.addBalance(address, value)
+	}
+
+	f := &frame{
+		caller:   caller,
+		address:  address,
+		codeAddr: address,
+		value:    value,
+		code:     code,
+		gas:      gas,
+	}
+
+	e.depth++
+	ret, err := e.run(f)
+	e.depth--
+
+	if err == nil {
+		if len(ret) > maxCodeSize {
+			err = errMaxCodeSize
+		} else if len(ret) > 0 && ret[0] == 0xef {
+			err = errInvalidCode
+		} else if !f.useGas(uint64(len(ret)) * gasCodeDeposit) {
+			err = errOutOfGas
+		} else {
+			e.state.setCode(address, ret)
+		}
+	}
+	if err != nil {
+		e.state.revertTo(snapshot)
+		if err != errReverted {
+			f.gas = 0
+		}
+	}
+	return ret, f.gas, err
+}
+
+// accessGas charges the cost of accessing an account (EIP-2929)
+func (e *evm) accessGas(addr ethgo.Address) uint64 {
+	if e.state.warmAddress(addr) {
+		return gasWarmAccess
+	}
+	return gasColdAccount
+}
+
+// sstoreGas returns the cost and updates the refund counter of a storage write (EIP-2200, EIP-2929 and EIP-3529)
+func (e *evm) sstoreGas(addr ethgo.Address, key, val ethgo.Hash) uint64 {
+	var cost uint64
+	if !e.state.warmSlot(addr, key) {
+		cost = gasColdSload
+	}
+
+	current := e.state.getStorage(addr, key)
+	if current == val {
+		return cost + gasWarmAccess
+	}
+
+	zero := ethgo.Hash{}
+	original := e.state.getOriginalStorage(addr, key)
+	if original == current {
+		if original == zero {
+			return cost + gasSstoreSet
+		}
+		if val == zero {
+			e.state.addRefund(gasSstoreClearsRef)
+		}
+		return cost + gasSstoreReset
+	}
+
+	if original != zero {
+		if current == zero {
+			e.state.subRefund(gasSstoreClearsRef)
+		} else if val == zero {
+			e.state.addRefund(gasSstoreClearsRef)
+		}
+	}
+	if original == val {
+		if original == zero {
+			e.state.addRefund(gasSstoreSet - gasWarmAccess)
+		} else {
+			e.state.addRefund(gasSstoreReset - gasWarmAccess)
+		}
+	}
+	return cost + gasWarmAccess
+}
+
+// stackReqs returns the number of items popped and pushed by the opcode
+func stackReqs(op byte) (int, int, bool) {
+	switch {
+	case op >= PUSH1 && op <= PUSH32:
+		return 0, 1, true
+	case op >= DUP1 && op <= DUP16:
+		n := int(op-DUP1) + 1
+		return n, n + 1, true
+	case op >= SWAP1 && op <= SWAP16:
+		n := int(op-SWAP1) + 2
+		return n, n, true
+	case op >= LOG0 && op <= LOG4:
+		return int(op-LOG0) + 2, 0, true
+	}
+
+	switch op {
+	case STOP, JUMPDEST, INVALID:
+		return 0, 0, true
+	case ADDRESS, ORIGIN, CALLER, CALLVALUE, CALLDATASIZE, CODESIZE, GASPRICE, RETURNDATASIZE,
+		COINBASE, TIMESTAMP, NUMBER, PREVRANDAO, GASLIMIT, CHAINID, SELFBALANCE, BASEFEE,
+		BLOBBASEFEE, PC, MSIZE, GAS, PUSH0:
+		return 0, 1, true
+	case ISZERO, NOT, BALANCE, CALLDATALOAD, EXTCODESIZE, EXTCODEHASH, BLOCKHASH, BLOBHASH,
+		MLOAD, SLOAD, TLOAD:
+		return 1, 1, true
+	case POP, JUMP, SELFDESTRUCT:
+		return 1, 0, true
+	case ADD, MUL, SUB, DIV, SDIV, MOD, SMOD, EXP, SIGNEXTEND, LT, GT, SLT, SGT, EQ, AND, OR,
+		XOR, BYTE, SHL, SHR, SAR, KECCAK256:
+		return 2, 1, true
+	case MSTORE, MSTORE8, SSTORE, JUMPI, TSTORE, RETURN, REVERT:
+		return 2, 0, true
+	case ADDMOD, MULMOD, CREATE:
+		return 3, 1, true
+	case CALLDATACOPY, CODECOPY, RETURNDATACOPY, MCOPY:
+		return 3, 0, true
+	case CREATE2:
+		return 4, 1, true
+	case EXTCODECOPY:
+		return 4, 0, true
+	case DELEGATECALL, STATICCALL:
+		return 6, 1, true
+	case CALL, CALLCODE:
+		return 7, 1, true
+	}
+	return 0, 0, false
+}
+
+// staticGas returns the constant gas of the opcode
+func staticGas(op byte) uint64 {
+	switch {
+	case op >= PUSH1 && op <= PUSH32, op >= DUP1 && op <= DUP16, op >= SWAP1 && op <= SWAP16:
+		return gasVeryLow
+	case op >= LOG0 && op <= LOG4:
+		return gasLog + gasLogTopic*uint64(op-LOG0)
+	}
+
+	switch op {
+	case STOP, RETURN, REVERT, INVALID:
+		return gasZero
+	case ADDRESS, ORIGIN, CALLER, CALLVALUE, CALLDATASIZE, CODESIZE, GASPRICE, RETURNDATASIZE,
+		COINBASE, TIMESTAMP, NUMBER, PREVRANDAO, GASLIMIT, CHAINID, BASEFEE, BLOBBASEFEE,
+		POP, PC, MSIZE, GAS, PUSH0:
+		return gasBase
+	case ADD, SUB, NOT, LT, GT, SLT, SGT, EQ, ISZERO, AND, OR, XOR, BYTE, SHL, SHR, SAR,
+		CALLDATALOAD, MLOAD, MSTORE, MSTORE8, CALLDATACOPY, CODECOPY, RETURNDATACOPY, MCOPY, BLOBHASH:
+		return gasVeryLow
+	case MUL, DIV, SDIV, MOD, SMOD, SIGNEXTEND, SELFBALANCE:
+		return gasLow
+	case ADDMOD, MULMOD, JUMP:
+		return gasMid
+	case JUMPI, EXP:
+		return gasHigh
+	case KECCAK256:
+		return gasKeccak256
+	case BLOCKHASH:
+		return gasBlockHash
+	case JUMPDEST:
+		return gasJumpDest
+	case TLOAD, TSTORE:
+		return gasWarmAccess
+	case CREATE, CREATE2:
+		return gasCreate
+	case SELFDESTRUCT:
+		return gasSelfDestruct
+	}
+	// the access costs (BALANCE, SLOAD, CALL...) are dynamic
+	return 0
+}
+
+// run executes the code of the frame
+func (e *evm) run(f *frame) ([]byte, error) {
+	f.jumpdests = jumpdests(f.code)
+
+	for f.pc < uint64(len(f.code)) {
+		op := f.code[f.pc]
+
+		pops, pushes, ok := stackReqs(op)
+		if !ok {
+			return nil, errInvalidOpcode
+		}
+		if len(f.stack) < pops {
+			return nil, errStackUnderflow
+		}
+		if len(f.stack)-pops+pushes > maxStackSize {
+			return nil, errStackOverflow
+		}
+		if !f.useGas(staticGas(op)) {
+			return nil, errOutOfGas
+		}
+
+		ret, stop, err := e.execute(f, op)
+		if err != nil {
+			return ret, err
+		}
+		if stop {
+			return ret, nil
+		}
+		f.pc++
+	}
+	return nil, nil
+}
+
+// execute runs one opcode. It returns the output and true if the execution stops.
+func (e *evm) execute(f *frame, op byte) ([]byte, bool, error) {
+	switch {
+	case op == PUSH0:
+		f.push(new(big.Int))
+		return nil, false, nil
+
+	case op >= PUSH1 && op <= PUSH32:
+		n := uint64(op-PUSH1) + 1
+		start := f.pc + 1
+		data := make([]byte, n)
+		if start < uint64(len(f.code)) {
+			copy(data, f.code[start:])
+		}
+		f.push(new(big.Int).SetBytes(data))
+		f.pc += n
+		return nil, false, nil
+
+	case op >= DUP1 && op <= DUP16:
+		f.push(new(big.Int).Set(f.peek(int(op - DUP1))))
+		return nil, false, nil
+
+	case op >= SWAP1 && op <= SWAP16:
+		n := len(f.stack) - 1
+		m := n - int(op-SWAP1) - 1
+		f.stack[n], f.stack[m] = f.stack[m], f.stack[n]
+		return nil, false, nil
+
+	case op >= LOG0 && op <= LOG4:
+		if f.static {
+			return nil, false, errWriteProtection
+		}
+		offset, size := f.pop(), f.pop()
+		topics := make([]ethgo.Hash, int(op-LOG0))
+		for i := range topics {
+			topics[i] = bigToHash(f.pop())
+		}
+		off, sz, ok := f.expandMemory(offset, size)
+		if !ok || !f.useGas(sz*gasLogData) {
+			return nil, false, errOutOfGas
+		}
+		e.state.addLog(&ethgo.Log{
+			Address: f.address,
+			Topics:  topics,
+			Data:    append([]byte{}, f.memory[off:off+sz]...),
+		})
+		return nil, false, nil
+	}
+
+	switch op {
+	case STOP:
+		return nil, true, nil
+
+	// arithmetic
+	case ADD:
+		a, b := f.pop(), f.pop()
+		f.push(u256(a.Add(a, b)))
+	case MUL:
+		a, b := f.pop(), f.pop()
+		f.push(u256(a.Mul(a, b)))
+	case SUB:
+		a, b := f.pop(), f.pop()
+		f.push(u256(a.Sub(a, b)))
+	case DIV:
+		a, b := f.pop(), f.pop()
+		if b.Sign() == 0 {
+			f.push(new(big.Int))
+		} else {
+			f.push(a.Div(a, b))
+		}
+	case SDIV:
+		a, b := toSigned(f.pop()), toSigned(f.pop())
+		if b.Sign() == 0 {
+			f.push(new(big.Int))
+		} else {
+			f.push(u256(a.Quo(a, b)))
+		}
+	case MOD:
+		a, b := f.pop(), f.pop()
+		if b.Sign() == 0 {
+			f.push(new(big.Int))
+		} else {
+			f.push(a.Mod(a, b))
+		}
+	case SMOD:
+		a, b := toSigned(f.pop()), toSigned(f.pop())
+		if b.Sign() == 0 {
+			f.push(new(big.Int))
+		} else {
+			f.push(u256(a.Rem(a, b)))
+		}
+	case ADDMOD:
+		a, b, n := f.pop(), f.pop(), f.pop()
+		if n.Sign() == 0 {
+			f.push(new(big.Int))
+		} else {
+			a.Add(a, b)
+			f.push(a.Mod(a, n))
+		}
+	case MULMOD:
+		a, b, n := f.pop(), f.pop(), f.pop()
+		if n.Sign() == 0 {
+			f.push(new(big.Int))
+		} else {
+			a.Mul(a, b)
+			f.push(a.Mod(a, n))
+		}
+	case EXP:
+		base, exp := f.pop(), f.pop()
+		if !f.useGas(gasExpByte * uint64((exp.BitLen()+7)/8)) {
+			return nil, false, errOutOfGas
+		}
+		f.push(base.Exp(base, exp, tt256))
+	case SIGNEXTEND:
+		b, x := f.pop(), f.pop()
+		if b.Cmp(big.NewInt(31)) < 0 {
+			bit := uint(b.Uint64()*8 + 7)
+			mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), bit+1), big.NewInt(1))
+			if x.Bit(int(bit)) == 1 {
+				x.Or(x, new(big.Int).Xor(tt256m1, mask))
+			} else {
+				x.And(x, mask)
+			}
+		}
+		f.push(x)
+
+	// comparison and bitwise
+	case LT:
+		a, b := f.pop(), f.pop()
+		f.push(boolToBig(a.Cmp(b) < 0))
+	case GT:
+		a, b := f.pop(), f.pop()
+		f.push(boolToBig(a.Cmp(b) > 0))
+	case SLT:
+		a, b := toSigned(f.pop()), toSigned(f.pop())
+		f.push(boolToBig(a.Cmp(b) < 0))
+	case SGT:
+		a, b := toSigned(f.pop()), toSigned(f.pop())
+		f.push(boolToBig(a.Cmp(b) > 0))
+	case EQ:
+		a, b := f.pop(), f.pop()
+		f.push(boolToBig(a.Cmp(b) == 0))
+	case ISZERO:
+		f.push(boolToBig(f.pop().Sign() == 0))
+	case AND:
+		a, b := f.pop(), f.pop()
+		f.push(a.And(a, b))
+	case OR:
+		a, b := f.pop(), f.pop()
+		f.push(a.Or(a, b))
+	case XOR:
+		a, b := f.pop(), f.pop()
+		f.push(a.Xor(a, b))
+	case NOT:
+		a := f.pop()
+		f.push(a.Xor(a, tt256m1))
+	case BYTE:
+		i, x := f.pop(), f.pop()
+		if i.Cmp(big.NewInt(32)) < 0 {
+			f.push(big.NewInt(int64(bigToHash(x)[i.Uint64()])))
+		} else {
+			f.push(new(big.Int))
+		}
+	case SHL:
+		shift, val := f.pop(), f.pop()
+		if shift.Cmp(big.NewInt(256)) < 0 {
+			f.push(u256(val.Lsh(val, uint(shift.Uint64()))))
+		} else {
+			f.push(new(big.Int))
+		}
+	case SHR:
+		shift, val := f.pop(), f.pop()
+		if shift.Cmp(big.NewInt(256)) < 0 {
+			f.push(val.Rsh(val, uint(shift.Uint64())))
+		} else {
+			f.push(new(big.Int))
+		}
+	case SAR:
+		shift, val := f.pop(), toSigned(f.pop())
+		if shift.Cmp(big.NewInt(256)) >= 0 {
+			shift = big.NewInt(256)
+		}
+		f.push(u256(val.Rsh(val, uint(shift.Uint64()))))
+
+	case KECCAK256:
+		offset, size := f.pop(), f.pop()
+		off, sz, ok := f.expandMemory(offset, size)
+		if !ok || !f.useGas(gasKeccak256Word*words(sz)) {
+			return nil, false, errOutOfGas
+		}
+		f.push(new(big.Int).SetBytes(ethgo.Keccak256(f.memory[off : off+sz])))
+
+	// environment
+	case ADDRESS:
+		f.push(new(big.Int).SetBytes(f.address[:]))
+	case BALANCE:
+		addr := bigToAddress(f.pop())
+		if !f.useGas(e.accessGas(addr)) {
+			return nil, false, errOutOfGas
+		}
+		f.push(e.state.getBalance(addr))
+	case ORIGIN:
+		f.push(new(big.Int).SetBytes(e.origin[:]))
+	case CALLER:
+		f.push(new(big.Int).SetBytes(f.caller[:]))
+	case CALLVALUE:
+		f.push(new(big.Int).Set(f.value))
+	case CALLDATALOAD:
+		f.push(new(big.Int).SetBytes(getData(f.input, f.pop(), 32)))
+	case CALLDATASIZE:
+		f.push(big.NewInt(int64(len(f.input))))
+	case CALLDATACOPY, CODECOPY, RETURNDATACOPY:
+		memOffset, dataOffset, size := f.pop(), f.pop(), f.pop()
+
+		var data []byte
+		switch op {
+		case CALLDATACOPY:
+			data = f.input
+		case CODECOPY:
+			data = f.code
+		case RETURNDATACOPY:
+			data = f.returnData
+			end := new(big.Int).Add(dataOffset, size)
+			if !end.IsUint64() || end.Uint64() > uint64(len(data)) {
+				return nil, false, errReturnDataOutOfBounds
+			}
+		}
+
+		off, sz, ok := f.expandMemory(memOffset, size)
+		if !ok || !f.useGas(gasCopyWord*words(sz)) {
+			return nil, false, errOutOfGas
+		}
+		copy(f.memory[off:off+sz], getData(data, dataOffset, sz))
+	case CODESIZE:
+		f.push(big.NewInt(int64(len(f.code))))
+	case GASPRICE:
+		f.push(new(big.Int).Set(e.price))
+	case EXTCODESIZE:
+		addr := bigToAddress(f.pop())
+		if !f.useGas(e.accessGas(addr)) {
+			return nil, false, errOutOfGas
+		}
+		f.push(big.NewInt(int64(len(e.state.getCode(addr)))))
+	case EXTCODECOPY:
+		addr, memOffset, codeOffset, size := bigToAddress(f.pop()), f.pop(), f.pop(), f.pop()
+		if !f.useGas(e.accessGas(addr)) {
+			return nil, false, errOutOfGas
+		}
+		off, sz, ok := f.expandMemory(memOffset, size)
+		if !ok || !f.useGas(gasCopyWord*words(sz)) {
+			return nil, false, errOutOfGas
+		}
+		copy(f.memory[off:off+sz], getData(e.state.getCode(addr), codeOffset, sz))
+	case RETURNDATASIZE:
+		f.push(big.NewInt(int64(len(f.returnData))))
+	case EXTCODEHASH:
+		addr := bigToAddress(f.pop())
+		if !f.useGas(e.accessGas(addr)) {
+			return nil, false, errOutOfGas
+		}
+		f.push(hashToBig(e.state.getCodeHash(addr)))
+
+	// block
+	case BLOCKHASH:
+		num := f.pop()
+		if num.IsUint64() && num.Uint64() < e.block.number && e.block.number-num.Uint64() <= 256 {
+			f.push(hashToBig(e.block.getHash(num.Uint64())))
+		} else {
+			f.push(new(big.Int))
+		}
+	case COINBASE:
+		f.push(new(big.Int).SetBytes(e.block.coinbase[:]))
+	case TIMESTAMP:
+		f.push(new(big.Int).SetUint64(e.block.timestamp))
+	case NUMBER:
+		f.push(new(big.Int).SetUint64(e.block.number))
+	case PREVRANDAO:
+		f.push(hashToBig(e.block.prevRandao))
+	case GASLIMIT:
+		f.push(new(big.Int).SetUint64(e.block.gasLimit))
+	case CHAINID:
+		f.push(new(big.Int).Set(e.block.chainID))
+	case SELFBALANCE:
+		f.push(e.state.getBalance(f.address))
+	case BASEFEE:
+		f.push(new(big.Int).Set(e.block.baseFee))
+	case BLOBHASH:
+		// there are no blob transactions
+		f.pop()
+		f.push(new(big.Int))
+	case BLOBBASEFEE:
+		f.push(big.NewInt(1))
+
+	// stack, memory, storage and flow
+	case POP:
+		f.pop()
+	case MLOAD:
+		off, _, ok := f.expandMemory(f.pop(), big.NewInt(32))
+		if !ok {
+			return nil, false, errOutOfGas
+		}
+		f.push(new(big.Int).SetBytes(f.memory[off : off+32]))
+	case MSTORE:
+		offset, val := f.pop(), f.pop()
+		off, _, ok := f.expandMemory(offset, big.NewInt(32))
+		if !ok {
+			return nil, false, errOutOfGas
+		}
+		val.FillBytes(f.memory[off : off+32])
+	case MSTORE8:
+		offset, val := f.pop(), f.pop()
+		off, _, ok := f.expandMemory(offset, big.NewInt(1))
+		if !ok {
+			return nil, false, errOutOfGas
+		}
+		f.memory[off] = byte(val.Uint64() & 0xff)
+	case SLOAD:
+		key := bigToHash(f.pop())
+		cost := uint64(gasWarmAccess)
+		if !e.state.warmSlot(f.address, key) {
+			cost = gasColdSload
+		}
+		if !f.useGas(cost) {
+			return nil, false, errOutOfGas
+		}
+		f.push(hashToBig(e.state.getStorage(f.address, key)))
+	case SSTORE:
+		if f.static {
+			return nil, false, errWriteProtection
+		}
+		if f.gas <= gasSstoreSentry {
+			return nil, false, errOutOfGas
+		}
+		key, val := bigToHash(f.pop()), bigToHash(f.pop())
+		if !f.useGas(e.sstoreGas(f.address, key, val)) {
+			return nil, false, errOutOfGas
+		}
+		e.state.setStorage(f.address, key, val)
+	case JUMP:
+		dest := f.pop()
+		if !dest.IsUint64() || dest.Uint64() >= uint64(len(f.code)) || !f.jumpdests[dest.Uint64()] {
+			return nil, false, errInvalidJump
+		}
+		f.pc = dest.Uint64() - 1
+	case JUMPI:
+		dest, cond := f.pop(), f.pop()
+		if cond.Sign() != 0 {
+			if !dest.IsUint64() || dest.Uint64() >= uint64(len(f.code)) || !f.jumpdests[dest.Uint64()] {
+				return nil, false, errInvalidJump
+			}
+			f.pc = dest.Uint64() - 1
+		}
+	case PC:
+		f.push(new(big.Int).SetUint64(f.pc))
+	case MSIZE:
+		f.push(big.NewInt(int64(len(f.memory))))
+	case GAS:
+		f.push(new(big.Int).SetUint64(f.gas))
+	case JUMPDEST:
+	case TLOAD:
+		f.push(hashToBig(e.state.getTransient(f.address, bigToHash(f.pop()))))
+	case TSTORE:
+		if f.static {
+			return nil, false, errWriteProtection
+		}
+		key, val := bigToHash(f.pop()), bigToHash(f.pop())
+		e.state.setTransient(f.address, key, val)
+	case MCOPY:
+		dst, src, size := f.pop(), f.pop(), f.pop()
+		if size.Sign() != 0 {
+			end := src
+			if dst.Cmp(src) > 0 {
+				end = dst
+			}
+			if _, _, ok := f.expandMemory(end, size); !ok {
+				return nil, false, errOutOfGas
+			}
+			if !f.useGas(gasCopyWord * words(size.Uint64())) {
+				return nil, false, errOutOfGas
+			}
+			sz := size.Uint64()
+			copy(f.memory[dst.Uint64():dst.Uint64()+sz], f.memory[src.Uint64():src.Uint64()+sz])
+		}
+
+	// system
+	case CREATE, CREATE2:
+		return e.opCreate(f, op)
+	case CALL, CALLCODE, DELEGATECALL, STATICCALL:
+		return e.opCall(f, op)
+	case RETURN, REVERT:
+		offset, size := f.pop(), f.pop()
+		off, sz, ok := f.expandMemory(offset, size)
+		if !ok {
+			return nil, false, errOutOfGas
+		}
+		ret := append([]byte{}, f.memory[off:off+sz]...)
+		if op == REVERT {
+			return ret, true, errReverted
+		}
+		return ret, true, nil
+	case INVALID:
+		return nil, false, errInvalidOpcode
+	case SELFDESTRUCT:
+		if f.static {
+			return nil, false, errWriteProtection
+		}
+		beneficiary := bigToAddress(f.pop())
+		var cost uint64
+		if !e.state.warmAddress(beneficiary) {
+			cost += gasColdAccount
+		}
+		if e.state.empty(beneficiary) && e.state.getBalance(f.address).Sign() != 0 {
+			cost += gasNewAccount
+		}
+		if !f.useGas(cost) {
+			return nil, false, errOutOfGas
+		}
+		e.state.selfDestruct(f.address, beneficiary)
+		return nil, true, nil
+	}
+	return nil, false, nil
+}
+
+func (e *evm) opCreate(f *frame, op byte) ([]byte, bool, error) {
+	if f.static {
+		return nil, false, errWriteProtection
+	}
+	value, offset, size := f.pop(), f.pop(), f.pop()
+	var salt *big.Int
+	if op == CREATE2 {
+		salt = f.pop()
+	}
+
+	off, sz, ok := f.expandMemory(offset, size)
+	if !ok || sz > maxInitCodeSize {
+		return nil, false, errOutOfGas
+	}
+	cost := gasInitCodeWord * words(sz)
+	if op == CREATE2 {
+		cost += gasKeccak256Word * words(sz)
+	}
+	if !f.useGas(cost) {
+		return nil, false, errOutOfGas
+	}
+	code := append([]byte{}, f.memory[off:off+sz]...)
+
+	var address ethgo.Address
+	if op == CREATE {
+		address = createAddress(f.address, e.state.getNonce(f.address))
+	} else {
+		address = create2Address(f.address, bigToHash(salt), code)
+	}
+
+	// all but one 64th of the gas
+	gas := f.gas - f.gas/64
+	f.gas -= gas
+
+	ret, gasLeft, err := e.create(f.address, code, gas, value, address)
+	f.gas += gasLeft
+
+	f.returnData = nil
+	if err == errReverted {
+		f.returnData = ret
+	}
+	if err != nil {
+		f.push(new(big.Int))
+	} else {
+		f.push(new(big.Int).SetBytes(address[:]))
+	}
+	return nil, false, nil
+}
+
+func (e *evm) opCall(f *frame, op byte) ([]byte, bool, error) {
+	requested, addr := f.pop(), bigToAddress(f.pop())
+	value := new(big.Int)
+	if op == CALL || op == CALLCODE {
+		value = f.pop()
+	}
+	inOffset, inSize, outOffset, outSize := f.pop(), f.pop(), f.pop(), f.pop()
+
+	if op == CALL && f.static && value.Sign() != 0 {
+		return nil, false, errWriteProtection
+	}
+
+	inOff, inSz, ok := f.expandMemory(inOffset, inSize)
+	if !ok {
+		return nil, false, errOutOfGas
+	}
+	outOff, outSz, ok := f.expandMemory(outOffset, outSize)
+	if !ok {
+		return nil, false, errOutOfGas
+	}
+
+	cost := e.accessGas(addr)
+	if value.Sign() != 0 {
+		cost += gasCallValue
+		if op == CALL && e.state.empty(addr) {
+			cost += gasNewAccount
+		}
+	}
+	if !f.useGas(cost) {
+		return nil, false, errOutOfGas
+	}
+
+	// all but one 64th of the gas
+	gas := f.gas - f.gas/64
+	if requested.IsUint64() && requested.Uint64() < gas {
+		gas = requested.Uint64()
+	}
+	f.gas -= gas
+	if value.Sign() != 0 {
+		gas += gasCallStipend
+	}
+
+	input := append([]byte{}, f.memory[inOff:inOff+inSz]...)
+
+	var (
+		ret     []byte
+		gasLeft uint64
+		err     error
+	)
+	switch op {
+	case CALL:
+		ret, gasLeft, err = e.call(f.address, addr, addr, input, gas, value, true, f.static)
+	case CALLCODE:
+		ret, gasLeft, err = e.call(f.address, f.address, addr, input, gas, value, true, f.static)
+	case DELEGATECALL:
+		ret, gasLeft, err = e.call(f.caller, f.address, addr, input, gas, f.value, false, f.static)
+	case STATICCALL:
+		ret, gasLeft, err = e.call(f.address, addr, addr, input, gas, value, true, true)
+	}
+	f.gas += gasLeft
+
+	if err == nil || err == errReverted {
+		copy(f.memory[outOff:outOff+outSz], ret)
+	}
+	f.returnData = ret
+	f.push(boolToBig(err == nil))
+	return nil, false, nil
+}
@@ -0,0 +1,182 @@
+package simulated
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/jsonrpc/codec"
+	"github.com/Ethernal-Tech/ethgo/wallet"
+	"github.com/stretchr/testify/require"
+)
+
+// asm builds the bytecode from opcodes (int) and raw push data ([]byte)
+func asm(items ...interface{}) []byte {
+	code := []byte{}
+	for _, item := range items {
+		switch obj := item.(type) {
+		case int:
+			code = append(code, byte(obj))
+		case []byte:
+			code = append(code, obj...)
+		default:
+			panic("unexpected asm item")
+		}
+	}
+	return code
+}
+
+// push returns the smallest PUSH instruction for the value
+func push(v uint64) []byte {
+	b := new(big.Int).SetUint64(v).Bytes()
+	if len(b) == 0 {
+		return []byte{PUSH0}
+	}
+	return append([]byte{byte(PUSH1 + len(b) - 1)}, b...)
+}
+
+// ret returns the 32 bytes word at the top of the stack
+var ret = []byte{PUSH0, MSTORE, PUSH1, 32, PUSH0, RETURN}
+
+func runCode(t *testing.T, code []byte, input []byte) ([]byte, error) {
+	t.Helper()
+
+	b := NewBackend()
+	addr := ethgo.Address{0x11}
+	b.SetCode(addr, code)
+
+	res, err := b.Call(&ethgo.CallMsg{From: ethgo.Address{0x22}, To: &addr, Data: input}, ethgo.Latest)
+	if err != nil {
+		return nil, err
+	}
+	return hex.DecodeString(res[2:])
+}
+
+func word(v *big.Int) []byte {
+	return ethgo.BytesToHash(u256(v).Bytes()).Bytes()
+}
+
+func TestEVM_Opcodes(t *testing.T) {
+	cases := []struct {
+		name string
+		code []byte
+		res  *big.Int
+	}{
+		{"add", asm(push(2), push(3), ADD, ret), big.NewInt(5)},
+		{"sub underflow", asm(push(1), push(0), SUB, ret), big.NewInt(-1)},
+		{"exp", asm(push(10), push(2), EXP, ret), big.NewInt(1024)},
+		{"sdiv", asm(push(2), push(10), push(0), SUB, SDIV, ret), big.NewInt(-5)},
+		{"sar", asm(push(16), push(0), SUB, push(2), SAR, ret), big.NewInt(-4)},
+		{"shl", asm(push(1), push(8), SHL, ret), big.NewInt(256)},
+		{"signextend", asm(push(0xff), push(0), SIGNEXTEND, ret), big.NewInt(-1)},
+		{"byte", asm(push(0xabcd), push(30), BYTE, ret), big.NewInt(0xab)},
+		{"slt", asm(push(1), push(1), push(0), SUB, SLT, ret), big.NewInt(1)},
+		{"push0", asm(PUSH0, ret), big.NewInt(0)},
+		{"sstore", asm(push(42), push(1), SSTORE, push(1), SLOAD, ret), big.NewInt(42)},
+		{"tstore", asm(push(42), push(1), TSTORE, push(1), TLOAD, ret), big.NewInt(42)},
+		{"mcopy", asm(push(0xabcd), PUSH0, MSTORE, push(32), PUSH0, push(32), MCOPY, push(32), MLOAD, ret), big.NewInt(0xabcd)},
+		{"chainid", asm(CHAINID, ret), new(big.Int).SetUint64(DefaultChainID)},
+		{"calldatasize", asm(CALLDATASIZE, ret), big.NewInt(3)},
+		{"jump", asm(push(4), JUMP, INVALID, JUMPDEST, push(7), ret), big.NewInt(7)},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			res, err := runCode(t, c.code, []byte{1, 2, 3})
+			require.NoError(t, err)
+			require.Equal(t, word(c.res), res)
+		})
+	}
+}
+
+func TestEVM_Revert(t *testing.T) {
+	code := asm(push(0xdead), PUSH0, MSTORE, push(2), push(30), REVERT)
+
+	_, err := runCode(t, code, nil)
+	require.ErrorIs(t, err, codec.ErrExecutionReverted)
+
+	var revertErr *codec.RevertError
+	require.True(t, errors.As(err, &revertErr))
+	require.Equal(t, []byte{0xde, 0xad}, revertErr.Data)
+}
+
+func TestEVM_InvalidJump(t *testing.T) {
+	_, err := runCode(t, asm(push(3), JUMP, STOP), nil)
+	require.Error(t, err)
+	require.NotErrorIs(t, err, codec.ErrExecutionReverted)
+}
+
+func TestEVM_Create2(t *testing.T) {
+	// the init code returns an empty runtime code
+	initCode := asm(PUSH0, PUSH0, RETURN)
+
+	var initWord [32]byte
+	copy(initWord[:], initCode)
+
+	code := asm(
+		PUSH32, initWord[:], PUSH0, MSTORE,
+		// create2(value, offset, size, salt)
+		push(5), push(uint64(len(initCode))), PUSH0, PUSH0, CREATE2,
+		ret,
+	)
+	res, err := runCode(t, code, nil)
+	require.NoError(t, err)
+
+	expected := create2Address(ethgo.Address{0x11}, ethgo.BytesToHash([]byte{5}), initCode)
+	require.Equal(t, expected, ethgo.BytesToAddress(res))
+}
+
+// callPrecompile returns a code that calls the precompile with the calldata and returns its output
+func callPrecompile(addr byte, outSize uint64) []byte {
+	return asm(
+		CALLDATASIZE, PUSH0, PUSH0, CALLDATACOPY,
+		// staticcall(gas, addr, in, insize, out, outsize)
+		push(outSize), PUSH0, CALLDATASIZE, PUSH0, push(uint64(addr)), GAS, STATICCALL,
+		POP, RETURNDATASIZE, PUSH0, RETURN,
+	)
+}
+
+func TestEVM_Precompiles(t *testing.T) {
+	t.Run("ecrecover", func(t *testing.T) {
+		key, err := wallet.GenerateKey()
+		require.NoError(t, err)
+
+		hash := ethgo.Keccak256([]byte("message"))
+		sig, err := key.Sign(hash)
+		require.NoError(t, err)
+
+		input := append([]byte{}, hash...)
+		input = append(input, ethgo.BytesToHash([]byte{sig[64] + 27}).Bytes()...)
+		input = append(input, sig[:64]...)
+
+		res, err := runCode(t, callPrecompile(1, 32), input)
+		require.NoError(t, err)
+		require.Equal(t, key.Address(), ethgo.BytesToAddress(res))
+
+		// an invalid v returns an empty output
+		input[63] = 30
+		res, err = runCode(t, callPrecompile(1, 32), input)
+		require.NoError(t, err)
+		require.Empty(t, res)
+	})
+
+	t.Run("sha256", func(t *testing.T) {
+		input := []byte("some data")
+		expected := sha256.Sum256(input)
+
+		res, err := runCode(t, callPrecompile(2, 32), input)
+		require.NoError(t, err)
+		require.Equal(t, expected[:], res)
+	})
+
+	t.Run("identity", func(t *testing.T) {
+		input := []byte{1, 2, 3, 4, 5}
+
+		res, err := runCode(t, callPrecompile(4, 5), input)
+		require.NoError(t, err)
+		require.Equal(t, input, res)
+	})
+}
@@ -0,0 +1,87 @@
+package simulated
+
+import (
+	"crypto/sha256"
+	"math/big"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/wallet"
+)
+
+// precompile is a contract implemented natively by the evm
+type precompile struct {
+	gas func(input []byte) uint64
+	run func(input []byte) []byte
+}
+
+var precompiles = map[ethgo.Address]*precompile{
+	ethgo.BytesToAddress([]byte{1}): {
+		gas: func(input []byte) uint64 { return 3000 },
+		run: runEcrecover,
+	},
+	ethgo.BytesToAddress([]byte{2}): {
+		gas: func(input []byte) uint64 { return 60 + 12*words(uint64(len(input))) },
+		run: func(input []byte) []byte {
+			h := sha256.Sum256(input)
+			return h[:]
+		},
+	},
+	ethgo.BytesToAddress([]byte{4}): {
+		gas: func(input []byte) uint64 { return 15 + 3*words(uint64(len(input))) },
+		run: func(input []byte) []byte {
+			return append([]byte{}, input...)
+		},
+	},
+}
+
+// secp256k1N is the order of the secp256k1 curve
+var secp256k1N, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+
+// runEcrecover recovers the address of the signer. The output is empty if the signature is not valid.
+func runEcrecover(input []byte) []byte {
+	input = rightPad(input, 128)
+
+	// v is a 32 bytes word that must be either 27 or 28
+	for _, b := range input[32:63] {
+		if b != 0 {
+			return nil
+		}
+	}
+	v := input[63]
+	if v != 27 && v != 28 {
+		return nil
+	}
+	r := new(big.Int).SetBytes(input[64:96])
+	s := new(big.Int).SetBytes(input[96:128])
+	if r.Sign() == 0 || s.Sign() == 0 || r.Cmp(secp256k1N) >= 0 || s.Cmp(secp256k1N) >= 0 {
+		return nil
+	}
+
+	sig := make([]byte, 65)
+	copy(sig, input[64:128])
+	sig[64] = v - 27
+
+	addr, err := wallet.Ecrecover(input[:32], sig)
+	if err != nil {
+		return nil
+	}
+	return leftPad(addr[:], 32)
+}
+
+func rightPad(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+	res := make([]byte, size)
+	copy(res, b)
+	return res
+}
+
+func leftPad(b []byte, size int) []byte {
+	if len(b) >= size {
+		return b
+	}
+	res := make([]byte, size)
+	copy(res[size-len(b):], b)
+	return res
+}
@@ -0,0 +1,360 @@
+package simulated
+
+import (
+	"math/big"
+
+	"github.com/Ethernal-Tech/ethgo"
+)
+
+var emptyCodeHash = ethgo.BytesToHash(ethgo.Keccak256(nil))
+
+type account struct {
+	nonce   uint64
+	balance *big.Int
+	code    []byte
+	storage map[ethgo.Hash]ethgo.Hash
+}
+
+func newAccount() *account {
+	return &account{
+		balance: new(big.Int),
+		storage: map[ethgo.Hash]ethgo.Hash{},
+	}
+}
+
+func (a *account) copy() *account {
+	aa := &account{
+		nonce:   a.nonce,
+		balance: new(big.Int).Set(a.balance),
+		code:    a.code,
+		storage: make(map[ethgo.Hash]ethgo.Hash, len(a.storage)),
+	}
+	for k, v := range a.storage {
+		aa.storage[k] = v
+	}
+	return aa
+}
+
+func (a *account) empty() bool {
+	return a.nonce == 0 && a.balance.Sign() == 0 && len(a.code) == 0
+}
+
+// state is the world state of the chain. The changes done during the execution of a transaction
+// are tracked in a journal so that they can be reverted when a call frame fails.
+type state struct {
+	accounts map[ethgo.Address]*account
+
+	// journal of the changes of the transaction
+	journal []func()
+
+	// values scoped to the transaction
+	original      map[ethgo.Address]map[ethgo.Hash]ethgo.Hash
+	transient     map[ethgo.Address]map[ethgo.Hash]ethgo.Hash
+	accessedAddrs map[ethgo.Address]struct{}
+	accessedSlots map[ethgo.Address]map[ethgo.Hash]struct{}
+	created       map[ethgo.Address]struct{}
+	destructed    map[ethgo.Address]struct{}
+	logs          []*ethgo.Log
+	refund        uint64
+}
+
+func newState() *state {
+	s := &state{
+		accounts: map[ethgo.Address]*account{},
+	}
+	s.resetTx()
+	return s
+}
+
+// copy returns a deep copy of the accounts of the state
+func (s *state) copy() *state {
+	ss := newState()
+	for addr, acct := range s.accounts {
+		ss.accounts[addr] = acct.copy()
+	}
+	return ss
+}
+
+// resetTx clears the values scoped to the transaction
+func (s *state) resetTx() {
+	s.journal = s.journal[:0]
+	s.original = map[ethgo.Address]map[ethgo.Hash]ethgo.Hash{}
+	s.transient = map[ethgo.Address]map[ethgo.Hash]ethgo.Hash{}
+	s.accessedAddrs = map[ethgo.Address]struct{}{}
+	s.accessedSlots = map[ethgo.Address]map[ethgo.Hash]struct{}{}
+	s.created = map[ethgo.Address]struct{}{}
+	s.destructed = map[ethgo.Address]struct{}{}
+	s.logs = nil
+	s.refund = 0
+}
+
+// finalizeTx removes the accounts destructed during the
+// transaction and clears the values scoped to the transaction
+func (s *state) finalizeTx() {
+	for addr := range s.destructed {
+		delete(s.accounts, addr)
+	}
+	s.resetTx()
+}
+
+func (s *state) snapshot() int {
+	return len(s.journal)
+}
+
+func (s *state) revertTo(snapshot int) {
+	for i := len(s.journal) - 1; i >= snapshot; i-- {
+		s.journal[i]()
+	}
+	s.journal = s.journal[:snapshot]
+}
+
+func (s *state) getAccount(addr ethgo.Address) *account {
+	return s.accounts[addr]
+}
+
+func (s *state) getOrCreate(addr ethgo.Address) *account {
+	if acct, ok := s.accounts[addr]; ok {
+		return acct
+	}
+	acct := newAccount()
+	s.accounts[addr] = acct
+	s.journal = append(s.journal, func() {
+		delete(s.accounts, addr)
+	})
+	return acct
+}
+
+func (s *state) exists(addr ethgo.Address) bool {
+	_, ok := s.accounts[addr]
+	return ok
+}
+
+// empty returns true if the account does not exist or it is empty (EIP-161)
+func (s *state) empty(addr ethgo.Address) bool {
+	acct, ok := s.accounts[addr]
+	return !ok || acct.empty()
+}
+
+func (s *state) getBalance(addr ethgo.Address) *big.Int {
+	if acct := s.getAccount(addr); acct != nil {
+		return new(big.Int).Set(acct.balance)
+	}
+	return new(big.Int)
+}
+
+func (s *state) setBalance(addr ethgo.Address, balance *big.Int) {
+	acct := s.getOrCreate(addr)
+	prev := acct.balance
+	acct.balance = new(big.Int).Set(balance)
+	s.journal = append(s.journal, func() {
+		acct.balance = prev
+	})
+}
+
+func (s *state) addBalance(addr ethgo.Address, amount *big.Int) {
+	s.setBalance(addr, new(big.Int).Add(s.getBalance(addr), amount))
+}
+
+func (s *state) subBalance(addr ethgo.Address, amount *big.Int) {
+	s.setBalance(addr, new(big.Int).Sub(s.getBalance(addr), amount))
+}
+
+func (s *state) getNonce(addr ethgo.Address) uint64 {
+	if acct := s.getAccount(addr); acct != nil {
+		return acct.nonce
+	}
+	return 0
+}
+
+func (s *state) setNonce(addr ethgo.Address, nonce uint64) {
+	acct := s.getOrCreate(addr)
+	prev := acct.nonce
+	acct.nonce = nonce
+	s.journal = append(s.journal, func() {
+		acct.nonce = prev
+	})
+}
+
+func (s *state) getCode(addr ethgo.Address) []byte {
+	if acct := s.getAccount(addr); acct != nil {
+		return acct.code
+	}
+	return nil
+}
+
+func (s *state) getCodeHash(addr ethgo.Address) ethgo.Hash {
+	acct := s.getAccount(addr)
+	if acct == nil || acct.empty() {
+		return ethgo.Hash{}
+	}
+	return ethgo.BytesToHash(ethgo.Keccak256(acct.code))
+}
+
+func (s *state) setCode(addr ethgo.Address, code []byte) {
+	acct := s.getOrCreate(addr)
+	prev := acct.code
+	acct.code = code
+	s.journal = append(s.journal, func() {
+		acct.code = prev
+	})
+}
+
+func (s *state) getStorage(addr ethgo.Address, key ethgo.Hash) ethgo.Hash {
+	if acct := s.getAccount(addr); acct != nil {
+		return acct.storage[key]
+	}
+	return ethgo.Hash{}
+}
+
+// getOriginalStorage returns the value of the slot at the beginning of the transaction
+func (s *state) getOriginalStorage(addr ethgo.Address, key ethgo.Hash) ethgo.Hash {
+	if slots, ok := s.original[addr]; ok {
+		if val, ok := slots[key]; ok {
+			return val
+		}
+	}
+	return s.getStorage(addr, key)
+}
+
+func (s *state) setStorage(addr ethgo.Address, key, val ethgo.Hash) {
+	acct := s.getOrCreate(addr)
+
+	slots, ok := s.original[addr]
+	if !ok {
+		slots = map[ethgo.Hash]ethgo.Hash{}
+		s.original[addr] = slots
+	}
+	if _, ok := slots[key]; !ok {
+		// the original value is kept for the whole transaction even if the change is reverted
+		slots[key] = acct.storage[key]
+	}
+
+	prev, found := acct.storage[key]
+	if val == (ethgo.Hash{}) {
+		delete(acct.storage, key)
+	} else {
+		acct.storage[key] = val
+	}
+	s.journal = append(s.journal, func() {
+		if found {
+			acct.storage[key] = prev
+		} else {
+			delete(acct.storage, key)
+		}
+	})
+}
+
+// clearStorage removes the storage of an account (i.e. when a contract is created
+// at the address of an account with balance)
+func (s *state) clearStorage(addr ethgo.Address) {
+	acct := s.getOrCreate(addr)
+	prev := acct.storage
+	acct.storage = map[ethgo.Hash]ethgo.Hash{}
+	s.journal = append(s.journal, func() {
+		acct.storage = prev
+	})
+}
+
+func (s *state) getTransient(addr ethgo.Address, key ethgo.Hash) ethgo.Hash {
+	return s.transient[addr][key]
+}
+
+func (s *state) setTransient(addr ethgo.Address, key, val ethgo.Hash) {
+	slots, ok := s.transient[addr]
+	if !ok {
+		slots = map[ethgo.Hash]ethgo.Hash{}
+		s.transient[addr] = slots
+	}
+	prev := slots[key]
+	slots[key] = val
+	s.journal = append(s.journal, func() {
+		slots[key] = prev
+	})
+}
+
+// warmAddress marks the address as accessed (EIP-2929) and
+// returns whether the address had been accessed already
+func (s *state) warmAddress(addr ethgo.Address) bool {
+	if _, ok := s.accessedAddrs[addr]; ok {
+		return true
+	}
+	s.accessedAddrs[addr] = struct{}{}
+	s.journal = append(s.journal, func() {
+		delete(s.accessedAddrs, addr)
+	})
+	return false
+}
+
+// warmSlot marks the storage slot as accessed (EIP-2929) and
+// returns whether the slot had been accessed already
+func (s *state) warmSlot(addr ethgo.Address, key ethgo.Hash) bool {
+	slots, ok := s.accessedSlots[addr]
+	if !ok {
+		slots = map[ethgo.Hash]struct{}{}
+		s.accessedSlots[addr] = slots
+	}
+	if _, ok := slots[key]; ok {
+		return true
+	}
+	slots[key] = struct{}{}
+	s.journal = append(s.journal, func() {
+		delete(slots, key)
+	})
+	return false
+}
+
+func (s *state) markCreated(addr ethgo.Address) {
+	s.created[addr] = struct{}{}
+	s.journal = append(s.journal, func() {
+		delete(s.created, addr)
+	})
+}
+
+// selfDestruct transfers the balance to the beneficiary. The account is only removed
+// if it was created in the same transaction (EIP-6780).
+func (s *state) selfDestruct(addr, beneficiary ethgo.Address) {
+	if addr != beneficiary {
+		balance := s.getBalance(addr)
+		s.subBalance(addr, balance)
+		s.addBalance(beneficiary, balance)
+	}
+
+	if _, ok := s.created[addr]; !ok {
+		return
+	}
+	if addr == beneficiary {
+		// the ether is burnt
+		s.setBalance(addr, new(big.Int))
+	}
+	s.destructed[addr] = struct{}{}
+	s.journal = append(s.journal, func() {
+		delete(s.destructed, addr)
+	})
+}
+
+func (s *state) addLog(log *ethgo.Log) {
+	s.logs = append(s.logs, log)
+	num := len(s.logs)
+	s.journal = append(s.journal, func() {
+		s.logs = s.logs[:num-1]
+	})
+}
+
+func (s *state) addRefund(gas uint64) {
+	prev := s.refund
+	s.refund += gas
+	s.journal = append(s.journal, func() {
+		s.refund = prev
+	})
+}
+
+func (s *state) subRefund(gas uint64) {
+	prev := s.refund
+	if gas > s.refund {
+		panic("refund counter below zero")
+	}
+	s.refund -= gas
+	s.journal = append(s.journal, func() {
+		s.refund = prev
+	})
+}
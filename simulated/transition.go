@@ -0,0 +1,139 @@
+package simulated
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/jsonrpc/codec"
+)
+
+// message is a transaction or a call to execute on top of the state
+type message struct {
+	from       ethgo.Address
+	to         *ethgo.Address
+	nonce      uint64
+	value      *big.Int
+	gas        uint64
+	gasPrice   *big.Int
+	input      []byte
+	accessList ethgo.AccessList
+
+	// skipChecks disables the nonce check (eth_call and eth_estimateGas)
+	skipChecks bool
+}
+
+// executionResult is the result of applying a message
+type executionResult struct {
+	gasUsed         uint64
+	ret             []byte
+	err             error
+	contractAddress ethgo.Address
+	logs            []*ethgo.Log
+}
+
+// intrinsicGas returns the gas charged before the execution of the message
+func intrinsicGas(msg *message) uint64 {
+	gas := uint64(gasTx)
+	if msg.to == nil {
+		gas = gasTxCreate
+		gas += gasInitCodeWord * words(uint64(len(msg.input)))
+	}
+	for _, b := range msg.input {
+		if b == 0 {
+			gas += gasTxDataZero
+		} else {
+			gas += gasTxDataNonZero
+		}
+	}
+	for _, entry := range msg.accessList {
+		gas += gasTxAccessListAddress
+		gas += gasTxAccessListSlot * uint64(len(entry.Storage))
+	}
+	return gas
+}
+
+// applyMessage executes the message. An error is returned if the message is not valid
+// (i.e. not enough funds) while the errors of the execution are part of the result.
+func applyMessage(st *state, block *blockContext, msg *message) (*executionResult, error) {
+	st.resetTx()
+	defer st.finalizeTx()
+
+	if !msg.skipChecks {
+		nonce := st.getNonce(msg.from)
+		if msg.nonce < nonce {
+			return nil, fmt.Errorf("%w: address %s, tx: %d state: %d", codec.ErrNonceTooLow, msg.from, msg.nonce, nonce)
+		}
+		if msg.nonce > nonce {
+			return nil, fmt.Errorf("%w: address %s, tx: %d state: %d", codec.ErrNonceTooHigh, msg.from, msg.nonce, nonce)
+		}
+	}
+	if msg.to == nil && len(msg.input) > maxInitCodeSize {
+		return nil, fmt.Errorf("max initcode size exceeded: code size %d limit %d", len(msg.input), maxInitCodeSize)
+	}
+
+	intrinsic := intrinsicGas(msg)
+	if msg.gas < intrinsic {
+		return nil, fmt.Errorf("intrinsic gas too low: have %d, want %d", msg.gas, intrinsic)
+	}
+
+	gasCost := new(big.Int).Mul(new(big.Int).SetUint64(msg.gas), msg.gasPrice)
+	if balance := st.getBalance(msg.from); balance.Cmp(new(big.Int).Add(gasCost, msg.value)) < 0 {
+		return nil, fmt.Errorf("%w for gas * price + value: address %s have %s want %s", codec.ErrInsufficientFunds, msg.from, balance, new(big.Int).Add(gasCost, msg.value))
+	}
+	st.subBalance(msg.from, gasCost)
+
+	// warm the accounts (EIP-2929 and EIP-3651)
+	st.warmAddress(msg.from)
+	st.warmAddress(block.coinbase)
+	if msg.to != nil {
+		st.warmAddress(*msg.to)
+	}
+	for addr := range precompiles {
+		st.warmAddress(addr)
+	}
+	for _, entry := range msg.accessList {
+		st.warmAddress(entry.Address)
+		for _, key := range entry.Storage {
+			st.warmSlot(entry.Address, key)
+		}
+	}
+
+	e := &evm{
+		state:  st,
+		block:  block,
+		origin: msg.from,
+		price:  msg.gasPrice,
+	}
+
+	res := &executionResult{}
+	gas := msg.gas - intrinsic
+
+	var gasLeft uint64
+	if msg.to == nil {
+		res.contractAddress = createAddress(msg.from, st.getNonce(msg.from))
+		res.ret, gasLeft, res.err = e.create(msg.from, msg.input, gas, msg.value, res.contractAddress)
+	} else {
+		st.setNonce(msg.from, st.getNonce(msg.from)+1)
+		res.ret, gasLeft, res.err = e.call(msg.from, *msg.to, *msg.to, msg.input, gas, msg.value, true, false)
+	}
+
+	// refund up to a fifth of the gas used (EIP-3529)
+	gasUsed := msg.gas - gasLeft
+	refund := st.refund
+	if refund > gasUsed/5 {
+		refund = gasUsed / 5
+	}
+	gasLeft += refund
+	res.gasUsed = gasUsed - refund
+
+	st.addBalance(msg.from, new(big.Int).Mul(new(big.Int).SetUint64(gasLeft), msg.gasPrice))
+
+	// the base fee is burnt and the miner receives the tip
+	if tip := new(big.Int).Sub(msg.gasPrice, block.baseFee); tip.Sign() > 0 {
+		st.addBalance(block.coinbase, tip.Mul(tip, new(big.Int).SetUint64(res.gasUsed)))
+	}
+
+	res.logs = st.logs
+	return res, nil
+}
@@ -13,8 +13,15 @@ var defaultArena fastjson.ArenaPool
 // MarshalJSON implements the marshal interface
 func (l *Log) MarshalJSON() ([]byte, error) {
 	a := defaultArena.Get()
-	defer a.Reset()
 
+	v := l.marshalJSON(a)
+	res := v.MarshalTo(nil)
+	a.Reset()
+	defaultArena.Put(a)
+	return res, nil
+}
+
+func (l *Log) marshalJSON(a *fastjson.Arena) *fastjson.Value {
 	o := a.NewObject()
 	if l.Removed {
 		o.Set("removed", a.NewTrue())
@@ -34,10 +41,7 @@ func (l *Log) MarshalJSON() ([]byte, error) {
 		vv.SetArrayItem(indx, a.NewString(topic.String()))
 	}
 	o.Set("topics", vv)
-
-	res := o.MarshalTo(nil)
-	defaultArena.Put(a)
-	return res, nil
+	return o
 }
 
 // MarshalJSON implements the marshal interface
@@ -47,7 +51,6 @@ func (t *Block) MarshalJSON() ([]byte, error) {
 	}
 
 	a := defaultArena.Get()
-	defer a.Reset()
 
 	o := a.NewObject()
 	o.Set("number", a.NewString(fmt.Sprintf("0x%x", t.Number)))
@@ -96,6 +99,7 @@ func (t *Block) MarshalJSON() ([]byte, error) {
 	}
 
 	res := o.MarshalTo(nil)
+	a.Reset()
 	defaultArena.Put(a)
 	return res, nil
 }
@@ -103,10 +107,10 @@ func (t *Block) MarshalJSON() ([]byte, error) {
 // MarshalJSON implements the Marshal interface.
 func (t *Transaction) MarshalJSON() ([]byte, error) {
 	a := defaultArena.Get()
-	defer a.Reset()
 
 	v := t.marshalJSON(a)
 	res := v.MarshalTo(nil)
+	a.Reset()
 	defaultArena.Put(a)
 	return res, nil
 }
@@ -187,10 +191,51 @@ func (t *AccessList) marshalJSON(a *fastjson.Arena) *fastjson.Value {
 	return arr
 }
 
+// MarshalJSON implements the Marshal interface.
+func (r *Receipt) MarshalJSON() ([]byte, error) {
+	a := defaultArena.Get()
+
+	o := a.NewObject()
+	o.Set("transactionHash", a.NewString(r.TransactionHash.String()))
+	o.Set("transactionIndex", a.NewString(fmt.Sprintf("0x%x", r.TransactionIndex)))
+	o.Set("blockHash", a.NewString(r.BlockHash.String()))
+	o.Set("blockNumber", a.NewString(fmt.Sprintf("0x%x", r.BlockNumber)))
+	o.Set("from", a.NewString(r.From.String()))
+	if r.To == nil {
+		o.Set("to", a.NewNull())
+	} else {
+		o.Set("to", a.NewString(r.To.String()))
+	}
+	if r.ContractAddress == ZeroAddress {
+		o.Set("contractAddress", a.NewNull())
+	} else {
+		o.Set("contractAddress", a.NewString(r.ContractAddress.String()))
+	}
+	o.Set("gasUsed", a.NewString(fmt.Sprintf("0x%x", r.GasUsed)))
+	o.Set("cumulativeGasUsed", a.NewString(fmt.Sprintf("0x%x", r.CumulativeGasUsed)))
+	bloom := r.LogsBloom
+	if len(bloom) == 0 {
+		// the bloom is always 256 bytes
+		bloom = make([]byte, 256)
+	}
+	o.Set("logsBloom", a.NewString("0x"+hex.EncodeToString(bloom)))
+	o.Set("status", a.NewString(fmt.Sprintf("0x%x", r.Status)))
+
+	logs := a.NewArray()
+	for indx, log := range r.Logs {
+		logs.SetArrayItem(indx, log.marshalJSON(a))
+	}
+	o.Set("logs", logs)
+
+	res := o.MarshalTo(nil)
+	a.Reset()
+	defaultArena.Put(a)
+	return res, nil
+}
+
 // MarshalJSON implements the Marshal interface.
 func (c *CallMsg) MarshalJSON() ([]byte, error) {
 	a := defaultArena.Get()
-	defer a.Reset()
 
 	o := a.NewObject()
 	o.Set("from", a.NewString(c.From.String()))
@@ -211,6 +256,7 @@ func (c *CallMsg) MarshalJSON() ([]byte, error) {
 	}
 
 	res := o.MarshalTo(nil)
+	a.Reset()
 	defaultArena.Put(a)
 	return res, nil
 }
@@ -218,7 +264,6 @@ func (c *CallMsg) MarshalJSON() ([]byte, error) {
 // MarshalJSON implements the Marshal interface.
 func (l *LogFilter) MarshalJSON() ([]byte, error) {
 	a := defaultArena.Get()
-	defer a.Reset()
 
 	o := a.NewObject()
 	if len(l.Address) == 1 {
@@ -228,6 +273,7 @@ func (l *LogFilter) MarshalJSON() ([]byte, error) {
 		for indx, addr := range l.Address {
 			v.SetArrayItem(indx, a.NewString(addr.String()))
 		}
+		o.Set("address", v)
 	}
 
 	v := a.NewArray()
@@ -264,13 +310,13 @@ func (l *LogFilter) MarshalJSON() ([]byte, error) {
 	}
 
 	res := o.MarshalTo(nil)
+	a.Reset()
 	defaultArena.Put(a)
 	return res, nil
 }
 
 func (s StateOverride) MarshalJSON() ([]byte, error) {
 	a := defaultArena.Get()
-	defer a.Reset()
 
 	o := a.NewObject()
 	for addr, obj := range s {
@@ -302,6 +348,7 @@ func (s StateOverride) MarshalJSON() ([]byte, error) {
 	}
 
 	res := o.MarshalTo(nil)
+	a.Reset()
 	defaultArena.Put(a)
 
 	return res, nil
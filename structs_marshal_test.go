@@ -97,6 +97,19 @@ func TestLogFilter_MarshalJSON(t *testing.T) {
 	}
 }
 
+func TestLogFilter_MarshalJSON_MultipleAddresses(t *testing.T) {
+	filter := &LogFilter{
+		Address: []Address{HexToAddress("0x1"), HexToAddress("0x2")},
+	}
+
+	output, err := filter.MarshalJSON()
+	require.NoError(t, err)
+
+	reverseOutput := &LogFilter{}
+	require.NoError(t, json.Unmarshal(output, reverseOutput))
+	assert.Equal(t, filter.Address, reverseOutput.Address)
+}
+
 func TestMarshal_StateOverride(t *testing.T) {
 	nonce := uint64(1)
 	code := []byte{0x1}
@@ -118,3 +118,20 @@ func TestReceipt_Unmarshal(t *testing.T) {
 		assert.NoError(t, receipt.UnmarshalJSON(c))
 	}
 }
+
+func TestReceipt_MarshalJSON(t *testing.T) {
+	var cases []json.RawMessage
+	assert.NoError(t, json.Unmarshal(receiptsFixtures, &cases))
+
+	for _, c := range cases {
+		receipt := &Receipt{}
+		assert.NoError(t, receipt.UnmarshalJSON(c))
+
+		data, err := receipt.MarshalJSON()
+		assert.NoError(t, err)
+
+		receipt2 := &Receipt{}
+		assert.NoError(t, receipt2.UnmarshalJSON(data))
+		assert.Equal(t, receipt, receipt2)
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"sync"
 
 	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/jsonrpc/codec"
 )
 
 type mockCall int
@@ -168,7 +169,8 @@ func (d *MockClient) GetBlockByNumber(i ethgo.BlockNumber, full bool) (*ethgo.Bl
 			}
 			return d.blockByNumberLock(d.num)
 		default:
-			return nil, fmt.Errorf("getBlockByNumber query not supported")
+			// like a node that does not support the tag
+			return nil, &codec.ErrorObject{Code: codec.CodeInvalidParams, Message: fmt.Sprintf("invalid argument 0: block tag %s not supported", i)}
 		}
 	}
 	return d.blockByNumberLock(uint64(i))
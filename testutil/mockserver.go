@@ -0,0 +1,547 @@
+package testutil
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/jsonrpc/codec"
+	"github.com/gorilla/websocket"
+)
+
+// MockHandler handles a jsonrpc method of the MockServer. If the error is not
+// a *codec.ErrorObject it is returned to the client with the -32000 code.
+type MockHandler func(params []json.RawMessage) (interface{}, error)
+
+type mockError struct {
+	err   error
+	count int
+}
+
+type mockSubscription struct {
+	id     string
+	conn   *mockConn
+	method string
+	filter *ethgo.LogFilter
+}
+
+type mockConn struct {
+	conn *websocket.Conn
+	lock sync.Mutex
+}
+
+func (m *mockConn) write(obj interface{}) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.conn.WriteJSON(obj)
+}
+
+// MockServer is an in-process jsonrpc server (http and websocket) that serves the chain of
+// a MockClient. The methods can be replaced with custom handlers and it is possible to inject
+// errors and latency in the responses.
+type MockServer struct {
+	srv   *httptest.Server
+	chain *MockClient
+
+	lock     sync.Mutex
+	handlers map[string]MockHandler
+	errors   map[string]*mockError
+	latency  map[string]time.Duration
+	calls    map[string]int
+
+	subsLock sync.Mutex
+	subs     map[string]*mockSubscription
+	conns    map[*mockConn]struct{}
+	subSeq   uint64
+}
+
+// NewMockServer starts a new MockServer. It is closed when the test finishes.
+func NewMockServer(t *testing.T) *MockServer {
+	m := &MockServer{
+		chain:    &MockClient{},
+		handlers: map[string]MockHandler{},
+		errors:   map[string]*mockError{},
+		latency:  map[string]time.Duration{},
+		calls:    map[string]int{},
+		subs:     map[string]*mockSubscription{},
+		conns:    map[*mockConn]struct{}{},
+	}
+	m.registerDefaultHandlers()
+
+	m.srv = httptest.NewServer(http.HandlerFunc(m.serveHTTP))
+	t.Cleanup(m.Close)
+
+	return m
+}
+
+// HTTPAddr returns the http address of the server
+func (m *MockServer) HTTPAddr() string {
+	return m.srv.URL
+}
+
+// WSAddr returns the websocket address of the server
+func (m *MockServer) WSAddr() string {
+	return "ws://" + strings.TrimPrefix(m.srv.URL, "http://")
+}
+
+// Chain returns the MockClient with the chain served by the server
+func (m *MockServer) Chain() *MockClient {
+	return m.chain
+}
+
+// Close closes the websocket connections and stops the server
+func (m *MockServer) Close() {
+	m.subsLock.Lock()
+	for conn := range m.conns {
+		conn.conn.Close()
+	}
+	m.conns = map[*mockConn]struct{}{}
+	m.subs = map[string]*mockSubscription{}
+	m.subsLock.Unlock()
+
+	m.srv.Close()
+}
+
+// Handle sets the handler of a method. It replaces the default handler if there is any.
+func (m *MockServer) Handle(method string, handler MockHandler) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.handlers[method] = handler
+}
+
+// InjectError returns the error for the next count calls to the method
+// (or all of them if count is zero) instead of calling the handler
+func (m *MockServer) InjectError(method string, err error, count int) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.errors[method] = &mockError{err: err, count: count}
+}
+
+// ClearErrors removes the injected errors
+func (m *MockServer) ClearErrors() {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.errors = map[string]*mockError{}
+}
+
+// SetLatency delays the responses of the method. An empty
+// method sets the latency of all the methods.
+func (m *MockServer) SetLatency(method string, latency time.Duration) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.latency[method] = latency
+}
+
+// Calls returns the number of calls handled for the method
+func (m *MockServer) Calls(method string) int {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.calls[method]
+}
+
+// AddScenario adds the blocks to the chain and notifies the subscriptions. The logs of the
+// blocks replaced by the scenario are notified as removed to simulate a reorg.
+func (m *MockServer) AddScenario(l MockList) {
+	removed := []*ethgo.Log{}
+
+	m.chain.lock.Lock()
+	for _, b := range l {
+		hash, ok := m.chain.blockNum[uint64(b.num)]
+		if !ok || hash == b.Hash() {
+			continue
+		}
+		for _, log := range m.chain.logs[hash] {
+			log = log.Copy()
+			log.Removed = true
+			removed = append(removed, log)
+		}
+	}
+	m.chain.lock.Unlock()
+
+	m.chain.AddScenario(l)
+
+	for _, b := range l {
+		block, err := m.chain.GetBlockByHash(b.Hash(), false)
+		if err != nil {
+			continue
+		}
+		m.PushNewHead(block)
+	}
+	m.PushLogs(append(removed, l.GetLogs()...)...)
+}
+
+// PushNewHead notifies the block to the newHeads subscriptions
+func (m *MockServer) PushNewHead(block *ethgo.Block) {
+	for _, sub := range m.subscriptions("newHeads") {
+		m.notify(sub, block)
+	}
+}
+
+// PushLogs notifies the logs to the logs subscriptions that match them
+func (m *MockServer) PushLogs(logs ...*ethgo.Log) {
+	for _, sub := range m.subscriptions("logs") {
+		for _, log := range logs {
+			if sub.filter == nil || matchLog(sub.filter, log) {
+				m.notify(sub, log)
+			}
+		}
+	}
+}
+
+func (m *MockServer) subscriptions(method string) (res []*mockSubscription) {
+	m.subsLock.Lock()
+	defer m.subsLock.Unlock()
+
+	for _, sub := range m.subs {
+		if sub.method == method {
+			res = append(res, sub)
+		}
+	}
+	return
+}
+
+func (m *MockServer) notify(sub *mockSubscription, obj interface{}) {
+	result, err := json.Marshal(obj)
+	if err != nil {
+		panic(err)
+	}
+	params, err := json.Marshal(&codec.Subscription{ID: sub.id, Result: result})
+	if err != nil {
+		panic(err)
+	}
+	// the connection might be closed already
+	sub.conn.write(&codec.Request{JsonRPC: "2.0", Method: "eth_subscription", Params: params})
+}
+
+func matchLog(filter *ethgo.LogFilter, log *ethgo.Log) bool {
+	if len(filter.Address) != 0 {
+		found := false
+		for _, addr := range filter.Address {
+			if addr == log.Address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for indx, topics := range filter.Topics {
+		if len(topics) == 0 {
+			continue
+		}
+		if indx >= len(log.Topics) {
+			return false
+		}
+		found := false
+		for _, topic := range topics {
+			if topic == nil || *topic == log.Topics[indx] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+type mockResponse struct {
+	JsonRPC string             `json:"jsonrpc"`
+	ID      uint64             `json:"id"`
+	Result  interface{}        `json:"result,omitempty"`
+	Error   *codec.ErrorObject `json:"error,omitempty"`
+}
+
+func (m *MockServer) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if websocket.IsWebSocketUpgrade(r) {
+		m.serveWebsocket(w, r)
+		return
+	}
+
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var req codec.Request
+	if err := json.Unmarshal(data, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(m.handle(nil, &req))
+}
+
+func (m *MockServer) serveWebsocket(w http.ResponseWriter, r *http.Request) {
+	upgrader := websocket.Upgrader{}
+	wsConn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	conn := &mockConn{conn: wsConn}
+
+	m.subsLock.Lock()
+	m.conns[conn] = struct{}{}
+	m.subsLock.Unlock()
+
+	defer func() {
+		m.subsLock.Lock()
+		delete(m.conns, conn)
+		for id, sub := range m.subs {
+			if sub.conn == conn {
+				delete(m.subs, id)
+			}
+		}
+		m.subsLock.Unlock()
+
+		wsConn.Close()
+	}()
+
+	for {
+		_, data, err := wsConn.ReadMessage()
+		if err != nil {
+			return
+		}
+		var req codec.Request
+		if err := json.Unmarshal(data, &req); err != nil {
+			return
+		}
+		go conn.write(m.handle(conn, &req))
+	}
+}
+
+func (m *MockServer) handle(conn *mockConn, req *codec.Request) *mockResponse {
+	resp := &mockResponse{JsonRPC: "2.0", ID: req.ID}
+
+	var params []json.RawMessage
+	if len(req.Params) != 0 {
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			resp.Error = &codec.ErrorObject{Code: codec.CodeInvalidParams, Message: err.Error()}
+			return resp
+		}
+	}
+
+	m.lock.Lock()
+	latency, ok := m.latency[req.Method]
+	if !ok {
+		latency = m.latency[""]
+	}
+	var injectedErr error
+	if e, ok := m.errors[req.Method]; ok {
+		injectedErr = e.err
+		if e.count > 0 {
+			if e.count--; e.count == 0 {
+				delete(m.errors, req.Method)
+			}
+		}
+	}
+	handler, ok := m.handlers[req.Method]
+	m.lock.Unlock()
+
+	if latency != 0 {
+		time.Sleep(latency)
+	}
+
+	var (
+		result interface{}
+		err    error
+	)
+	switch {
+	case injectedErr != nil:
+		err = injectedErr
+	case req.Method == "eth_subscribe":
+		result, err = m.subscribe(conn, params)
+	case req.Method == "eth_unsubscribe":
+		result, err = m.unsubscribe(params)
+	case ok:
+		result, err = handler(params)
+	default:
+		err = &codec.ErrorObject{
+			Code:    codec.CodeMethodNotFound,
+			Message: fmt.Sprintf("the method %s does not exist/is not available", req.Method),
+		}
+	}
+
+	m.lock.Lock()
+	m.calls[req.Method]++
+	m.lock.Unlock()
+
+	if err != nil {
+		errObj, ok := err.(*codec.ErrorObject)
+		if !ok {
+			errObj = &codec.ErrorObject{Code: codec.CodeInvalidInput, Message: err.Error()}
+		}
+		resp.Error = errObj
+		return resp
+	}
+	if result == nil {
+		// null results (i.e. not found objects) are not omitted
+		result = json.RawMessage("null")
+	}
+	resp.Result = result
+	return resp
+}
+
+func (m *MockServer) subscribe(conn *mockConn, params []json.RawMessage) (interface{}, error) {
+	if conn == nil {
+		return nil, &codec.ErrorObject{Code: codec.CodeMethodNotFound, Message: "notifications not supported"}
+	}
+	if len(params) == 0 {
+		return nil, &codec.ErrorObject{Code: codec.CodeInvalidParams, Message: "missing subscription method"}
+	}
+
+	sub := &mockSubscription{
+		id:   fmt.Sprintf("0x%x", atomic.AddUint64(&m.subSeq, 1)),
+		conn: conn,
+	}
+	if err := json.Unmarshal(params[0], &sub.method); err != nil {
+		return nil, &codec.ErrorObject{Code: codec.CodeInvalidParams, Message: err.Error()}
+	}
+	switch sub.method {
+	case "newHeads":
+	case "logs":
+		if len(params) > 1 {
+			sub.filter = &ethgo.LogFilter{}
+			if err := sub.filter.UnmarshalJSON(params[1]); err != nil {
+				return nil, &codec.ErrorObject{Code: codec.CodeInvalidParams, Message: err.Error()}
+			}
+		}
+	default:
+		return nil, &codec.ErrorObject{Code: codec.CodeInvalidParams, Message: fmt.Sprintf("no %q subscription", sub.method)}
+	}
+
+	m.subsLock.Lock()
+	m.subs[sub.id] = sub
+	m.subsLock.Unlock()
+
+	return sub.id, nil
+}
+
+func (m *MockServer) unsubscribe(params []json.RawMessage) (interface{}, error) {
+	if len(params) == 0 {
+		return nil, &codec.ErrorObject{Code: codec.CodeInvalidParams, Message: "missing subscription id"}
+	}
+	var id string
+	if err := json.Unmarshal(params[0], &id); err != nil {
+		return nil, &codec.ErrorObject{Code: codec.CodeInvalidParams, Message: err.Error()}
+	}
+
+	m.subsLock.Lock()
+	defer m.subsLock.Unlock()
+
+	_, ok := m.subs[id]
+	delete(m.subs, id)
+	return ok, nil
+}
+
+func decodeMockParam(params []json.RawMessage, indx int, obj interface{}) error {
+	if indx >= len(params) {
+		return &codec.ErrorObject{Code: codec.CodeInvalidParams, Message: fmt.Sprintf("missing value for required argument %d", indx)}
+	}
+	if err := json.Unmarshal(params[indx], obj); err != nil {
+		return &codec.ErrorObject{Code: codec.CodeInvalidParams, Message: fmt.Sprintf("invalid argument %d: %v", indx, err)}
+	}
+	return nil
+}
+
+func decodeMockBlockNumber(params []json.RawMessage, indx int) (ethgo.BlockNumber, error) {
+	var str string
+	if err := decodeMockParam(params, indx, &str); err != nil {
+		return 0, err
+	}
+	switch str {
+	case "latest", "pending", "safe", "finalized":
+		return ethgo.Latest, nil
+	case "earliest":
+		return ethgo.Earliest, nil
+	}
+	var num ethgo.ArgUint64
+	if err := num.UnmarshalText([]byte(str)); err != nil {
+		return 0, &codec.ErrorObject{Code: codec.CodeInvalidParams, Message: fmt.Sprintf("invalid block number %s", str)}
+	}
+	return ethgo.BlockNumber(num), nil
+}
+
+// blockOrNull returns nil for the blocks that are not found in the chain
+func blockOrNull(block *ethgo.Block, err error) (interface{}, error) {
+	if err != nil {
+		return nil, nil
+	}
+	return block, nil
+}
+
+func (m *MockServer) registerDefaultHandlers() {
+	m.handlers["eth_chainId"] = func(params []json.RawMessage) (interface{}, error) {
+		chainID, _ := m.chain.ChainID()
+		return fmt.Sprintf("0x%x", chainID), nil
+	}
+	m.handlers["net_version"] = func(params []json.RawMessage) (interface{}, error) {
+		chainID, _ := m.chain.ChainID()
+		return chainID.String(), nil
+	}
+	m.handlers["web3_clientVersion"] = func(params []json.RawMessage) (interface{}, error) {
+		return "ethgo/mock", nil
+	}
+	m.handlers["eth_blockNumber"] = func(params []json.RawMessage) (interface{}, error) {
+		num, _ := m.chain.BlockNumber()
+		return fmt.Sprintf("0x%x", num), nil
+	}
+	m.handlers["eth_getBlockByNumber"] = func(params []json.RawMessage) (interface{}, error) {
+		num, err := decodeMockBlockNumber(params, 0)
+		if err != nil {
+			return nil, err
+		}
+		return blockOrNull(m.chain.GetBlockByNumber(num, false))
+	}
+	m.handlers["eth_getBlockByHash"] = func(params []json.RawMessage) (interface{}, error) {
+		var hash ethgo.Hash
+		if err := decodeMockParam(params, 0, &hash); err != nil {
+			return nil, err
+		}
+		return blockOrNull(m.chain.GetBlockByHash(hash, false))
+	}
+	m.handlers["eth_getLogs"] = func(params []json.RawMessage) (interface{}, error) {
+		var filter ethgo.LogFilter
+		if err := decodeMockParam(params, 0, &filter); err != nil {
+			return nil, err
+		}
+		if filter.BlockHash == nil {
+			// default to the latest block like the nodes
+			num, _ := m.chain.BlockNumber()
+			if filter.From == nil {
+				filter.SetFromUint64(num)
+			}
+			if filter.To == nil {
+				filter.SetToUint64(num)
+			}
+		}
+		logs, err := m.chain.GetLogs(&filter)
+		if err != nil {
+			return nil, err
+		}
+		res := []*ethgo.Log{}
+		for _, log := range logs {
+			if matchLog(&filter, log) {
+				res = append(res, log)
+			}
+		}
+		return res, nil
+	}
+}
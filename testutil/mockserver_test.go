@@ -0,0 +1,150 @@
+package testutil
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/jsonrpc"
+	"github.com/Ethernal-Tech/ethgo/jsonrpc/codec"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMockServer_Chain(t *testing.T) {
+	srv := NewMockServer(t)
+
+	l := MockList{}
+	l.Create(0, 5, func(b *MockBlock) {
+		b.Log("0x01")
+	})
+	srv.AddScenario(l)
+
+	for _, addr := range []string{srv.HTTPAddr(), srv.WSAddr()} {
+		client, err := jsonrpc.NewClient(addr)
+		require.NoError(t, err)
+
+		num, err := client.Eth().BlockNumber()
+		require.NoError(t, err)
+		require.Equal(t, uint64(4), num)
+
+		block, err := client.Eth().GetBlockByNumber(2, false)
+		require.NoError(t, err)
+		require.Equal(t, l[2].Hash(), block.Hash)
+		require.Equal(t, l[1].Hash(), block.ParentHash)
+
+		block, err = client.Eth().GetBlockByHash(ethgo.HexToHash("0x1234"), false)
+		require.NoError(t, err)
+		require.Nil(t, block)
+
+		filter := &ethgo.LogFilter{}
+		filter.SetFromUint64(1)
+		filter.SetToUint64(3)
+
+		logs, err := client.Eth().GetLogs(filter)
+		require.NoError(t, err)
+		require.Len(t, logs, 3)
+
+		client.Close()
+	}
+}
+
+func TestMockServer_Handlers(t *testing.T) {
+	srv := NewMockServer(t)
+
+	client, err := jsonrpc.NewClient(srv.HTTPAddr())
+	require.NoError(t, err)
+
+	srv.Handle("eth_gasPrice", func(params []json.RawMessage) (interface{}, error) {
+		return "0x10", nil
+	})
+	price, err := client.Eth().GasPrice()
+	require.NoError(t, err)
+	require.Equal(t, uint64(16), price)
+
+	// unknown methods
+	require.True(t, errors.Is(client.Call("eth_foo", nil), jsonrpc.ErrMethodNotFound))
+
+	// inject an error for the next call
+	srv.InjectError("eth_gasPrice", &codec.ErrorObject{Code: 429, Message: "too many requests"}, 1)
+
+	_, err = client.Eth().GasPrice()
+	require.True(t, errors.Is(err, jsonrpc.ErrRateLimited))
+
+	_, err = client.Eth().GasPrice()
+	require.NoError(t, err)
+	require.Equal(t, 3, srv.Calls("eth_gasPrice"))
+
+	// latency
+	srv.SetLatency("eth_gasPrice", 100*time.Millisecond)
+
+	now := time.Now()
+	_, err = client.Eth().GasPrice()
+	require.NoError(t, err)
+	require.GreaterOrEqual(t, time.Since(now), 100*time.Millisecond)
+}
+
+func TestMockServer_Subscriptions(t *testing.T) {
+	srv := NewMockServer(t)
+
+	l := MockList{}
+	l.Create(0, 3, func(b *MockBlock) {})
+	srv.AddScenario(l)
+
+	client, err := jsonrpc.NewClient(srv.WSAddr())
+	require.NoError(t, err)
+	defer client.Close()
+
+	heads := make(chan *ethgo.Block, 10)
+	cancelHeads, err := client.Subscribe("newHeads", func(b []byte) {
+		block := &ethgo.Block{}
+		require.NoError(t, block.UnmarshalJSON(b))
+		heads <- block
+	})
+	require.NoError(t, err)
+
+	logs := make(chan *ethgo.Log, 10)
+	_, err = client.Subscribe("logs", func(b []byte) {
+		log := &ethgo.Log{}
+		require.NoError(t, log.UnmarshalJSON(b))
+		logs <- log
+	})
+	require.NoError(t, err)
+
+	// fork at block 2 with logs
+	fork := MockList{
+		Mock(2).Extra("a").Log("0x01"),
+	}
+	srv.AddScenario(fork)
+
+	block := <-heads
+	require.Equal(t, fork[0].Hash(), block.Hash)
+
+	log := <-logs
+	require.False(t, log.Removed)
+	require.Equal(t, fork[0].Hash(), log.BlockHash)
+
+	// reorg the block with the logs
+	reorg := MockList{
+		Mock(2).Extra("b"),
+		Mock(3).Extra("b").Parent(2),
+	}
+	srv.AddScenario(reorg)
+
+	// the notifications are dispatched concurrently, so they may arrive out of order
+	received := []*ethgo.Block{<-heads, <-heads}
+	sort.Slice(received, func(i, j int) bool {
+		return received[i].Number < received[j].Number
+	})
+	require.Equal(t, reorg[0].Hash(), received[0].Hash)
+	require.Equal(t, reorg[1].Hash(), received[1].Hash)
+
+	log = <-logs
+	require.True(t, log.Removed)
+	require.Equal(t, fork[0].Hash(), log.BlockHash)
+
+	require.NoError(t, cancelHeads())
+	require.Len(t, srv.subscriptions("newHeads"), 0)
+}
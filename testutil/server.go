@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"math/big"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/Ethernal-Tech/ethgo"
 	"github.com/Ethernal-Tech/ethgo/compiler"
+	"github.com/Ethernal-Tech/ethgo/jsonrpc/codec"
 	"github.com/ory/dockertest"
 	"golang.org/x/crypto/sha3"
 )
@@ -276,7 +278,7 @@ func (t *TestServer) WaitForReceipt(hash ethgo.Hash) (*ethgo.Receipt, error) {
 	for {
 		err := t.client.call("eth_getTransactionReceipt", &receipt, hash)
 		if err != nil {
-			if err.Error() != "not found" {
+			if !errors.Is(err, errNotFound) {
 				return nil, err
 			}
 		}
@@ -322,15 +324,9 @@ type jsonRPCRequest struct {
 }
 
 type jsonRPCResponse struct {
-	ID     int                 `json:"id"`
-	Result json.RawMessage     `json:"result"`
-	Error  *jsonRPCErrorObject `json:"error,omitempty"`
-}
-
-type jsonRPCErrorObject struct {
-	Code    int         `json:"code"`
-	Message string      `json:"message"`
-	Data    interface{} `json:"data,omitempty"`
+	ID     int                `json:"id"`
+	Result json.RawMessage    `json:"result"`
+	Error  *codec.ErrorObject `json:"error,omitempty"`
 }
 
 type ethClient struct {
@@ -372,7 +368,7 @@ func (e *ethClient) call(method string, out interface{}, params ...interface{})
 	}
 
 	if jsonResp.Error != nil {
-		return fmt.Errorf(jsonResp.Error.Message)
+		return jsonResp.Error
 	}
 	if bytes.Equal(jsonResp.Result, []byte("null")) {
 		return errNotFound
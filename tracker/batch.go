@@ -0,0 +1,322 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/jsonrpc/codec"
+)
+
+// WithMaxBatchSize sets the maximum number of blocks queried with eth_getLogs.
+// The range grows up to this size while the responses are small. By default,
+// it is the batch size.
+func WithMaxBatchSize(b uint64) ConfigOption {
+	return func(c *Config) {
+		c.MaxBatchSize = b
+	}
+}
+
+// WithMaxBatchLogs sets the number of logs expected in a single eth_getLogs response.
+// The range shrinks if a response returns more logs and only grows if a response
+// returns less than half of them.
+func WithMaxBatchLogs(n uint64) ConfigOption {
+	return func(c *Config) {
+		c.MaxBatchLogs = n
+	}
+}
+
+// WithConcurrency sets the number of ranges queried concurrently during the batch sync.
+// The logs are still processed and emitted in order.
+func WithConcurrency(n int) ConfigOption {
+	return func(c *Config) {
+		c.Concurrency = n
+	}
+}
+
+// tooMuchDataRequestedError returns true if the provider rejected the query
+// because of the size of the range or the size of the response
+func tooMuchDataRequestedError(err error) bool {
+	return errors.Is(err, codec.ErrTooManyResults)
+}
+
+// suggestedRangeRegexp matches the range suggested by alchemy in the
+// error (i.e. 'this block range should work: [0x1, 0x2]')
+var suggestedRangeRegexp = regexp.MustCompile(`\[(0x[0-9a-fA-F]+), ?(0x[0-9a-fA-F]+)\]`)
+
+// suggestedRange returns the range suggested by the provider in the error
+func suggestedRange(err error) (uint64, uint64, bool) {
+	var obj *codec.ErrorObject
+	if !errors.As(err, &obj) {
+		return 0, 0, false
+	}
+	match := suggestedRangeRegexp.FindStringSubmatch(obj.Message)
+	if match == nil {
+		return 0, 0, false
+	}
+	from, err := strconv.ParseUint(match[1][2:], 16, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	to, err := strconv.ParseUint(match[2][2:], 16, 64)
+	if err != nil {
+		return 0, 0, false
+	}
+	return from, to, true
+}
+
+// batchSizer adapts the size of the eth_getLogs ranges to the responses of the provider
+type batchSizer struct {
+	// size is the number of blocks of the next range after the first one
+	// (i.e. a size of 0 queries a single block)
+	size    uint64
+	max     uint64
+	maxLogs uint64
+}
+
+func newBatchSizer(config *Config) *batchSizer {
+	return &batchSizer{
+		size:    config.BatchSize,
+		max:     max(config.BatchSize, config.MaxBatchSize),
+		maxLogs: config.MaxBatchLogs,
+	}
+}
+
+// update sets the size of the next range from the result of a query
+func (b *batchSizer) update(res *batchResult) {
+	if res.split {
+		// use the largest range accepted by the provider
+		b.size = res.accepted
+		return
+	}
+
+	num := uint64(len(res.logs))
+	if b.maxLogs != 0 && num > b.maxLogs {
+		// shrink the range proportionally to the logs returned
+		b.size = (b.size + 1) * b.maxLogs / num
+		if b.size != 0 {
+			b.size--
+		}
+		return
+	}
+	if b.maxLogs == 0 || num <= b.maxLogs/2 {
+		// the response is small, double the range
+		b.size = min(b.max, 2*b.size+1)
+	}
+}
+
+// batchResult is the result of the query of a range
+type batchResult struct {
+	from, to uint64
+	logs     []*ethgo.Log
+	err      error
+
+	// split is true if the provider rejected the range and it was bisected
+	split bool
+
+	// accepted is the size of the largest sub range accepted by the provider
+	accepted uint64
+}
+
+// fetchLogs queries the logs of the range. If the provider rejects the range because
+// it returns too many results, the range is split in two halves (or at the range
+// suggested by the provider) that are queried separately.
+func (t *Tracker) fetchLogs(search *ethgo.LogFilter, from, to uint64) *batchResult {
+	res := &batchResult{from: from, to: to}
+
+	query := *search
+	query.SetFromUint64(from)
+	query.SetToUint64(to)
+
+	res.logs, res.err = t.provider.GetLogs(&query)
+	if res.err == nil {
+		res.accepted = to - from
+		return res
+	}
+	if !tooMuchDataRequestedError(res.err) || from == to {
+		return res
+	}
+
+	mid := from + (to-from)/2
+	if sFrom, sTo, ok := suggestedRange(res.err); ok && sFrom == from && sTo >= from && sTo < to {
+		mid = sTo
+	}
+
+	left := t.fetchLogs(search, from, mid)
+	if left.err != nil {
+		return left
+	}
+	right := t.fetchLogs(search, mid+1, to)
+	if right.err != nil {
+		return right
+	}
+
+	res.logs = append(left.logs, right.logs...)
+	res.err = nil
+	res.split = true
+	res.accepted = max(left.accepted, right.accepted)
+	return res
+}
+
+// fetchRanges queries the ranges concurrently. The results are in the same order as the ranges.
+func (t *Tracker) fetchRanges(search *ethgo.LogFilter, ranges [][2]uint64) []*batchResult {
+	results := make([]*batchResult, len(ranges))
+	if len(ranges) == 1 {
+		results[0] = t.fetchLogs(search, ranges[0][0], ranges[0][1])
+		return results
+	}
+
+	var wg sync.WaitGroup
+	for indx, r := range ranges {
+		wg.Add(1)
+		go func(indx int, from, to uint64) {
+			defer wg.Done()
+			results[indx] = t.fetchLogs(search, from, to)
+		}(indx, r[0], r[1])
+	}
+	wg.Wait()
+	return results
+}
+
+func (t *Tracker) syncBatch(ctx context.Context, filters []*filter, from, to uint64) error {
+	sizer := newBatchSizer(t.config)
+	concurrency := max(1, t.config.Concurrency)
+
+	for i := from; i <= to; {
+		// split the next part of the sync in ranges of the current size
+		ranges := [][2]uint64{}
+		for j := i; j <= to && len(ranges) < concurrency; {
+			dst := min(to, j+sizer.size)
+			ranges = append(ranges, [2]uint64{j, dst})
+			j = dst + 1
+		}
+		last := ranges[len(ranges)-1][1]
+
+		// only the filters that have not processed the ranges yet are part of the query
+		active := []*filter{}
+		for _, f := range filters {
+			if f.next <= last && !f.isRemoved() {
+				active = append(active, f)
+			}
+		}
+		if len(active) == 0 {
+			return nil
+		}
+
+		// the transaction filters scan the blocks of the ranges instead
+		var results []*batchResult
+		if logFilters, _ := splitFilters(active); len(logFilters) != 0 {
+			results = t.fetchRanges(combinedFilterSearch(logFilters), ranges)
+		} else {
+			for _, r := range ranges {
+				results = append(results, &batchResult{from: r[0], to: r[1]})
+			}
+		}
+
+		// process the results in order up to the first failed range
+		for _, res := range results {
+			if res.err != nil {
+				return res.err
+			}
+			if err := t.processBatch(ctx, active, res); err != nil {
+				return err
+			}
+			sizer.update(res)
+
+			// check if the execution is over after each query batch
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+		i = last + 1
+	}
+	return nil
+}
+
+// processBatch stores and emits the logs of the range for each filter
+func (t *Tracker) processBatch(ctx context.Context, filters []*filter, res *batchResult) error {
+	if t.SyncCh != nil {
+		select {
+		case t.SyncCh <- res.to:
+		default:
+		}
+	}
+
+	block, err := t.getBlockByNumber(res.to)
+	if err != nil {
+		return err
+	}
+
+	// only the filters that have not processed the range yet
+	pending := []*filter{}
+	for _, f := range filters {
+		if f.next <= res.to {
+			pending = append(pending, f)
+		}
+	}
+
+	logFilters, txFilters := splitFilters(pending)
+	txs, err := t.scanTxs(txFilters, res.from, res.to)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range logFilters {
+		// add the logs of the filter to the store and update the last block entry
+		if err := t.commit(ctx, f, &update{added: f.filterLogs(res.logs), last: block}); err != nil {
+			return err
+		}
+	}
+	for indx, f := range txFilters {
+		if err := t.commit(ctx, f, &update{added: txs[indx], last: block}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanTxs returns the transactions of the blocks in the range that match each of the
+// transaction filters. Each filter only matches the blocks it has not processed yet.
+func (t *Tracker) scanTxs(filters []*filter, from, to uint64) ([][]*ethgo.Log, error) {
+	res := make([][]*ethgo.Log, len(filters))
+	if len(filters) == 0 {
+		return res, nil
+	}
+
+	first := to
+	for _, f := range filters {
+		first = min(first, f.next)
+	}
+	for num := max(from, first); num <= to; num++ {
+		block, err := t.provider.GetBlockByNumber(ethgo.BlockNumber(num), true)
+		if err != nil {
+			return nil, err
+		} else if block == nil {
+			return nil, fmt.Errorf("block with number %d not found", num)
+		}
+
+		active := []*filter{}
+		for _, f := range filters {
+			if f.next <= num {
+				active = append(active, f)
+			}
+		}
+		txs, err := t.matchTxs(active, block)
+		if err != nil {
+			return nil, err
+		}
+
+		i := 0
+		for indx, f := range filters {
+			if f.next <= num {
+				res[indx] = append(res[indx], txs[i]...)
+				i++
+			}
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,220 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/jsonrpc/codec"
+	"github.com/Ethernal-Tech/ethgo/testutil"
+	"github.com/Ethernal-Tech/ethgo/tracker/store/inmem"
+	"github.com/stretchr/testify/require"
+)
+
+// logLimitProvider is a mock provider that rejects the eth_getLogs
+// queries with more than limit logs with an alchemy like error
+type logLimitProvider struct {
+	*testutil.MockClient
+
+	limit int
+	delay bool
+
+	lock   sync.Mutex
+	ranges [][2]uint64
+}
+
+func (p *logLimitProvider) GetLogs(filter *ethgo.LogFilter) ([]*ethgo.Log, error) {
+	if filter.BlockHash != nil {
+		return p.MockClient.GetLogs(filter)
+	}
+	if p.delay {
+		time.Sleep(time.Duration(rand.Intn(5)) * time.Millisecond)
+	}
+
+	logs, err := p.MockClient.GetLogs(filter)
+	if err != nil {
+		return nil, err
+	}
+	if p.limit != 0 && len(logs) > p.limit {
+		return nil, &codec.ErrorObject{Code: -32602, Message: fmt.Sprintf("Log response size exceeded. You can make eth_getLogs requests with up to a %d logs", p.limit)}
+	}
+
+	p.lock.Lock()
+	p.ranges = append(p.ranges, [2]uint64{uint64(*filter.From), uint64(*filter.To)})
+	p.lock.Unlock()
+
+	return logs, nil
+}
+
+func newLogLimitProvider(numLogs func(num int) int) (*logLimitProvider, int) {
+	total := 0
+
+	l := testutil.MockList{}
+	l.Create(0, 100, func(b *testutil.MockBlock) {
+		for i := 0; i < numLogs(b.GetNum()); i++ {
+			total++
+			b.Log("0x1")
+		}
+	})
+
+	m := &testutil.MockClient{}
+	m.AddScenario(l)
+
+	return &logLimitProvider{MockClient: m}, total
+}
+
+func batchSyncLogs(t *testing.T, provider Provider, opts ...ConfigOption) []*ethgo.Log {
+	t.Helper()
+
+	tt, err := NewTracker(provider, opts...)
+	require.NoError(t, err)
+
+	doneCh := make(chan []*ethgo.Log)
+	go func() {
+		logs := []*ethgo.Log{}
+		for evnt := range tt.EventCh {
+			logs = append(logs, evnt.Added...)
+		}
+		doneCh <- logs
+	}()
+
+	require.NoError(t, tt.BatchSync(context.Background()))
+	close(tt.EventCh)
+
+	logs := <-doneCh
+	require.Len(t, tt.Entry().(*inmem.Entry).Logs(), len(logs))
+	return logs
+}
+
+func TestBatchSizer(t *testing.T) {
+	logs := func(n int) []*ethgo.Log {
+		return make([]*ethgo.Log, n)
+	}
+
+	cases := []struct {
+		name     string
+		sizer    *batchSizer
+		res      *batchResult
+		expected uint64
+	}{
+		{"split", &batchSizer{size: 99, max: 99}, &batchResult{split: true, accepted: 24}, 24},
+		{"grow", &batchSizer{size: 9, max: 99}, &batchResult{}, 19},
+		{"grow up to max", &batchSizer{size: 79, max: 99}, &batchResult{}, 99},
+		{"grow small response", &batchSizer{size: 9, max: 99, maxLogs: 10}, &batchResult{logs: logs(5)}, 19},
+		{"keep", &batchSizer{size: 9, max: 99, maxLogs: 10}, &batchResult{logs: logs(8)}, 9},
+		{"shrink", &batchSizer{size: 99, max: 99, maxLogs: 10}, &batchResult{logs: logs(50)}, 19},
+		{"shrink to one block", &batchSizer{size: 1, max: 99, maxLogs: 10}, &batchResult{logs: logs(50)}, 0},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			c.sizer.update(c.res)
+			require.Equal(t, c.expected, c.sizer.size)
+		})
+	}
+}
+
+func TestSuggestedRange(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", &codec.ErrorObject{
+		Code:    -32602,
+		Message: "Log response size exceeded. Based on your parameters, this block range should work: [0x10, 0x1f]",
+	})
+	require.True(t, tooMuchDataRequestedError(err))
+
+	from, to, ok := suggestedRange(err)
+	require.True(t, ok)
+	require.Equal(t, uint64(0x10), from)
+	require.Equal(t, uint64(0x1f), to)
+
+	_, _, ok = suggestedRange(&codec.ErrorObject{Message: "query returned more than 10000 results"})
+	require.False(t, ok)
+}
+
+func TestTrackerBatchBisection(t *testing.T) {
+	// blocks 30 to 40 have many logs and the range has to be split
+	p, total := newLogLimitProvider(func(num int) int {
+		if num >= 30 && num < 40 {
+			return 5
+		}
+		return 1
+	})
+	p.limit = 25
+
+	logs := batchSyncLogs(t, p, WithBatchSize(19))
+	require.Len(t, logs, total)
+
+	// the range is split on the blocks with many logs and grows again after them
+	sizes := map[uint64]uint64{}
+	for _, r := range p.ranges {
+		sizes[r[0]] = r[1] - r[0]
+	}
+	require.Less(t, sizes[30], uint64(19))
+	require.Equal(t, uint64(19), sizes[50])
+}
+
+func TestTrackerBatchSingleBlockTooLarge(t *testing.T) {
+	p, _ := newLogLimitProvider(func(num int) int {
+		if num == 50 {
+			return 5
+		}
+		return 0
+	})
+	p.limit = 4
+
+	tt, err := NewTracker(p, WithFilter(&FilterConfig{Async: true}))
+	require.NoError(t, err)
+
+	// a single block cannot be split
+	err = tt.BatchSync(context.Background())
+	require.ErrorIs(t, err, codec.ErrTooManyResults)
+}
+
+func TestTrackerBatchMaxLogs(t *testing.T) {
+	p, total := newLogLimitProvider(func(num int) int {
+		return 1
+	})
+
+	logs := batchSyncLogs(t, p, WithBatchSize(49), WithMaxBatchLogs(10))
+	require.Len(t, logs, total)
+
+	// the first query returns too many logs and the next ones are capped
+	require.Equal(t, [2]uint64{0, 49}, p.ranges[0])
+	for _, r := range p.ranges[1:] {
+		require.LessOrEqual(t, r[1]-r[0], uint64(9))
+	}
+}
+
+func TestTrackerBatchMaxBatchSize(t *testing.T) {
+	p, total := newLogLimitProvider(func(num int) int {
+		return 1
+	})
+
+	logs := batchSyncLogs(t, p, WithBatchSize(4), WithMaxBatchSize(39))
+	require.Len(t, logs, total)
+
+	// the range doubles up to the maximum size
+	sizes := []uint64{}
+	for _, r := range p.ranges {
+		sizes = append(sizes, r[1]-r[0])
+	}
+	require.Equal(t, []uint64{4, 9, 19, 39}, sizes[:4])
+}
+
+func TestTrackerBatchConcurrency(t *testing.T) {
+	p, total := newLogLimitProvider(func(num int) int {
+		return 1
+	})
+	p.delay = true
+
+	logs := batchSyncLogs(t, p, WithBatchSize(4), WithConcurrency(4))
+	require.Len(t, logs, total)
+
+	// the logs are emitted in order even if the queries finish in any order
+	for i := 1; i < len(logs); i++ {
+		require.Less(t, logs[i-1].BlockNumber, logs[i].BlockNumber)
+	}
+}
@@ -0,0 +1,137 @@
+package tracker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/jsonrpc/codec"
+	"github.com/Ethernal-Tech/ethgo/tracker/store"
+)
+
+// WithFinality syncs only up to the block of the finality tag (ethgo.Finalized or ethgo.Safe).
+// The events are only emitted once the logs are final and cannot be removed by a reorg.
+// The sync fails if the node does not support the tag, unless WithConfirmations is set.
+func WithFinality(tag ethgo.BlockNumber) ConfigOption {
+	return func(c *Config) {
+		c.Finality = tag
+	}
+}
+
+// WithConfirmations syncs only up to the blocks with n confirmations. If used with
+// WithFinality, the confirmations are only used if the chain does not support the finality tag.
+func WithConfirmations(n uint64) ConfigOption {
+	return func(c *Config) {
+		c.Confirmations = n
+	}
+}
+
+func (t *Tracker) isFinalityMode() bool {
+	return t.config.Finality != 0 || t.config.Confirmations != 0
+}
+
+// GetCursor returns the head or the final cursor of the filter. The cursors are
+// only set in finality mode.
+func (t *Tracker) GetCursor(name string, typ store.CursorType) (*store.Cursor, error) {
+	f := t.findFilter(name)
+	if f == nil {
+		return nil, fmt.Errorf("filter '%s' not found", name)
+	}
+	return store.GetCursor(t.store, f.config.Hash, typ)
+}
+
+// finalBlock returns the head of the chain and the last block that cannot be reverted.
+// The final block is nil if there is no final block yet.
+func (t *Tracker) finalBlock() (*ethgo.Block, *ethgo.Block, error) {
+	head, err := t.provider.GetBlockByNumber(ethgo.Latest, false)
+	if err != nil {
+		return nil, nil, err
+	} else if head == nil {
+		return nil, nil, fmt.Errorf("head block not found")
+	}
+
+	if t.config.Finality != 0 {
+		final, err := t.provider.GetBlockByNumber(t.config.Finality, false)
+		if err == nil && final != nil {
+			return head, final, nil
+		}
+		if err != nil && !unsupportedTagError(err) {
+			// the confirmations may be shallower than the finality of the chain
+			return nil, nil, err
+		}
+		if t.config.Confirmations == 0 {
+			return nil, nil, fmt.Errorf("the node does not support the finality tag %s: %v", t.config.Finality, err)
+		}
+		// the chain does not support the finality tag, use the confirmations
+	}
+
+	if head.Number < t.config.Confirmations {
+		return head, nil, nil
+	}
+	final, err := t.getBlockByNumber(head.Number - t.config.Confirmations)
+	if err != nil {
+		return nil, nil, err
+	}
+	return head, final, nil
+}
+
+// unsupportedTagError returns true if the node rejects the finality tag. The nodes
+// before the merge either reject the tag or do not find the block of the tag.
+func unsupportedTagError(err error) bool {
+	return errors.Is(err, codec.ErrInvalidParams) || errors.Is(err, codec.ErrMethodNotFound) || errors.Is(err, codec.ErrHeaderNotFound)
+}
+
+// syncFinal syncs the filters up to the final block. Since the final blocks
+// cannot be reverted, the logs are queried in batches with eth_getLogs and
+// there is no need to track the blocks in the backlog.
+func (t *Tracker) syncFinal(ctx context.Context, filters []*filter) error {
+	if err := t.preSyncCheck(); err != nil {
+		return err
+	}
+	if len(filters) == 0 {
+		return nil
+	}
+	defer t.clearPending(filters)
+
+	head, final, err := t.finalBlock()
+	if err != nil {
+		return err
+	}
+	if final == nil {
+		return nil
+	}
+
+	syncing := []*filter{}
+	for _, f := range filters {
+		synced, err := t.reconcileFilter(ctx, f, final)
+		if err != nil {
+			return err
+		}
+		if !synced {
+			syncing = append(syncing, f)
+		}
+	}
+
+	if len(syncing) != 0 {
+		origin := syncing[0].next
+		for _, f := range syncing[1:] {
+			origin = min(origin, f.next)
+		}
+		if err := t.syncBatch(ctx, syncing, origin, final.Number); err != nil {
+			return err
+		}
+	}
+
+	headCursor := &store.Cursor{Number: head.Number, Hash: head.Hash}
+	finalCursor := &store.Cursor{Number: final.Number, Hash: final.Hash}
+	for _, f := range filters {
+		if err := store.SetCursor(t.store, f.config.Hash, store.CursorHead, headCursor); err != nil {
+			return err
+		}
+		if err := store.SetCursor(t.store, f.config.Hash, store.CursorFinal, finalCursor); err != nil {
+			return err
+		}
+	}
+	return nil
+}
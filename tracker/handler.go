@@ -0,0 +1,234 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ethernal-Tech/ethgo"
+)
+
+var dbAcked = "acked"
+
+const (
+	defaultHandlerAttempts = 3
+	defaultHandlerBackoff  = 500 * time.Millisecond
+)
+
+// Handler processes the events of the tracker. The event is acknowledged when the
+// handler returns nil and the checkpoint of the filter only advances after that.
+type Handler func(ctx context.Context, evnt *Event) error
+
+// WithHandler delivers the events to the handler instead of EventCh. The sync waits
+// for the handler, so a slow handler slows down the sync instead of losing events.
+// If the tracker stops before an event is acknowledged, it is delivered again on restart.
+func WithHandler(h Handler) ConfigOption {
+	return func(c *Config) {
+		c.Handler = h
+	}
+}
+
+// WithHandlerRetry sets the number of times an event is delivered to the handler
+// before the sync fails and the delay before the first retry, which doubles
+// after each attempt. By default, an event is delivered up to 3 times.
+func WithHandlerRetry(attempts uint64, backoff time.Duration) ConfigOption {
+	return func(c *Config) {
+		c.HandlerAttempts = attempts
+		c.HandlerBackoff = backoff
+	}
+}
+
+// WithAtLeastOnce stores the idempotency keys (see IdempotencyKey) of the logs
+// acknowledged by the handler until the checkpoint of the filter advances. Every
+// log is delivered at least once, but the logs delivered again after a restart or
+// a failed sync are skipped if they were already acknowledged.
+func WithAtLeastOnce() ConfigOption {
+	return func(c *Config) {
+		c.AtLeastOnce = true
+	}
+}
+
+// IdempotencyKey returns the key that identifies the log in the chain. Since it
+// includes the block hash, the key of a log changes if it is included again after a reorg.
+// The transactions of the transaction filters use the transaction index as the log index.
+func IdempotencyKey(log *ethgo.Log) string {
+	return fmt.Sprintf("%s:%d", log.BlockHash.String(), log.LogIndex)
+}
+
+// update is the change in the logs of a filter after processing a range of blocks
+type update struct {
+	// removed are the logs removed by a reorg and removeIndex is
+	// the index in the entry of the first removed log
+	removed     []*ethgo.Log
+	removeIndex uint64
+
+	// added are the logs included in the range
+	added []*ethgo.Log
+
+	// last is the new checkpoint of the filter, if any
+	last *ethgo.Block
+}
+
+// commit applies the update to the entry and the checkpoint of the filter. With a
+// handler, the event is acknowledged before anything is stored. Otherwise, the
+// event is emitted on EventCh once the update is stored.
+func (t *Tracker) commit(ctx context.Context, f *filter, u *update) error {
+	if t.config.Handler != nil {
+		if err := t.deliver(ctx, f, u); err != nil {
+			return err
+		}
+	}
+
+	if len(u.removed) != 0 {
+		if err := f.entry.RemoveLogs(u.removeIndex); err != nil {
+			return err
+		}
+	}
+	if len(u.added) != 0 {
+		if err := f.entry.StoreLogs(u.added); err != nil {
+			return err
+		}
+	}
+	if u.last != nil {
+		if err := t.storeLastBlock(f, u.last); err != nil {
+			return err
+		}
+		// the acknowledged logs are covered by the checkpoint now
+		if err := t.resetAcked(f); err != nil {
+			return err
+		}
+	}
+
+	if t.config.Handler == nil {
+		evnt, err := f.event(u.added, u.removed)
+		if err != nil {
+			return err
+		}
+		t.emitEvent(f, evnt)
+	}
+	return nil
+}
+
+// event returns the event with the logs of the filter. The logs of the
+// transaction filters are decoded as the transactions of the event.
+func (f *filter) event(added, removed []*ethgo.Log) (*Event, error) {
+	if !f.isTx() {
+		return &Event{Added: added, Removed: removed}, nil
+	}
+
+	var err error
+	evnt := &Event{}
+	if evnt.AddedTxs, err = decodeTrackedTxs(added); err != nil {
+		return nil, err
+	}
+	if evnt.RemovedTxs, err = decodeTrackedTxs(removed); err != nil {
+		return nil, err
+	}
+	return evnt, nil
+}
+
+// deliver sends the event of the update to the handler until it is acknowledged or the attempts run out
+func (t *Tracker) deliver(ctx context.Context, f *filter, u *update) error {
+	added := u.added
+	if t.config.AtLeastOnce {
+		added = f.unacked(added)
+	}
+	if len(added) == 0 && len(u.removed) == 0 {
+		return nil
+	}
+
+	evnt, err := f.event(added, u.removed)
+	if err != nil {
+		return err
+	}
+	evnt.Filter = f.config.Name
+
+	attempts := t.config.HandlerAttempts
+	if attempts == 0 {
+		attempts = defaultHandlerAttempts
+	}
+	backoff := t.config.HandlerBackoff
+	if backoff == 0 {
+		backoff = defaultHandlerBackoff
+	}
+
+	for attempt := uint64(1); ; attempt++ {
+		err := t.config.Handler(ctx, evnt)
+		if err == nil {
+			break
+		}
+		if attempt == attempts {
+			return fmt.Errorf("handler failed after %d attempts: %w", attempt, err)
+		}
+		t.logger.Printf("[WARN]: handler failed for filter '%s', retry in %s: %v", f.config.Name, backoff, err)
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+
+	if t.config.AtLeastOnce {
+		return t.ackLogs(f, added)
+	}
+	return nil
+}
+
+// loadAcked loads the idempotency keys of the logs acknowledged after the checkpoint
+func (t *Tracker) loadAcked(f *filter) error {
+	f.acked = map[string]struct{}{}
+	f.ackedKeys = nil
+
+	data, err := t.store.Get(dbAcked + "_" + f.config.Hash)
+	if err != nil {
+		return err
+	}
+	if data == "" {
+		return nil
+	}
+	for _, key := range strings.Split(data, ",") {
+		f.acked[key] = struct{}{}
+		f.ackedKeys = append(f.ackedKeys, key)
+	}
+	return nil
+}
+
+// ackLogs stores the idempotency keys of the acknowledged logs
+func (t *Tracker) ackLogs(f *filter, logs []*ethgo.Log) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	for _, log := range logs {
+		key := IdempotencyKey(log)
+		f.acked[key] = struct{}{}
+		f.ackedKeys = append(f.ackedKeys, key)
+	}
+	return t.store.Set(dbAcked+"_"+f.config.Hash, strings.Join(f.ackedKeys, ","))
+}
+
+// resetAcked removes the idempotency keys once the checkpoint includes the logs
+func (t *Tracker) resetAcked(f *filter) error {
+	if len(f.ackedKeys) == 0 {
+		return nil
+	}
+	f.acked = map[string]struct{}{}
+	f.ackedKeys = nil
+	return t.store.Set(dbAcked+"_"+f.config.Hash, "")
+}
+
+// unacked returns the logs that were not acknowledged yet
+func (f *filter) unacked(logs []*ethgo.Log) []*ethgo.Log {
+	if len(f.acked) == 0 {
+		return logs
+	}
+	res := []*ethgo.Log{}
+	for _, log := range logs {
+		if _, ok := f.acked[IdempotencyKey(log)]; !ok {
+			res = append(res, log)
+		}
+	}
+	return res
+}
@@ -0,0 +1,192 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/testutil"
+	"github.com/Ethernal-Tech/ethgo/tracker/store"
+	"github.com/Ethernal-Tech/ethgo/tracker/store/inmem"
+	"github.com/stretchr/testify/require"
+)
+
+// checkpointStore is a store that fails to store the checkpoint while fail is set
+type checkpointStore struct {
+	store.Store
+
+	lock sync.Mutex
+	fail bool
+}
+
+func (s *checkpointStore) setFail(fail bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.fail = fail
+}
+
+func (s *checkpointStore) Set(k, v string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.fail && strings.HasPrefix(k, dbLastBlock) {
+		return fmt.Errorf("failed to store the checkpoint")
+	}
+	return s.Store.Set(k, v)
+}
+
+// handlerRecorder is a handler that records the logs acknowledged
+type handlerRecorder struct {
+	lock sync.Mutex
+	logs []*ethgo.Log
+	errs int
+
+	// failFrom makes the handler fail for the events with logs after the block
+	failFrom uint64
+}
+
+func (h *handlerRecorder) handle(ctx context.Context, evnt *Event) error {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.errs > 0 {
+		h.errs--
+		return fmt.Errorf("handler error")
+	}
+	for _, log := range evnt.Added {
+		if h.failFrom != 0 && log.BlockNumber >= h.failFrom {
+			return fmt.Errorf("handler error")
+		}
+	}
+	h.logs = append(h.logs, evnt.Added...)
+	return nil
+}
+
+func newHandlerProvider() (*testutil.MockClient, testutil.MockList) {
+	l := testutil.MockList{}
+	l.Create(0, 100, func(b *testutil.MockBlock) {
+		if b.GetNum()%5 == 0 {
+			b.Log("0x1")
+		}
+	})
+
+	m := &testutil.MockClient{}
+	m.AddScenario(l)
+	return m, l
+}
+
+func TestTrackerHandler(t *testing.T) {
+	m, l := newHandlerProvider()
+
+	h := &handlerRecorder{errs: 2}
+	tt, err := NewTracker(m,
+		testConfig(),
+		WithHandler(h.handle),
+		WithHandlerRetry(3, time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	// the events are not sent to EventCh, so nobody has to read it
+	require.NoError(t, tt.BatchSync(context.Background()))
+
+	require.True(t, testutil.CompareLogs(l.GetLogs(), h.logs))
+	require.True(t, testutil.CompareLogs(l.GetLogs(), tt.Entry().(*inmem.Entry).Logs()))
+}
+
+func TestTrackerHandlerCheckpoint(t *testing.T) {
+	m, l := newHandlerProvider()
+	store := inmem.NewInmemStore()
+
+	h := &handlerRecorder{failFrom: 50}
+	tt, err := NewTracker(m,
+		testConfig(),
+		WithStore(store),
+		WithHandler(h.handle),
+		WithHandlerRetry(2, time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	err = tt.BatchSync(context.Background())
+	require.ErrorContains(t, err, "handler failed after 2 attempts")
+
+	// the checkpoint does not include the batch that was not acknowledged
+	last, err := tt.GetLastBlock()
+	require.NoError(t, err)
+	require.Less(t, last.Number, uint64(50))
+	require.True(t, testutil.CompareLogs(h.logs, tt.Entry().(*inmem.Entry).Logs()))
+
+	// the batch is delivered again on restart
+	h.failFrom = 0
+
+	tt, err = NewTracker(m,
+		testConfig(),
+		WithStore(store),
+		WithHandler(h.handle),
+	)
+	require.NoError(t, err)
+	require.NoError(t, tt.BatchSync(context.Background()))
+
+	require.True(t, testutil.CompareLogs(l.GetLogs(), h.logs))
+	require.True(t, testutil.CompareLogs(l.GetLogs(), tt.Entry().(*inmem.Entry).Logs()))
+}
+
+func TestTrackerHandlerAtLeastOnce(t *testing.T) {
+	// run returns the logs delivered before the restart and all the logs delivered
+	run := func(t *testing.T, opts ...ConfigOption) (int, []*ethgo.Log) {
+		m, l := newHandlerProvider()
+		store := &checkpointStore{Store: inmem.NewInmemStore()}
+		h := &handlerRecorder{}
+
+		opts = append(opts, testConfig(), WithStore(store), WithHandler(h.handle))
+
+		// the tracker stops after the first batch is acknowledged
+		// but before the checkpoint is stored
+		store.setFail(true)
+
+		tt, err := NewTracker(m, opts...)
+		require.NoError(t, err)
+		require.Error(t, tt.BatchSync(context.Background()))
+		require.NotEmpty(t, h.logs)
+		acked := len(h.logs)
+
+		store.setFail(false)
+
+		// the logs stored after the checkpoint are removed on restart
+		tt, err = NewTracker(m, opts...)
+		require.NoError(t, err)
+		require.Empty(t, tt.Entry().(*inmem.Entry).Logs())
+
+		require.NoError(t, tt.BatchSync(context.Background()))
+		require.True(t, testutil.CompareLogs(l.GetLogs(), tt.Entry().(*inmem.Entry).Logs()))
+
+		return acked, h.logs
+	}
+
+	_, l := newHandlerProvider()
+
+	t.Run("default", func(t *testing.T) {
+		// the first batch is delivered twice
+		acked, logs := run(t)
+		require.Len(t, logs, len(l.GetLogs())+acked)
+	})
+
+	t.Run("at least once", func(t *testing.T) {
+		// the acknowledged logs are skipped
+		_, logs := run(t, WithAtLeastOnce())
+		require.True(t, testutil.CompareLogs(l.GetLogs(), logs))
+	})
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	log := &ethgo.Log{BlockHash: ethgo.Hash{0x1}, LogIndex: 10}
+	require.Equal(t, ethgo.Hash{0x1}.String()+":10", IdempotencyKey(log))
+
+	// the key changes if the log is included in another block
+	log.BlockHash = ethgo.Hash{0x2}
+	require.NotEqual(t, ethgo.Hash{0x1}.String()+":10", IdempotencyKey(log))
+}
@@ -0,0 +1,164 @@
+package tracker
+
+import (
+	"context"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/tracker/source"
+)
+
+var (
+	_ HistoricalSource = (*source.Etherscan)(nil)
+	_ HistoricalSource = (*source.Blockscout)(nil)
+	_ HistoricalSource = (*source.File)(nil)
+)
+
+// HistoricalSource is a source of historical logs used to fast sync the filters without
+// a checkpoint (i.e. an indexer API or a local dump of logs, see the source package).
+type HistoricalSource interface {
+	// Covers returns true if the source has all the logs of the filter. Otherwise,
+	// the filter is synced from the provider.
+	Covers(filter *ethgo.LogFilter) bool
+
+	// LastBlock returns the last block up to which the source has all the logs
+	LastBlock() (uint64, error)
+
+	// GetLogs returns the logs that match the filter in the range of blocks of the
+	// filter in the order of the chain. The blockHash of the logs is optional, but the
+	// logs without it in the last blocks of the range are synced from the provider.
+	GetLogs(filter *ethgo.LogFilter) ([]*ethgo.Log, error)
+}
+
+// WithHistoricalSource seeds the log filters without a checkpoint with the logs of the
+// source up to its last block before the live sync starts. The filters that the source
+// does not cover are synced from the provider. The logs in the last blocks
+// of the seeded range are validated against the block hashes of the provider.
+func WithHistoricalSource(s HistoricalSource) ConfigOption {
+	return func(c *Config) {
+		c.HistoricalSource = s
+	}
+}
+
+// seed stores the logs of the historical source in the entry of the filter and the last
+// block of the seeded range as the checkpoint. It returns nil if there is no source for
+// the filter or nothing to seed.
+func (t *Tracker) seed(ctx context.Context, f *filter, target *ethgo.Block) (*ethgo.Block, error) {
+	src := t.config.HistoricalSource
+	if src == nil || f.isTx() || !src.Covers(f.config.getFilterSearch()) {
+		return nil, nil
+	}
+
+	last, err := src.LastBlock()
+	if err != nil {
+		return nil, err
+	}
+	if last > target.Number {
+		last = target.Number
+	}
+
+	// like the fast track, the Start block itself is not synced
+	first := f.config.Start
+	if first != 0 {
+		first++
+	}
+	if last == 0 || last < first {
+		return nil, nil
+	}
+
+	from, to := ethgo.BlockNumber(first), ethgo.BlockNumber(last)
+	filter := f.config.getFilterSearch()
+	filter.From = &from
+	filter.To = &to
+
+	srcLogs, err := src.GetLogs(filter)
+	if err != nil {
+		return nil, err
+	}
+	// the source may not match all the topics
+	logs := []*ethgo.Log{}
+	for _, log := range srcLogs {
+		if log.BlockNumber >= first && log.BlockNumber <= last && f.config.match(log) {
+			logs = append(logs, log)
+		}
+	}
+
+	end, err := t.validateSeed(f, logs, first, last)
+	if err != nil {
+		return nil, err
+	}
+	if end == first {
+		return nil, nil
+	}
+	if end <= last {
+		t.logger.Printf("[WARN]: filter '%s' seeded logs of block %d do not match the chain, seeding up to block %d", f.config.Name, end, end-1)
+
+		last = end - 1
+		for indx, log := range logs {
+			if log.BlockNumber > last {
+				logs = logs[:indx]
+				break
+			}
+		}
+	}
+
+	checkpoint, err := t.getBlockByNumber(last)
+	if err != nil {
+		return nil, err
+	}
+	if err := t.commit(ctx, f, &update{added: logs, last: checkpoint}); err != nil {
+		return nil, err
+	}
+	t.logger.Printf("[INFO]: filter '%s' seeded with %d logs up to block %d", f.config.Name, len(logs), last)
+	return checkpoint, nil
+}
+
+// validateSeed compares the seeded logs in the last MaxBlockBacklog blocks of the range,
+// the checkpoint block included, with the logs of the provider since the source may have
+// indexed a fork or may not have all the logs of its last blocks. The logs without a block
+// hash in those blocks do not match. It returns the first block that does not match or
+// last+1 if all of them match.
+func (t *Tracker) validateSeed(f *filter, logs []*ethgo.Log, first, last uint64) (uint64, error) {
+	from := first
+	if window := t.blockTracker.MaxBlockBacklog(); last-first >= window {
+		from = last - window + 1
+	}
+
+	res := t.fetchLogs(f.config.getFilterSearch(), from, last)
+	if res.err != nil {
+		return 0, res.err
+	}
+	expected := map[uint64][]*ethgo.Log{}
+	for _, log := range res.logs {
+		if f.config.match(log) {
+			expected[log.BlockNumber] = append(expected[log.BlockNumber], log)
+		}
+	}
+	seeded := map[uint64][]*ethgo.Log{}
+	for _, log := range logs {
+		if log.BlockNumber >= from {
+			seeded[log.BlockNumber] = append(seeded[log.BlockNumber], log)
+		}
+	}
+
+	for num := from; num <= last; num++ {
+		if !sameLogs(seeded[num], expected[num]) {
+			return num, nil
+		}
+	}
+	return last + 1, nil
+}
+
+// sameLogs returns true if the logs of a block are the same logs of the chain
+func sameLogs(a, b []*ethgo.Log) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for indx := range a {
+		if a[indx].BlockHash != b[indx].BlockHash ||
+			a[indx].TransactionHash != b[indx].TransactionHash ||
+			a[indx].LogIndex != b[indx].LogIndex {
+			return false
+		}
+	}
+	return true
+}
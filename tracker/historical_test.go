@@ -0,0 +1,160 @@
+package tracker
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/testutil"
+	"github.com/Ethernal-Tech/ethgo/tracker/store/inmem"
+	"github.com/stretchr/testify/require"
+)
+
+// mockSource is a historical source with the logs up to the last block. Without
+// covers, it covers all the filters.
+type mockSource struct {
+	last   uint64
+	logs   []*ethgo.Log
+	covers func(filter *ethgo.LogFilter) bool
+}
+
+func (m *mockSource) Covers(filter *ethgo.LogFilter) bool {
+	return m.covers == nil || m.covers(filter)
+}
+
+func (m *mockSource) LastBlock() (uint64, error) {
+	return m.last, nil
+}
+
+func (m *mockSource) GetLogs(filter *ethgo.LogFilter) ([]*ethgo.Log, error) {
+	if !m.Covers(filter) {
+		return nil, fmt.Errorf("filter not covered")
+	}
+	res := []*ethgo.Log{}
+	for _, log := range m.logs {
+		if log.BlockNumber >= uint64(*filter.From) && log.BlockNumber <= uint64(*filter.To) {
+			res = append(res, log)
+		}
+	}
+	return res, nil
+}
+
+func logsUpTo(logs []*ethgo.Log, num uint64) []*ethgo.Log {
+	res := []*ethgo.Log{}
+	for _, log := range logs {
+		if log.BlockNumber <= num {
+			res = append(res, log)
+		}
+	}
+	return res
+}
+
+func testHistoricalSource(t *testing.T, src *mockSource, seeded uint64) {
+	m, l := newHandlerProvider()
+	if src.logs == nil {
+		src.logs = l.GetLogs()
+	}
+
+	h := &eventRecorder{}
+	tt, err := NewTracker(m,
+		testConfig(),
+		WithHandler(h.handle),
+		WithHistoricalSource(src),
+	)
+	require.NoError(t, err)
+	require.NoError(t, tt.BatchSync(context.Background()))
+
+	// the logs of the source are seeded in the first event
+	events := h.events[""]
+	require.True(t, testutil.CompareLogs(logsUpTo(l.GetLogs(), seeded), events[0].Added))
+
+	// the rest of the logs are synced from the provider
+	require.True(t, testutil.CompareLogs(l.GetLogs(), tt.Entry().(*inmem.Entry).Logs()))
+}
+
+func TestTrackerHistoricalSource(t *testing.T) {
+	testHistoricalSource(t, &mockSource{last: 60}, 60)
+}
+
+func TestTrackerHistoricalSourceNoBlockHash(t *testing.T) {
+	_, l := newHandlerProvider()
+
+	// the logs without a block hash are not valid in the last 10 blocks
+	src := &mockSource{last: 60}
+	for _, log := range l.GetLogs() {
+		if log.BlockNumber > 50 {
+			log.BlockHash = ethgo.ZeroHash
+		}
+		src.logs = append(src.logs, log)
+	}
+	testHistoricalSource(t, src, 54)
+}
+
+func TestTrackerHistoricalSourceMissingLogs(t *testing.T) {
+	_, l := newHandlerProvider()
+
+	// the source does not have the logs of its last block
+	testHistoricalSource(t, &mockSource{last: 60, logs: logsUpTo(l.GetLogs(), 59)}, 59)
+}
+
+func TestTrackerHistoricalSourceFork(t *testing.T) {
+	m, l := newHandlerProvider()
+
+	// the source indexed a fork from block 55
+	fork := testutil.MockList{}
+	fork.Create(0, 61, func(b *testutil.MockBlock) {
+		if b.GetNum() >= 55 {
+			b.Extra("f0")
+		}
+		if b.GetNum()%5 == 0 {
+			b.Log("0x1")
+		}
+	})
+	src := &mockSource{last: 60, logs: fork.GetLogs()}
+
+	h := &eventRecorder{}
+	tt, err := NewTracker(m,
+		testConfig(),
+		WithHandler(h.handle),
+		WithHistoricalSource(src),
+	)
+	require.NoError(t, err)
+	require.NoError(t, tt.BatchSync(context.Background()))
+
+	// the seeded range ends before the fork
+	events := h.events[""]
+	require.True(t, testutil.CompareLogs(logsUpTo(l.GetLogs(), 54), events[0].Added))
+
+	// the logs of the fork are synced from the provider
+	require.True(t, testutil.CompareLogs(l.GetLogs(), tt.Entry().(*inmem.Entry).Logs()))
+}
+
+func TestTrackerHistoricalSourceCovers(t *testing.T) {
+	m, l := newHandlerProvider()
+
+	// the source only covers the filters with addresses
+	src := &mockSource{last: 60, logs: l.GetLogs(), covers: func(filter *ethgo.LogFilter) bool {
+		return len(filter.Address) != 0
+	}}
+
+	h := &eventRecorder{}
+	tt, err := NewTracker(m,
+		testConfig(),
+		WithHandler(h.handle),
+		WithHistoricalSource(src),
+		WithFilters(
+			&FilterConfig{Name: "a", Address: []ethgo.Address{ethgo.ZeroAddress}},
+			&FilterConfig{Name: "b"},
+		),
+	)
+	require.NoError(t, err)
+	require.NoError(t, tt.BatchSync(context.Background()))
+
+	// the filter with addresses is seeded by the source
+	require.True(t, testutil.CompareLogs(logsUpTo(l.GetLogs(), 60), h.events["a"][0].Added))
+	require.True(t, testutil.CompareLogs(l.GetLogs(), entryLogs(t, tt, "a")))
+
+	// the other filter is synced from the provider
+	require.True(t, testutil.CompareLogs(l.GetLogs(), entryLogs(t, tt, "b")))
+}
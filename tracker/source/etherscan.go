@@ -0,0 +1,115 @@
+package source
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/etherscan"
+)
+
+// pageSize is the maximum number of logs returned by a getLogs query of the API
+const pageSize = 1000
+
+// Etherscan is a historical source that queries the logs from the Etherscan API
+type Etherscan struct {
+	client *etherscan.Etherscan
+}
+
+// NewEtherscan creates a new historical source from an Etherscan client
+func NewEtherscan(client *etherscan.Etherscan) *Etherscan {
+	return &Etherscan{client: client}
+}
+
+// LastBlock returns the last block indexed by the API
+func (e *Etherscan) LastBlock() (uint64, error) {
+	return e.client.BlockNumber()
+}
+
+// Covers returns true if the filter has addresses since the API requires them
+func (e *Etherscan) Covers(filter *ethgo.LogFilter) bool {
+	return len(filter.Address) != 0
+}
+
+// GetLogs returns the logs of the filter. The API requires the addresses
+// of the filter and the topics are matched once the logs are returned.
+func (e *Etherscan) GetLogs(filter *ethgo.LogFilter) ([]*ethgo.Log, error) {
+	from, to, err := blockRange(filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(filter.Address) == 0 {
+		return nil, fmt.Errorf("an address to filter is required")
+	}
+
+	res := []*ethgo.Log{}
+	for _, addr := range filter.Address {
+		logs, err := e.getLogs(addr, from, to)
+		if err != nil {
+			return nil, err
+		}
+		for _, log := range logs {
+			if matchLog(filter, log) {
+				res = append(res, log)
+			}
+		}
+	}
+	sortLogs(res)
+	return res, nil
+}
+
+// getLogs returns all the logs of the address in the range. The API returns up to
+// pageSize logs per query, so the range continues from the block of the last log
+// until a query returns less logs.
+func (e *Etherscan) getLogs(addr ethgo.Address, from, to uint64) ([]*ethgo.Log, error) {
+	res := []*ethgo.Log{}
+	for from <= to {
+		params := map[string]string{
+			"address":   addr.String(),
+			"fromBlock": strconv.FormatUint(from, 10),
+			"toBlock":   strconv.FormatUint(to, 10),
+			"page":      "1",
+			"offset":    strconv.Itoa(pageSize),
+		}
+		var logs []*ethgo.Log
+		if err := e.client.Query("logs", "getLogs", &logs, params); err != nil {
+			return nil, err
+		}
+		if len(logs) < pageSize {
+			return append(res, logs...), nil
+		}
+
+		// the logs of the last block may be incomplete, query them again
+		last := logs[len(logs)-1].BlockNumber
+		if last <= from {
+			return nil, fmt.Errorf("block %d has more than %d logs of address %s", from, pageSize, addr)
+		}
+		for _, log := range logs {
+			if log.BlockNumber < last {
+				res = append(res, log)
+			}
+		}
+		from = last
+	}
+	return res, nil
+}
+
+// Blockscout is a historical source that queries the logs from the
+// Etherscan compatible API of a Blockscout instance
+type Blockscout struct {
+	*Etherscan
+}
+
+// NewBlockscout creates a new historical source from the url of a Blockscout instance
+func NewBlockscout(url, apiKey string) *Blockscout {
+	return &Blockscout{Etherscan: NewEtherscan(etherscan.NewEtherscan(url, apiKey))}
+}
+
+// LastBlock returns the last block indexed by the instance
+func (b *Blockscout) LastBlock() (uint64, error) {
+	var out string
+	if err := b.client.Query("block", "eth_block_number", &out, nil); err != nil {
+		return 0, err
+	}
+	return parseUint64orHex(out)
+}
@@ -0,0 +1,109 @@
+package source
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/etherscan"
+	"github.com/stretchr/testify/require"
+)
+
+// newMockAPI returns an Etherscan compatible API with 600 logs in blocks 1 and 2
+// and a log in block 3 that does not match the topic
+func newMockAPI(t *testing.T) *httptest.Server {
+	logs := []map[string]interface{}{}
+	for _, num := range []uint64{1, 2} {
+		for i := 0; i < 600; i++ {
+			logs = append(logs, map[string]interface{}{
+				"address":          addr1,
+				"topics":           []ethgo.Hash{topic1},
+				"data":             "0x",
+				"blockNumber":      fmt.Sprintf("0x%x", num),
+				"logIndex":         fmt.Sprintf("0x%x", i),
+				"transactionIndex": "0x",
+				"transactionHash":  hashA,
+			})
+		}
+	}
+	logs = append(logs, map[string]interface{}{
+		"address":          addr1,
+		"topics":           []ethgo.Hash{topic2},
+		"data":             "0x",
+		"blockNumber":      "0x3",
+		"logIndex":         "0x",
+		"transactionIndex": "0x",
+		"transactionHash":  hashA,
+	})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+
+		var res interface{}
+		switch query.Get("module") + "_" + query.Get("action") {
+		case "proxy_eth_blockNumber", "block_eth_block_number":
+			res = map[string]interface{}{"jsonrpc": "2.0", "id": 1, "result": "0x64"}
+
+		case "logs_getLogs":
+			require.Equal(t, addr1.String(), query.Get("address"))
+
+			from, _ := strconv.ParseUint(query.Get("fromBlock"), 10, 64)
+			to, _ := strconv.ParseUint(query.Get("toBlock"), 10, 64)
+			offset, _ := strconv.Atoi(query.Get("offset"))
+
+			result := []map[string]interface{}{}
+			for _, log := range logs {
+				num, _ := parseUint64orHex(log["blockNumber"].(string))
+				if num >= from && num <= to && len(result) < offset {
+					result = append(result, log)
+				}
+			}
+			res = map[string]interface{}{"status": "1", "message": "OK", "result": result}
+
+		default:
+			t.Fatalf("unexpected query %s", r.URL)
+		}
+		require.NoError(t, json.NewEncoder(w).Encode(res))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestEtherscanSource(t *testing.T) {
+	srv := newMockAPI(t)
+
+	for _, src := range []interface {
+		Covers(filter *ethgo.LogFilter) bool
+		LastBlock() (uint64, error)
+		GetLogs(filter *ethgo.LogFilter) ([]*ethgo.Log, error)
+	}{
+		NewEtherscan(etherscan.NewEtherscan(srv.URL, "")),
+		NewBlockscout(srv.URL, ""),
+	} {
+		last, err := src.LastBlock()
+		require.NoError(t, err)
+		require.Equal(t, uint64(100), last)
+
+		filter := rangeFilter(0, 10)
+		filter.Address = []ethgo.Address{addr1}
+		filter.Topics = [][]*ethgo.Hash{{&topic1}}
+
+		// the logs of block 2 are split in two pages
+		logs, err := src.GetLogs(filter)
+		require.NoError(t, err)
+		require.Len(t, logs, 1200)
+		require.Equal(t, uint64(2), logs[1199].BlockNumber)
+		require.Equal(t, uint64(599), logs[1199].LogIndex)
+
+		// the address is required
+		require.True(t, src.Covers(filter))
+		require.False(t, src.Covers(rangeFilter(0, 10)))
+
+		_, err = src.GetLogs(rangeFilter(0, 10))
+		require.Error(t, err)
+	}
+}
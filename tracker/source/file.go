@@ -0,0 +1,243 @@
+package source
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/Ethernal-Tech/ethgo"
+)
+
+// File is a historical source that reads the logs from a local dump (i.e. an archive export).
+// The dump is scanned on each query, so it is not loaded in memory.
+type File struct {
+	path      string
+	addresses []ethgo.Address
+	read      func(r io.Reader, handler func(log *ethgo.Log) error) error
+
+	lock sync.Mutex
+	last *uint64
+}
+
+// NewJSONL creates a historical source from a dump with a log in JSON format per line
+// (the format of eth_getLogs). The dump has all the logs of the addresses or, without
+// addresses, all the logs of the chain.
+func NewJSONL(path string, addresses ...ethgo.Address) *File {
+	return &File{path: path, addresses: addresses, read: readJSONL}
+}
+
+// NewCSV creates a historical source from a dump of logs in CSV format. The first row is
+// the header with the names of the columns: block_number, log_index and address are required,
+// and block_hash, tx_hash, tx_index, data and topic0 to topic3 are optional. The numbers are
+// either decimal or hex with the 0x prefix and the empty topics are not included in the log.
+// Like NewJSONL, the dump has all the logs of the addresses or of the chain.
+func NewCSV(path string, addresses ...ethgo.Address) *File {
+	return &File{path: path, addresses: addresses, read: readCSV}
+}
+
+func (f *File) scan(handler func(log *ethgo.Log) error) error {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	return f.read(file, handler)
+}
+
+// Covers returns true if the dump has all the logs of the chain or the filter only
+// matches the addresses of the dump
+func (f *File) Covers(filter *ethgo.LogFilter) bool {
+	if len(f.addresses) == 0 {
+		return true
+	}
+	if len(filter.Address) == 0 {
+		return false
+	}
+	for _, addr := range filter.Address {
+		found := false
+		for _, dumpAddr := range f.addresses {
+			if addr == dumpAddr {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// LastBlock returns the highest block with logs in the dump
+func (f *File) LastBlock() (uint64, error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.last != nil {
+		return *f.last, nil
+	}
+	last := uint64(0)
+	err := f.scan(func(log *ethgo.Log) error {
+		if log.BlockNumber > last {
+			last = log.BlockNumber
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	f.last = &last
+	return last, nil
+}
+
+// GetLogs returns the logs of the dump that match the filter
+func (f *File) GetLogs(filter *ethgo.LogFilter) ([]*ethgo.Log, error) {
+	from, to, err := blockRange(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*ethgo.Log{}
+	err = f.scan(func(log *ethgo.Log) error {
+		if log.BlockNumber >= from && log.BlockNumber <= to && matchLog(filter, log) {
+			res = append(res, log)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortLogs(res)
+	return res, nil
+}
+
+func readJSONL(r io.Reader, handler func(log *ethgo.Log) error) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 16*1024*1024)
+
+	for line := 1; scanner.Scan(); line++ {
+		buf := bytes.TrimSpace(scanner.Bytes())
+		if len(buf) == 0 {
+			continue
+		}
+		if !json.Valid(buf) {
+			return fmt.Errorf("line %d: invalid json", line)
+		}
+		log := &ethgo.Log{}
+		if err := log.UnmarshalJSON(buf); err != nil {
+			return fmt.Errorf("line %d: %v", line, err)
+		}
+		if err := handler(log); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+func readCSV(r io.Reader, handler func(log *ethgo.Log) error) error {
+	reader := csv.NewReader(r)
+	reader.ReuseRecord = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	columns := map[string]int{}
+	for indx, name := range header {
+		columns[strings.TrimSpace(name)] = indx
+	}
+	for _, name := range []string{"block_number", "log_index", "address"} {
+		if _, ok := columns[name]; !ok {
+			return fmt.Errorf("column '%s' not found", name)
+		}
+	}
+
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		log, err := csvLog(columns, record)
+		if err != nil {
+			return fmt.Errorf("line %d: %v", line, err)
+		}
+		if err := handler(log); err != nil {
+			return err
+		}
+	}
+}
+
+func csvLog(columns map[string]int, record []string) (*ethgo.Log, error) {
+	value := func(name string) string {
+		indx, ok := columns[name]
+		if !ok || indx >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[indx])
+	}
+	number := func(name string) (uint64, error) {
+		str := value(name)
+		if str == "" {
+			return 0, nil
+		}
+		num, err := parseUint64orHex(str)
+		if err != nil {
+			return 0, fmt.Errorf("invalid %s '%s'", name, str)
+		}
+		return num, nil
+	}
+
+	var err error
+	log := &ethgo.Log{}
+	if log.BlockNumber, err = number("block_number"); err != nil {
+		return nil, err
+	}
+	if log.LogIndex, err = number("log_index"); err != nil {
+		return nil, err
+	}
+	if log.TransactionIndex, err = number("tx_index"); err != nil {
+		return nil, err
+	}
+	if err := log.Address.UnmarshalText([]byte(value("address"))); err != nil {
+		return nil, err
+	}
+	if str := value("block_hash"); str != "" {
+		if err := log.BlockHash.UnmarshalText([]byte(str)); err != nil {
+			return nil, err
+		}
+	}
+	if str := value("tx_hash"); str != "" {
+		if err := log.TransactionHash.UnmarshalText([]byte(str)); err != nil {
+			return nil, err
+		}
+	}
+	if str := value("data"); str != "" && str != "0x" {
+		if log.Data, err = hex.DecodeString(strings.TrimPrefix(str, "0x")); err != nil {
+			return nil, fmt.Errorf("invalid data '%s'", str)
+		}
+	}
+	for i := 0; i < 4; i++ {
+		str := value(fmt.Sprintf("topic%d", i))
+		if str == "" {
+			break
+		}
+		var topic ethgo.Hash
+		if err := topic.UnmarshalText([]byte(str)); err != nil {
+			return nil, err
+		}
+		log.Topics = append(log.Topics, topic)
+	}
+	return log, nil
+}
@@ -0,0 +1,108 @@
+package source
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	addr1  = ethgo.HexToAddress("0x1")
+	addr2  = ethgo.HexToAddress("0x2")
+	topic1 = ethgo.HexToHash("0x1")
+	topic2 = ethgo.HexToHash("0x2")
+
+	hashA = ethgo.HexToHash("0xa")
+	hashB = ethgo.HexToHash("0xb")
+	hashC = ethgo.HexToHash("0xc")
+)
+
+func writeDump(t *testing.T, name, data string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(data), 0600))
+	return path
+}
+
+func rangeFilter(from, to uint64) *ethgo.LogFilter {
+	f, l := ethgo.BlockNumber(from), ethgo.BlockNumber(to)
+	return &ethgo.LogFilter{From: &f, To: &l}
+}
+
+func testDump(t *testing.T, src *File) {
+	last, err := src.LastBlock()
+	require.NoError(t, err)
+	require.Equal(t, uint64(12), last)
+
+	// the logs are returned in the order of the chain
+	logs, err := src.GetLogs(rangeFilter(0, 12))
+	require.NoError(t, err)
+	require.Len(t, logs, 3)
+	require.Equal(t, uint64(10), logs[0].BlockNumber)
+	require.Equal(t, uint64(1), logs[1].LogIndex)
+	require.Equal(t, uint64(12), logs[2].BlockNumber)
+
+	require.Equal(t, hashA, logs[0].BlockHash)
+	require.Equal(t, hashB, logs[0].TransactionHash)
+	require.Equal(t, []ethgo.Hash{topic1, topic2}, logs[0].Topics)
+	require.Equal(t, []byte{0x1, 0x2}, logs[0].Data)
+
+	// the logs without a block hash
+	require.Equal(t, ethgo.ZeroHash, logs[2].BlockHash)
+
+	logs, err = src.GetLogs(rangeFilter(11, 12))
+	require.NoError(t, err)
+	require.Len(t, logs, 1)
+
+	filter := rangeFilter(0, 12)
+	filter.Address = []ethgo.Address{addr1}
+	filter.Topics = [][]*ethgo.Hash{{&topic1}}
+
+	logs, err = src.GetLogs(filter)
+	require.NoError(t, err)
+	require.Len(t, logs, 2)
+}
+
+func TestFileJSONL(t *testing.T) {
+	path := writeDump(t, "logs.jsonl", fmt.Sprintf(`
+{"blockNumber":"0xc","logIndex":"0x0","transactionIndex":"0x0","transactionHash":"%[5]s","address":"%[2]s","data":"0x","topics":[]}
+{"blockNumber":"0xa","blockHash":"%[3]s","logIndex":"0x1","transactionIndex":"0x0","transactionHash":"%[4]s","address":"%[1]s","data":"0x","topics":["%[6]s"]}
+{"blockNumber":"0xa","blockHash":"%[3]s","logIndex":"0x0","transactionIndex":"0x0","transactionHash":"%[4]s","address":"%[1]s","data":"0x0102","topics":["%[6]s","%[7]s"]}
+`, addr1, addr2, hashA, hashB, hashC, topic1, topic2))
+	testDump(t, NewJSONL(path))
+
+	_, err := NewJSONL(writeDump(t, "invalid.jsonl", "{")).LastBlock()
+	require.Error(t, err)
+}
+
+func TestFileCSV(t *testing.T) {
+	path := writeDump(t, "logs.csv", fmt.Sprintf(`block_number,block_hash,tx_hash,log_index,address,data,topic0,topic1
+12,,%[5]s,0,%[2]s,0x,,
+0xa,%[3]s,%[4]s,1,%[1]s,,%[6]s,
+10,%[3]s,%[4]s,0,%[1]s,0x0102,%[6]s,%[7]s
+`, addr1, addr2, hashA, hashB, hashC, topic1, topic2))
+	testDump(t, NewCSV(path))
+
+	_, err := NewCSV(writeDump(t, "invalid.csv", "block_number,address\n")).LastBlock()
+	require.ErrorContains(t, err, "column 'log_index' not found")
+}
+
+func TestFileCovers(t *testing.T) {
+	filter := func(addrs ...ethgo.Address) *ethgo.LogFilter {
+		return &ethgo.LogFilter{Address: addrs}
+	}
+
+	// a dump of all the logs of the chain
+	src := NewJSONL("logs.jsonl")
+	require.True(t, src.Covers(filter()))
+	require.True(t, src.Covers(filter(addr1)))
+
+	// a dump of the logs of an address
+	src = NewCSV("logs.csv", addr1)
+	require.True(t, src.Covers(filter(addr1)))
+	require.False(t, src.Covers(filter(addr1, addr2)))
+	require.False(t, src.Covers(filter()))
+}
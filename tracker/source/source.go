@@ -0,0 +1,77 @@
+// Package source implements the historical sources of logs used to fast sync the tracker
+package source
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Ethernal-Tech/ethgo"
+)
+
+// blockRange returns the range of blocks of the filter. The filter of a
+// historical source must have an explicit range.
+func blockRange(filter *ethgo.LogFilter) (uint64, uint64, error) {
+	if filter.From == nil || filter.To == nil {
+		return 0, 0, fmt.Errorf("the historical source requires a range of blocks")
+	}
+	if *filter.From < 0 || *filter.To < 0 {
+		return 0, 0, fmt.Errorf("the historical source does not support block tags")
+	}
+	return uint64(*filter.From), uint64(*filter.To), nil
+}
+
+// matchLog returns true if the log matches the addresses and the topics of the filter
+func matchLog(filter *ethgo.LogFilter, log *ethgo.Log) bool {
+	if len(filter.Address) != 0 {
+		found := false
+		for _, addr := range filter.Address {
+			if addr == log.Address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for indx, topics := range filter.Topics {
+		if len(topics) == 0 {
+			continue
+		}
+		if indx >= len(log.Topics) {
+			return false
+		}
+		found := false
+		for _, topic := range topics {
+			if topic == nil || *topic == log.Topics[indx] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// sortLogs sorts the logs in the order of the chain
+func sortLogs(logs []*ethgo.Log) {
+	sort.SliceStable(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].LogIndex < logs[j].LogIndex
+	})
+}
+
+func parseUint64orHex(str string) (uint64, error) {
+	base := 10
+	if strings.HasPrefix(str, "0x") {
+		str = str[2:]
+		base = 16
+	}
+	return strconv.ParseUint(str, base, 64)
+}
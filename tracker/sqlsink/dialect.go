@@ -0,0 +1,114 @@
+package sqlsink
+
+import (
+	"fmt"
+
+	"github.com/Ethernal-Tech/ethgo/abi"
+)
+
+// Dialect is the SQL dialect of the database
+type Dialect string
+
+const (
+	// DialectPostgres is the dialect of PostgreSQL (i.e. lib/pq)
+	DialectPostgres Dialect = "postgres"
+
+	// DialectSQLite is the dialect of SQLite (i.e. modernc.org/sqlite or mattn/go-sqlite3)
+	DialectSQLite Dialect = "sqlite"
+)
+
+func (d Dialect) validate() error {
+	if d != DialectPostgres && d != DialectSQLite {
+		return fmt.Errorf("dialect '%s' not supported", d)
+	}
+	return nil
+}
+
+// placeholder returns the placeholder of the n-th (starting at 1) argument of a query
+func (d Dialect) placeholder(n int) string {
+	if d == DialectPostgres {
+		return fmt.Sprintf("$%d", n)
+	}
+	return "?"
+}
+
+// columnKind is how a value of an abi type is stored in a column
+type columnKind int
+
+const (
+	columnBool columnKind = iota
+	columnInt
+	columnNumeric
+	columnText
+	columnBytes
+	columnJSON
+)
+
+// kindOf returns the column kind for an abi type. Indexed arguments with dynamic
+// types are only available as the hash in the topic and are stored as bytes.
+func kindOf(typ *abi.Type, indexed bool) columnKind {
+	switch typ.Kind() {
+	case abi.KindBool:
+		return columnBool
+
+	case abi.KindInt:
+		if typ.Size() <= 64 {
+			return columnInt
+		}
+		return columnNumeric
+
+	case abi.KindUInt:
+		// a BIGINT column is signed and cannot fit an uint64
+		if typ.Size() < 64 {
+			return columnInt
+		}
+		return columnNumeric
+
+	case abi.KindAddress, abi.KindFixedPoint:
+		return columnText
+
+	case abi.KindFixedBytes, abi.KindFunction:
+		return columnBytes
+	}
+
+	if indexed {
+		return columnBytes
+	}
+	switch typ.Kind() {
+	case abi.KindString:
+		return columnText
+	case abi.KindBytes:
+		return columnBytes
+	default:
+		// arrays, slices and tuples
+		return columnJSON
+	}
+}
+
+// columnType returns the SQL type of the column kind
+func (d Dialect) columnType(kind columnKind) string {
+	switch kind {
+	case columnBool:
+		return "BOOLEAN"
+	case columnInt:
+		return "BIGINT"
+	case columnBytes:
+		if d == DialectPostgres {
+			return "BYTEA"
+		}
+		return "BLOB"
+	case columnNumeric:
+		if d == DialectPostgres {
+			return "NUMERIC(78)"
+		}
+		// sqlite converts the big numbers in a NUMERIC column to a (lossy) REAL
+		return "TEXT"
+	case columnJSON:
+		if d == DialectPostgres {
+			return "JSONB"
+		}
+		return "TEXT"
+	default:
+		return "TEXT"
+	}
+}
@@ -0,0 +1,364 @@
+package sqlsink
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/abi"
+	"github.com/Ethernal-Tech/ethgo/tracker"
+)
+
+const defaultTablePrefix = "event_"
+
+// metadata columns of the log included in every table
+var metadataColumns = []string{
+	"block_number",
+	"block_hash",
+	"tx_hash",
+	"tx_index",
+	"log_index",
+	"address",
+}
+
+// Config is the configuration of the sink
+type Config struct {
+	// TablePrefix is the prefix of the table of each event
+	TablePrefix string
+}
+
+type ConfigOption func(*Config)
+
+// WithTablePrefix sets the prefix of the event tables (by default 'event_')
+func WithTablePrefix(prefix string) ConfigOption {
+	return func(c *Config) {
+		c.TablePrefix = prefix
+	}
+}
+
+// DefaultConfig returns the default sink config
+func DefaultConfig() *Config {
+	return &Config{
+		TablePrefix: defaultTablePrefix,
+	}
+}
+
+// Sink stores the events of an abi decoded from the logs of the tracker, one
+// table per event. The logs of EventAdd are inserted and the logs of EventDel
+// (i.e. after a reorg) are deleted.
+type Sink struct {
+	db      *sql.DB
+	dialect Dialect
+	tables  map[ethgo.Hash]*table
+}
+
+// NewSink creates a new sink for the events of the abi and creates the tables
+// if they do not exist. Anonymous events are not stored since they cannot be matched by the topic.
+func NewSink(db *sql.DB, dialect Dialect, a *abi.ABI, opts ...ConfigOption) (*Sink, error) {
+	config := DefaultConfig()
+	for _, opt := range opts {
+		opt(config)
+	}
+	if err := dialect.validate(); err != nil {
+		return nil, err
+	}
+
+	s := &Sink{
+		db:      db,
+		dialect: dialect,
+		tables:  map[ethgo.Hash]*table{},
+	}
+
+	// create the tables in order
+	names := []string{}
+	for name := range a.Events {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		evnt := a.Events[name]
+		if evnt.Anonymous {
+			continue
+		}
+		t := newTable(config.TablePrefix+toSnakeCase(name), evnt, dialect)
+		for _, stmt := range t.schema(dialect) {
+			if _, err := db.Exec(stmt); err != nil {
+				return nil, fmt.Errorf("failed to create table for event '%s': %v", name, err)
+			}
+		}
+		s.tables[evnt.ID()] = t
+	}
+	return s, nil
+}
+
+// Table returns the name of the table of the event or an empty string if the event is not stored
+func (s *Sink) Table(event string) string {
+	for _, t := range s.tables {
+		if t.event.Name == event {
+			return t.name
+		}
+	}
+	return ""
+}
+
+// Handle stores the logs of the event in a single transaction. The removed logs
+// are deleted before the added logs are inserted. The logs that do not match
+// any of the events of the abi are ignored.
+func (s *Sink) Handle(evnt *tracker.Event) error {
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, log := range evnt.Removed {
+		t := s.match(log)
+		if t == nil {
+			continue
+		}
+		if _, err := tx.Exec(t.delete, log.BlockHash.String(), log.LogIndex); err != nil {
+			return err
+		}
+	}
+
+	for _, log := range evnt.Added {
+		t := s.match(log)
+		if t == nil {
+			continue
+		}
+		args, err := t.values(log)
+		if err != nil {
+			return fmt.Errorf("failed to decode event '%s': %v", t.event.Name, err)
+		}
+		if _, err := tx.Exec(t.insert, args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Run stores the events of the channel (i.e. the EventCh of the tracker)
+// until the channel is closed or the context is done
+func (s *Sink) Run(ctx context.Context, eventCh <-chan *tracker.Event) error {
+	for {
+		select {
+		case evnt, ok := <-eventCh:
+			if !ok {
+				return nil
+			}
+			if err := s.Handle(evnt); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// match returns the table of the log or nil if the log is not one of the events.
+// Events with the same signature but a different number of indexed arguments
+// (i.e. the ERC20 and ERC721 Transfer events) do not match.
+func (s *Sink) match(log *ethgo.Log) *table {
+	if len(log.Topics) == 0 {
+		return nil
+	}
+	t, ok := s.tables[log.Topics[0]]
+	if !ok || len(log.Topics) != t.numIndexed+1 {
+		return nil
+	}
+	return t
+}
+
+// table is the table of an event
+type table struct {
+	name    string
+	event   *abi.Event
+	columns []*column
+
+	numIndexed int
+	nonIndexed *abi.Type
+
+	insert string
+	delete string
+}
+
+// column is the column of an argument of the event
+type column struct {
+	name string
+	arg  *abi.TupleElem
+	kind columnKind
+
+	// key is the key of a non indexed argument in the decoded data. The unnamed
+	// arguments are decoded by their position among the non indexed arguments.
+	key string
+}
+
+func newTable(name string, evnt *abi.Event, dialect Dialect) *table {
+	t := &table{
+		name:  name,
+		event: evnt,
+	}
+
+	reserved := map[string]bool{}
+	for _, name := range metadataColumns {
+		reserved[name] = true
+	}
+
+	nonIndexed := []*abi.TupleElem{}
+	for indx, arg := range evnt.Inputs.TupleElems() {
+		name := toSnakeCase(arg.Name)
+		if name == "" {
+			name = fmt.Sprintf("arg%d", indx)
+		}
+		if reserved[name] {
+			name = "arg_" + name
+		}
+		reserved[name] = true
+
+		c := &column{
+			name: name,
+			arg:  arg,
+			kind: kindOf(arg.Elem, arg.Indexed),
+		}
+		if arg.Indexed {
+			t.numIndexed++
+		} else {
+			c.key = arg.Name
+			if c.key == "" {
+				c.key = strconv.Itoa(len(nonIndexed))
+			}
+			nonIndexed = append(nonIndexed, arg)
+		}
+		t.columns = append(t.columns, c)
+	}
+	t.nonIndexed = abi.NewTupleType(nonIndexed)
+
+	names := []string{}
+	placeholders := []string{}
+	for indx, name := range t.columnNames() {
+		names = append(names, quote(name))
+		placeholders = append(placeholders, dialect.placeholder(indx+1))
+	}
+	t.insert = fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT DO NOTHING",
+		quote(t.name), strings.Join(names, ", "), strings.Join(placeholders, ", "))
+	t.delete = fmt.Sprintf("DELETE FROM %s WHERE %s = %s AND %s = %s",
+		quote(t.name), quote("block_hash"), dialect.placeholder(1), quote("log_index"), dialect.placeholder(2))
+
+	return t
+}
+
+func (t *table) columnNames() []string {
+	names := append([]string{}, metadataColumns...)
+	for _, c := range t.columns {
+		names = append(names, c.name)
+	}
+	return names
+}
+
+// schema returns the statements to create the table and its indexes
+func (t *table) schema(dialect Dialect) []string {
+	columns := []string{
+		quote("block_number") + " BIGINT NOT NULL",
+		quote("block_hash") + " TEXT NOT NULL",
+		quote("tx_hash") + " TEXT NOT NULL",
+		quote("tx_index") + " BIGINT NOT NULL",
+		quote("log_index") + " BIGINT NOT NULL",
+		quote("address") + " TEXT NOT NULL",
+	}
+	for _, c := range t.columns {
+		columns = append(columns, quote(c.name)+" "+dialect.columnType(c.kind))
+	}
+	columns = append(columns, fmt.Sprintf("PRIMARY KEY (%s, %s)", quote("block_hash"), quote("log_index")))
+
+	return []string{
+		fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s (\n\t%s\n)", quote(t.name), strings.Join(columns, ",\n\t")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (%s)", quote(t.name+"_block_number"), quote(t.name), quote("block_number")),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (%s)", quote(t.name+"_address"), quote(t.name), quote("address")),
+	}
+}
+
+// values decodes the log and returns the values of the columns
+func (t *table) values(log *ethgo.Log) ([]interface{}, error) {
+	var nonIndexed map[string]interface{}
+	if len(t.nonIndexed.TupleElems()) != 0 {
+		raw, err := abi.Decode(t.nonIndexed, log.Data)
+		if err != nil {
+			return nil, err
+		}
+		obj, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("bad decoding")
+		}
+		nonIndexed = obj
+	}
+
+	values := []interface{}{
+		log.BlockNumber,
+		log.BlockHash.String(),
+		log.TransactionHash.String(),
+		log.TransactionIndex,
+		log.LogIndex,
+		log.Address.String(),
+	}
+
+	topics := log.Topics[1:]
+	for _, c := range t.columns {
+		var val interface{}
+		if c.arg.Indexed {
+			topic := topics[0]
+			topics = topics[1:]
+
+			if c.kind == columnBytes && c.arg.Elem.Kind() != abi.KindFixedBytes {
+				// only the hash of the value is in the topic
+				val = topic.Bytes()
+			} else {
+				var err error
+				if val, err = abi.ParseTopic(c.arg.Elem, topic); err != nil {
+					return nil, fmt.Errorf("failed to parse topic '%s': %v", c.name, err)
+				}
+			}
+		} else {
+			val = nonIndexed[c.key]
+		}
+
+		res, err := c.kind.value(val)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert argument '%s': %v", c.name, err)
+		}
+		values = append(values, res)
+	}
+	return values, nil
+}
+
+// quote quotes an identifier since the names of the arguments can
+// be reserved words (i.e. 'from' and 'to' in the Transfer event)
+func quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// toSnakeCase converts a name in camel case to snake case (i.e. 'tokenId' to 'token_id')
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for indx, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			b.WriteRune('_')
+			continue
+		}
+		if unicode.IsUpper(r) {
+			// add a separator on a lower to upper change or at the end of an acronym (i.e. 'NFTOwner')
+			if indx > 0 && (unicode.IsLower(runes[indx-1]) || (indx+1 < len(runes) && unicode.IsLower(runes[indx+1]) && unicode.IsUpper(runes[indx-1]))) {
+				b.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return strings.Trim(b.String(), "_")
+}
@@ -0,0 +1,293 @@
+package sqlsink
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/abi"
+	"github.com/Ethernal-Tech/ethgo/tracker"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a database/sql driver that records the statements
+// executed and fails the statements that contain failOn
+type recorder struct {
+	lock   sync.Mutex
+	stmts  []string
+	args   [][]driver.Value
+	failOn string
+}
+
+func (r *recorder) record(query string, args []driver.NamedValue) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.failOn != "" && strings.Contains(query, r.failOn) {
+		return fmt.Errorf("failed")
+	}
+	values := []driver.Value{}
+	for _, arg := range args {
+		values = append(values, arg.Value)
+	}
+	r.stmts = append(r.stmts, query)
+	r.args = append(r.args, values)
+	return nil
+}
+
+func (r *recorder) reset() {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	r.stmts, r.args = nil, nil
+}
+
+func (r *recorder) Connect(context.Context) (driver.Conn, error) {
+	return &recorderConn{r}, nil
+}
+
+func (r *recorder) Driver() driver.Driver {
+	return nil
+}
+
+type recorderConn struct {
+	r *recorder
+}
+
+func (c *recorderConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("prepare not supported")
+}
+
+func (c *recorderConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	if err := c.r.record(query, args); err != nil {
+		return nil, err
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (c *recorderConn) Close() error {
+	return nil
+}
+
+func (c *recorderConn) Begin() (driver.Tx, error) {
+	return &recorderTx{c.r}, c.r.record("BEGIN", nil)
+}
+
+type recorderTx struct {
+	r *recorder
+}
+
+func (t *recorderTx) Commit() error {
+	return t.r.record("COMMIT", nil)
+}
+
+func (t *recorderTx) Rollback() error {
+	return t.r.record("ROLLBACK", nil)
+}
+
+var testABI = abi.MustNewABI(`[
+	{"type": "event", "name": "Transfer", "inputs": [
+		{"name": "from", "type": "address", "indexed": true},
+		{"name": "to", "type": "address", "indexed": true},
+		{"name": "value", "type": "uint256"}
+	]},
+	{"type": "event", "name": "DataSet", "inputs": [
+		{"name": "key", "type": "string", "indexed": true},
+		{"name": "address", "type": "address"},
+		{"name": "small", "type": "uint8"},
+		{"name": "large", "type": "uint64"},
+		{"name": "list", "type": "int32[]"},
+		{"name": "payload", "type": "bytes"},
+		{"name": "flag", "type": "bool"}
+	]},
+	{"type": "event", "name": "Hidden", "anonymous": true, "inputs": []}
+]`)
+
+func newTestSink(t *testing.T, dialect Dialect) (*Sink, *recorder) {
+	t.Helper()
+
+	r := &recorder{}
+	s, err := NewSink(sql.OpenDB(r), dialect, testABI)
+	require.NoError(t, err)
+	return s, r
+}
+
+func transferLog(t *testing.T, from, to ethgo.Address, value int64, logIndex uint64) *ethgo.Log {
+	t.Helper()
+
+	evnt := testABI.Events["Transfer"]
+	data, err := abi.Encode(map[string]interface{}{"value": big.NewInt(value)}, abi.MustNewType("tuple(uint256 value)"))
+	require.NoError(t, err)
+
+	return &ethgo.Log{
+		BlockNumber:     10,
+		BlockHash:       ethgo.Hash{0x1},
+		TransactionHash: ethgo.Hash{0x2},
+		LogIndex:        logIndex,
+		Address:         ethgo.Address{0x3},
+		Topics:          []ethgo.Hash{evnt.ID(), ethgo.BytesToHash(from[:]), ethgo.BytesToHash(to[:])},
+		Data:            data,
+	}
+}
+
+func TestSink_Schema(t *testing.T) {
+	cases := map[Dialect][]string{
+		DialectPostgres: {
+			`"small" BIGINT`,
+			`"large" NUMERIC(78)`,
+			`"list" JSONB`,
+			`"payload" BYTEA`,
+			`"key" BYTEA`,
+			`"value" NUMERIC(78)`,
+		},
+		DialectSQLite: {
+			`"small" BIGINT`,
+			`"large" TEXT`,
+			`"list" TEXT`,
+			`"payload" BLOB`,
+			`"key" BLOB`,
+			`"value" TEXT`,
+		},
+	}
+
+	for dialect, columns := range cases {
+		t.Run(string(dialect), func(t *testing.T) {
+			s, r := newTestSink(t, dialect)
+
+			require.Equal(t, "event_transfer", s.Table("Transfer"))
+			require.Equal(t, "event_data_set", s.Table("DataSet"))
+			require.Empty(t, s.Table("Hidden"))
+
+			schema := strings.Join(r.stmts, "\n")
+			for _, column := range columns {
+				require.Contains(t, schema, column)
+			}
+
+			// the argument named as a metadata column is renamed
+			require.Contains(t, schema, `"arg_address" TEXT`)
+			require.Contains(t, schema, `PRIMARY KEY ("block_hash", "log_index")`)
+		})
+	}
+
+	_, err := NewSink(sql.OpenDB(&recorder{}), "mysql", testABI)
+	require.Error(t, err)
+}
+
+func TestSink_Handle(t *testing.T) {
+	s, r := newTestSink(t, DialectPostgres)
+	r.reset()
+
+	from, to := ethgo.Address{0x10}, ethgo.Address{0x20}
+	log := transferLog(t, from, to, 1000, 5)
+
+	// the erc721 transfer has the same signature with an indexed value
+	erc721Log := transferLog(t, from, to, 0, 6)
+	erc721Log.Topics = append(erc721Log.Topics, ethgo.Hash{0x1})
+
+	unknownLog := &ethgo.Log{Topics: []ethgo.Hash{{0x99}}}
+
+	require.NoError(t, s.Handle(&tracker.Event{
+		Type:  tracker.EventAdd,
+		Added: []*ethgo.Log{log, erc721Log, unknownLog},
+	}))
+
+	require.Len(t, r.stmts, 3)
+	require.Equal(t, "BEGIN", r.stmts[0])
+	require.Equal(t, `INSERT INTO "event_transfer" ("block_number", "block_hash", "tx_hash", "tx_index", "log_index", "address", "from", "to", "value") VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9) ON CONFLICT DO NOTHING`, r.stmts[1])
+	require.Equal(t, []driver.Value{
+		int64(10),
+		ethgo.Hash{0x1}.String(),
+		ethgo.Hash{0x2}.String(),
+		int64(0),
+		int64(5),
+		ethgo.Address{0x3}.String(),
+		from.String(),
+		to.String(),
+		"1000",
+	}, r.args[1])
+	require.Equal(t, "COMMIT", r.stmts[2])
+
+	// the removed logs are deleted
+	r.reset()
+	require.NoError(t, s.Handle(&tracker.Event{
+		Type:    tracker.EventDel,
+		Removed: []*ethgo.Log{log},
+	}))
+
+	require.Len(t, r.stmts, 3)
+	require.Equal(t, `DELETE FROM "event_transfer" WHERE "block_hash" = $1 AND "log_index" = $2`, r.stmts[1])
+	require.Equal(t, []driver.Value{ethgo.Hash{0x1}.String(), int64(5)}, r.args[1])
+}
+
+func TestSink_HandleRollback(t *testing.T) {
+	s, r := newTestSink(t, DialectSQLite)
+	r.reset()
+	r.failOn = "INSERT"
+
+	log := transferLog(t, ethgo.Address{0x10}, ethgo.Address{0x20}, 1000, 0)
+	require.Error(t, s.Handle(&tracker.Event{
+		Type:    tracker.EventAdd,
+		Removed: []*ethgo.Log{log},
+		Added:   []*ethgo.Log{log},
+	}))
+
+	// the delete is rolled back
+	require.Equal(t, []string{"BEGIN", `DELETE FROM "event_transfer" WHERE "block_hash" = ? AND "log_index" = ?`, "ROLLBACK"}, r.stmts)
+}
+
+func TestSink_DecodeValues(t *testing.T) {
+	s, r := newTestSink(t, DialectSQLite)
+	r.reset()
+
+	evnt := testABI.Events["DataSet"]
+	typ := abi.MustNewType("tuple(address address, uint8 small, uint64 large, int32[] list, bytes payload, bool flag)")
+	data, err := abi.Encode(map[string]interface{}{
+		"address": ethgo.Address{0x1},
+		"small":   uint8(7),
+		"large":   uint64(1 << 63),
+		"list":    []int32{-1, 2},
+		"payload": []byte{0xab},
+		"flag":    true,
+	}, typ)
+	require.NoError(t, err)
+
+	keyHash := ethgo.BytesToHash(ethgo.Keccak256([]byte("key")))
+	log := &ethgo.Log{
+		Topics: []ethgo.Hash{evnt.ID(), keyHash},
+		Data:   data,
+	}
+
+	eventCh := make(chan *tracker.Event, 1)
+	eventCh <- &tracker.Event{Added: []*ethgo.Log{log}}
+	close(eventCh)
+	require.NoError(t, s.Run(context.Background(), eventCh))
+
+	require.Equal(t, []driver.Value{
+		keyHash.Bytes(),
+		ethgo.Address{0x1}.String(),
+		int64(7),
+		"9223372036854775808",
+		`["-1","2"]`,
+		[]byte{0xab},
+		true,
+	}, r.args[1][len(metadataColumns):])
+}
+
+func TestToSnakeCase(t *testing.T) {
+	cases := map[string]string{
+		"Transfer":      "transfer",
+		"tokenId":       "token_id",
+		"NFTOwner":      "nft_owner",
+		"_owner":        "owner",
+		"OwnershipSet2": "ownership_set2",
+	}
+	for name, expected := range cases {
+		require.Equal(t, expected, toSnakeCase(name))
+	}
+}
@@ -0,0 +1,88 @@
+package sqlsink
+
+import (
+	"database/sql"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/abi"
+	"github.com/Ethernal-Tech/ethgo/tracker"
+	"github.com/stretchr/testify/require"
+
+	_ "modernc.org/sqlite"
+)
+
+func TestSink_SQLite(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "events.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	s, err := NewSink(db, DialectSQLite, testABI)
+	require.NoError(t, err)
+
+	// the tables are not created twice
+	_, err = NewSink(db, DialectSQLite, testABI)
+	require.NoError(t, err)
+
+	from, to := ethgo.Address{0x10}, ethgo.Address{0x20}
+	log0 := transferLog(t, from, to, 1000, 0)
+	log1 := transferLog(t, to, from, 500, 1)
+
+	added := &tracker.Event{Type: tracker.EventAdd, Added: []*ethgo.Log{log0, log1}}
+	require.NoError(t, s.Handle(added))
+
+	// the events are only stored once
+	require.NoError(t, s.Handle(added))
+
+	count := func() (num int) {
+		require.NoError(t, db.QueryRow(`SELECT COUNT(*) FROM "event_transfer"`).Scan(&num))
+		return
+	}
+	require.Equal(t, 2, count())
+
+	var sender, value string
+	var logIndex int64
+	require.NoError(t, db.QueryRow(`SELECT "from", "value", "log_index" FROM "event_transfer" WHERE "to" = ?`, from.String()).Scan(&sender, &value, &logIndex))
+	require.Equal(t, to.String(), sender)
+	require.Equal(t, "500", value)
+	require.Equal(t, int64(1), logIndex)
+
+	require.NoError(t, s.Handle(&tracker.Event{Type: tracker.EventDel, Removed: []*ethgo.Log{log1}}))
+	require.Equal(t, 1, count())
+}
+
+func TestSink_SQLiteUnnamedArgs(t *testing.T) {
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "events.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	fooABI := abi.MustNewABI(`[
+		{"type": "event", "name": "Foo", "inputs": [
+			{"name": "who", "type": "address", "indexed": true},
+			{"name": "", "type": "uint256"},
+			{"name": "", "type": "bool"}
+		]}
+	]`)
+	s, err := NewSink(db, DialectSQLite, fooABI)
+	require.NoError(t, err)
+
+	// the unnamed arguments are decoded by their position in the data
+	data, err := abi.Encode([]interface{}{big.NewInt(42), true}, abi.MustNewType("tuple(uint256, bool)"))
+	require.NoError(t, err)
+
+	who := ethgo.Address{0x10}
+	log := &ethgo.Log{
+		BlockNumber: 10,
+		Topics:      []ethgo.Hash{fooABI.Events["Foo"].ID(), ethgo.BytesToHash(who[:])},
+		Data:        data,
+	}
+	require.NoError(t, s.Handle(&tracker.Event{Type: tracker.EventAdd, Added: []*ethgo.Log{log}}))
+
+	var value string
+	var flag bool
+	require.NoError(t, db.QueryRow(`SELECT "arg1", "arg2" FROM "event_foo" WHERE "who" = ?`, who.String()).Scan(&value, &flag))
+	require.Equal(t, "42", value)
+	require.True(t, flag)
+}
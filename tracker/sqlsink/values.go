@@ -0,0 +1,131 @@
+package sqlsink
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strconv"
+)
+
+// value converts a decoded abi value to the value of the column
+func (k columnKind) value(v interface{}) (interface{}, error) {
+	switch k {
+	case columnBool:
+		b, ok := v.(bool)
+		if !ok {
+			return nil, fmt.Errorf("expected a bool but found %T", v)
+		}
+		return b, nil
+
+	case columnInt:
+		num, err := toBigInt(v)
+		if err != nil {
+			return nil, err
+		}
+		if !num.IsInt64() {
+			return nil, fmt.Errorf("%s does not fit in an int64", num)
+		}
+		return num.Int64(), nil
+
+	case columnNumeric:
+		num, err := toBigInt(v)
+		if err != nil {
+			return nil, err
+		}
+		return num.String(), nil
+
+	case columnText:
+		switch obj := v.(type) {
+		case string:
+			return obj, nil
+		case fmt.Stringer:
+			return obj.String(), nil
+		default:
+			return nil, fmt.Errorf("expected a text value but found %T", v)
+		}
+
+	case columnBytes:
+		return toBytes(v)
+
+	case columnJSON:
+		data, err := json.Marshal(toJSON(reflect.ValueOf(v)))
+		if err != nil {
+			return nil, err
+		}
+		return string(data), nil
+	}
+	return nil, fmt.Errorf("unknown column kind %d", k)
+}
+
+func toBigInt(v interface{}) (*big.Int, error) {
+	if num, ok := v.(*big.Int); ok {
+		return num, nil
+	}
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return big.NewInt(val.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return new(big.Int).SetUint64(val.Uint()), nil
+	}
+	return nil, fmt.Errorf("expected a number but found %T", v)
+}
+
+func toBytes(v interface{}) ([]byte, error) {
+	if buf, ok := v.([]byte); ok {
+		return buf, nil
+	}
+	val := reflect.ValueOf(v)
+	if val.Kind() != reflect.Array || val.Type().Elem().Kind() != reflect.Uint8 {
+		return nil, fmt.Errorf("expected bytes but found %T", v)
+	}
+	buf := make([]byte, val.Len())
+	reflect.Copy(reflect.ValueOf(buf), val)
+	return buf, nil
+}
+
+// toJSON converts the decoded arrays and tuples to a value that encodes to a readable
+// json. The numbers are encoded as strings since they do not fit in a json number.
+func toJSON(val reflect.Value) interface{} {
+	if !val.IsValid() {
+		return nil
+	}
+
+	// numbers, addresses and hashes
+	if stringer, ok := val.Interface().(fmt.Stringer); ok {
+		return stringer.String()
+	}
+
+	switch val.Kind() {
+	case reflect.Interface, reflect.Ptr:
+		return toJSON(val.Elem())
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(val.Int(), 10)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(val.Uint(), 10)
+
+	case reflect.Slice, reflect.Array:
+		if val.Type().Elem().Kind() == reflect.Uint8 {
+			buf, _ := toBytes(val.Interface())
+			return "0x" + hex.EncodeToString(buf)
+		}
+		res := make([]interface{}, val.Len())
+		for i := 0; i < val.Len(); i++ {
+			res[i] = toJSON(val.Index(i))
+		}
+		return res
+
+	case reflect.Map:
+		res := map[string]interface{}{}
+		iter := val.MapRange()
+		for iter.Next() {
+			res[fmt.Sprint(iter.Key().Interface())] = toJSON(iter.Value())
+		}
+		return res
+	}
+	return val.Interface()
+}
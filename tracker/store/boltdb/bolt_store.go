@@ -3,13 +3,18 @@ package trackerboltdb
 import (
 	"bytes"
 	"encoding/binary"
+	"math"
+	"sort"
 
 	"github.com/Ethernal-Tech/ethgo"
 	"github.com/Ethernal-Tech/ethgo/tracker/store"
 	bolt "go.etcd.io/bbolt"
 )
 
-var _ store.Store = (*BoltStore)(nil)
+var (
+	_ store.Store          = (*BoltStore)(nil)
+	_ store.QueryableEntry = (*Entry)(nil)
+)
 
 var (
 	dbLogs = []byte("logs")
@@ -112,13 +117,16 @@ func (b *BoltStore) GetEntry(hash string) (store.Entry, error) {
 	if _, err := txn.CreateBucketIfNotExists(bucketName); err != nil {
 		return nil, err
 	}
-	if err := txn.Commit(); err != nil {
-		return nil, err
-	}
 	e := &Entry{
 		conn:   b.conn,
 		bucket: bucketName,
 	}
+	if err := e.setupIndexes(txn); err != nil {
+		return nil, err
+	}
+	if err := txn.Commit(); err != nil {
+		return nil, err
+	}
 	return e, nil
 }
 
@@ -128,6 +136,79 @@ type Entry struct {
 	bucket []byte
 }
 
+// suffixes of the buckets of the secondary indexes of an entry
+var (
+	indexBlock   = []byte("/block")
+	indexAddress = []byte("/address")
+	indexTopic   = []byte("/topic")
+)
+
+func (e *Entry) indexBucket(index []byte) []byte {
+	return append(append([]byte{}, e.bucket...), index...)
+}
+
+// setupIndexes creates the buckets of the secondary indexes and
+// indexes the logs stored before the indexes existed
+func (e *Entry) setupIndexes(txn *bolt.Tx) error {
+	if txn.Bucket(e.indexBucket(indexBlock)) != nil {
+		return nil
+	}
+	for _, index := range [][]byte{indexBlock, indexAddress, indexTopic} {
+		if _, err := txn.CreateBucket(e.indexBucket(index)); err != nil {
+			return err
+		}
+	}
+
+	curs := txn.Bucket(e.bucket).Cursor()
+	for k, v := curs.First(); k != nil; k, v = curs.Next() {
+		var log ethgo.Log
+		if err := log.UnmarshalJSON(v); err != nil {
+			return err
+		}
+		if err := e.putIndexes(txn, k, &log); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// indexKeys returns the keys of the log in each of the secondary indexes
+func indexKeys(key []byte, log *ethgo.Log) map[string][][]byte {
+	topics := [][]byte{}
+	for pos, topic := range log.Topics {
+		topics = append(topics, concat([]byte{byte(pos)}, topic[:], key))
+	}
+	return map[string][][]byte{
+		string(indexBlock):   {concat(uint64ToBytes(log.BlockNumber), key)},
+		string(indexAddress): {concat(log.Address[:], key)},
+		string(indexTopic):   topics,
+	}
+}
+
+func (e *Entry) putIndexes(txn *bolt.Tx, key []byte, log *ethgo.Log) error {
+	for index, keys := range indexKeys(key, log) {
+		bucket := txn.Bucket(e.indexBucket([]byte(index)))
+		for _, k := range keys {
+			if err := bucket.Put(k, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (e *Entry) deleteIndexes(txn *bolt.Tx, key []byte, log *ethgo.Log) error {
+	for index, keys := range indexKeys(key, log) {
+		bucket := txn.Bucket(e.indexBucket([]byte(index)))
+		for _, k := range keys {
+			if err := bucket.Delete(k); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // LastIndex implements the store interface
 func (e *Entry) LastIndex() (uint64, error) {
 	tx, err := e.conn.Begin(false)
@@ -172,6 +253,12 @@ func (e *Entry) StoreLogs(logs []*ethgo.Log) error {
 		if err := bucket.Put(key, val); err != nil {
 			return err
 		}
+		if err := e.putIndexes(tx, key, log); err != nil {
+			return err
+		}
+		if store.LogStoredHook != nil {
+			store.LogStoredHook(logIndx)
+		}
 	}
 	return tx.Commit()
 }
@@ -187,7 +274,14 @@ func (e *Entry) RemoveLogs(indx uint64) error {
 	defer tx.Rollback()
 
 	curs := tx.Bucket(e.bucket).Cursor()
-	for k, _ := curs.Seek(indxKey); k != nil; k, _ = curs.Next() {
+	for k, v := curs.Seek(indxKey); k != nil; k, v = curs.Next() {
+		var log ethgo.Log
+		if err := log.UnmarshalJSON(v); err != nil {
+			return err
+		}
+		if err := e.deleteIndexes(tx, k, &log); err != nil {
+			return err
+		}
 		if err := curs.Delete(); err != nil {
 			return err
 		}
@@ -213,6 +307,136 @@ func (e *Entry) GetLog(indx uint64, log *ethgo.Log) error {
 	return nil
 }
 
+// QueryLogs implements the store.QueryableEntry interface
+func (e *Entry) QueryLogs(q *store.LogQuery) (*store.LogPage, error) {
+	page := &store.LogPage{}
+	err := e.query(q, func(indx uint64, log *ethgo.Log) bool {
+		if q.Limit != 0 && uint64(len(page.Logs)) == q.Limit {
+			page.Next = store.EncodeLogCursor(indx)
+			return false
+		}
+		page.Logs = append(page.Logs, log)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// CountLogs implements the store.QueryableEntry interface
+func (e *Entry) CountLogs(q *store.LogQuery) (uint64, error) {
+	all := *q
+	all.Cursor = ""
+
+	count := uint64(0)
+	err := e.query(&all, func(uint64, *ethgo.Log) bool {
+		count++
+		return true
+	})
+	return count, err
+}
+
+// query calls handler with the logs that match the query until it returns false
+func (e *Entry) query(q *store.LogQuery, handler func(indx uint64, log *ethgo.Log) bool) error {
+	start, err := store.DecodeLogCursor(q.Cursor)
+	if err != nil {
+		return err
+	}
+
+	txn, err := e.conn.Begin(false)
+	if err != nil {
+		return err
+	}
+	defer txn.Rollback()
+
+	// the logs are stored in block order, the range of blocks is a range of indexes
+	end := uint64(math.MaxUint64)
+	blocks := txn.Bucket(e.indexBucket(indexBlock)).Cursor()
+	k, _ := blocks.Seek(uint64ToBytes(q.FromBlock))
+	if k == nil {
+		return nil
+	}
+	start = max(start, bytesToUint64(k[8:]))
+	if q.ToBlock != 0 && q.ToBlock != math.MaxUint64 {
+		if k, _ := blocks.Seek(uint64ToBytes(q.ToBlock + 1)); k != nil {
+			end = bytesToUint64(k[8:])
+		}
+	}
+
+	// use the index of the addresses or of the first position with topics
+	var prefixes [][]byte
+	var index []byte
+	if len(q.Address) != 0 {
+		index = indexAddress
+		for _, addr := range q.Address {
+			prefixes = append(prefixes, concat(addr[:]))
+		}
+	} else {
+		for pos, topics := range q.Topics {
+			if len(topics) == 0 {
+				continue
+			}
+			index = indexTopic
+			for _, topic := range topics {
+				prefixes = append(prefixes, concat([]byte{byte(pos)}, topic[:]))
+			}
+			break
+		}
+	}
+
+	logs := txn.Bucket(e.bucket)
+	handle := func(key, val []byte) (bool, error) {
+		var log ethgo.Log
+		if err := log.UnmarshalJSON(val); err != nil {
+			return false, err
+		}
+		if !q.Match(&log) {
+			return true, nil
+		}
+		return handler(bytesToUint64(key), &log), nil
+	}
+
+	if index == nil {
+		curs := logs.Cursor()
+		for k, v := curs.Seek(uint64ToBytes(start)); k != nil && bytesToUint64(k) < end; k, v = curs.Next() {
+			if next, err := handle(k, v); err != nil || !next {
+				return err
+			}
+		}
+		return nil
+	}
+
+	candidates := []uint64{}
+	curs := txn.Bucket(e.indexBucket(index)).Cursor()
+	for _, prefix := range prefixes {
+		for k, _ := curs.Seek(concat(prefix, uint64ToBytes(start))); k != nil && bytes.HasPrefix(k, prefix); k, _ = curs.Next() {
+			indx := bytesToUint64(k[len(prefix):])
+			if indx >= end {
+				break
+			}
+			candidates = append(candidates, indx)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i] < candidates[j] })
+
+	for _, indx := range candidates {
+		key := uint64ToBytes(indx)
+		if next, err := handle(key, logs.Get(key)); err != nil || !next {
+			return err
+		}
+	}
+	return nil
+}
+
+func concat(items ...[]byte) []byte {
+	res := []byte{}
+	for _, item := range items {
+		res = append(res, item...)
+	}
+	return res
+}
+
 func bytesToUint64(b []byte) uint64 {
 	return binary.BigEndian.Uint64(b)
 }
@@ -1,28 +1,23 @@
 package trackerboltdb
 
 import (
-	"io/ioutil"
-	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/Ethernal-Tech/ethgo"
 	"github.com/Ethernal-Tech/ethgo/tracker/store"
+	bolt "go.etcd.io/bbolt"
 )
 
 func setupDB(t *testing.T) (store.Store, func()) {
-	dir, err := ioutil.TempDir("/tmp", "boltdb-test")
-	if err != nil {
-		t.Fatal(err)
-	}
-
-	path := filepath.Join(dir, "test.db")
+	path := filepath.Join(store.TempDir(t), "test.db")
 	store, err := New(path)
 	if err != nil {
 		t.Fatal(err)
 	}
 
 	close := func() {
-		if err := os.RemoveAll(dir); err != nil {
+		if err := store.Close(); err != nil {
 			t.Fatal(err)
 		}
 	}
@@ -32,3 +27,54 @@ func setupDB(t *testing.T) (store.Store, func()) {
 func TestBoltDBStore(t *testing.T) {
 	store.TestStore(t, setupDB)
 }
+
+func TestBoltDBStore_IndexExistingLogs(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.db")
+
+	// store the logs without the secondary indexes
+	db, err := bolt.Open(path, 0600, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucket(append([]byte("logs"), "1"...))
+		if err != nil {
+			return err
+		}
+		for i := uint64(0); i < 5; i++ {
+			log := &ethgo.Log{BlockNumber: i, Address: ethgo.Address{byte(i % 2)}}
+			val, err := log.MarshalJSON()
+			if err != nil {
+				return err
+			}
+			if err := bucket.Put(uint64ToBytes(i), val); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := New(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	entry, err := s.GetEntry("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	num, err := store.CountLogs(entry, &store.LogQuery{FromBlock: 1, Address: []ethgo.Address{{0x1}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if num != 2 {
+		t.Fatalf("expected 2 logs but found %d", num)
+	}
+}
@@ -0,0 +1,54 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Ethernal-Tech/ethgo"
+)
+
+// CursorType is the type of a position of a filter in the chain
+type CursorType string
+
+const (
+	// CursorHead is the head of the chain the last time the filter was synced
+	CursorHead CursorType = "head"
+
+	// CursorFinal is the last final block (it cannot be reverted) processed by the filter
+	CursorFinal CursorType = "final"
+)
+
+// Cursor is a position of a filter in the chain
+type Cursor struct {
+	Number uint64     `json:"number"`
+	Hash   ethgo.Hash `json:"hash"`
+}
+
+func cursorKey(entry string, typ CursorType) string {
+	return fmt.Sprintf("cursor_%s_%s", typ, entry)
+}
+
+// GetCursor returns the cursor of the entry or nil if it is not set
+func GetCursor(s Store, entry string, typ CursorType) (*Cursor, error) {
+	data, err := s.Get(cursorKey(entry, typ))
+	if err != nil {
+		return nil, err
+	}
+	if data == "" {
+		return nil, nil
+	}
+	cursor := &Cursor{}
+	if err := json.Unmarshal([]byte(data), cursor); err != nil {
+		return nil, fmt.Errorf("failed to decode %s cursor: %v", typ, err)
+	}
+	return cursor, nil
+}
+
+// SetCursor sets the cursor of the entry
+func SetCursor(s Store, entry string, typ CursorType, cursor *Cursor) error {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return err
+	}
+	return s.Set(cursorKey(entry, typ), string(data))
+}
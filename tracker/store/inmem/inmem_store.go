@@ -1,6 +1,7 @@
 package inmem
 
 import (
+	"sort"
 	"strings"
 	"sync"
 
@@ -8,7 +9,10 @@ import (
 	"github.com/Ethernal-Tech/ethgo/tracker/store"
 )
 
-var _ store.Store = (*InmemStore)(nil)
+var (
+	_ store.Store          = (*InmemStore)(nil)
+	_ store.QueryableEntry = (*Entry)(nil)
+)
 
 // InmemStore implements the Store interface.
 type InmemStore struct {
@@ -68,7 +72,9 @@ func (i *InmemStore) GetEntry(hash string) (store.Entry, error) {
 		return e, nil
 	}
 	e = &Entry{
-		logs: []*ethgo.Log{},
+		logs:      []*ethgo.Log{},
+		byAddress: map[ethgo.Address][]uint64{},
+		byTopic:   map[topicKey][]uint64{},
 	}
 	i.entries[hash] = e
 	return e, nil
@@ -78,6 +84,16 @@ func (i *InmemStore) GetEntry(hash string) (store.Entry, error) {
 type Entry struct {
 	l    sync.RWMutex
 	logs []*ethgo.Log
+
+	// secondary indexes with the (sorted) indexes of the logs by address and topic
+	byAddress map[ethgo.Address][]uint64
+	byTopic   map[topicKey][]uint64
+}
+
+// topicKey is a topic at a position of the log
+type topicKey struct {
+	pos   int
+	topic ethgo.Hash
 }
 
 // LastIndex implements the store interface
@@ -89,14 +105,23 @@ func (e *Entry) LastIndex() (uint64, error) {
 
 // Logs returns the logs of the inmemory store
 func (e *Entry) Logs() []*ethgo.Log {
-	return e.logs
+	e.l.RLock()
+	defer e.l.RUnlock()
+	return append([]*ethgo.Log{}, e.logs...)
 }
 
 // StoreLogs implements the store interface
 func (e *Entry) StoreLogs(logs []*ethgo.Log) error {
 	e.l.Lock()
 	defer e.l.Unlock()
+
 	for _, log := range logs {
+		indx := uint64(len(e.logs))
+		e.byAddress[log.Address] = append(e.byAddress[log.Address], indx)
+		for pos, topic := range log.Topics {
+			key := topicKey{pos, topic}
+			e.byTopic[key] = append(e.byTopic[key], indx)
+		}
 		e.logs = append(e.logs, log)
 	}
 	return nil
@@ -106,12 +131,118 @@ func (e *Entry) StoreLogs(logs []*ethgo.Log) error {
 func (e *Entry) RemoveLogs(indx uint64) error {
 	e.l.Lock()
 	defer e.l.Unlock()
+
+	for _, log := range e.logs[indx:] {
+		e.byAddress[log.Address] = truncateIndexes(e.byAddress[log.Address], indx)
+		for pos, topic := range log.Topics {
+			key := topicKey{pos, topic}
+			e.byTopic[key] = truncateIndexes(e.byTopic[key], indx)
+		}
+	}
 	e.logs = e.logs[:indx]
 	return nil
 }
 
+// truncateIndexes removes the indexes starting at indx
+func truncateIndexes(list []uint64, indx uint64) []uint64 {
+	return list[:sort.Search(len(list), func(i int) bool { return list[i] >= indx })]
+}
+
 // GetLog implements the store interface
 func (e *Entry) GetLog(indx uint64, log *ethgo.Log) error {
 	*log = *e.logs[indx]
 	return nil
 }
+
+// QueryLogs implements the store.QueryableEntry interface
+func (e *Entry) QueryLogs(q *store.LogQuery) (*store.LogPage, error) {
+	page := &store.LogPage{}
+	err := e.query(q, func(indx uint64, log *ethgo.Log) bool {
+		if q.Limit != 0 && uint64(len(page.Logs)) == q.Limit {
+			page.Next = store.EncodeLogCursor(indx)
+			return false
+		}
+		page.Logs = append(page.Logs, log)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return page, nil
+}
+
+// CountLogs implements the store.QueryableEntry interface
+func (e *Entry) CountLogs(q *store.LogQuery) (uint64, error) {
+	all := *q
+	all.Cursor = ""
+
+	count := uint64(0)
+	err := e.query(&all, func(uint64, *ethgo.Log) bool {
+		count++
+		return true
+	})
+	return count, err
+}
+
+// query calls handler with the logs that match the query until it returns false
+func (e *Entry) query(q *store.LogQuery, handler func(indx uint64, log *ethgo.Log) bool) error {
+	start, err := store.DecodeLogCursor(q.Cursor)
+	if err != nil {
+		return err
+	}
+
+	e.l.RLock()
+	defer e.l.RUnlock()
+
+	// the logs are stored in block order
+	end := uint64(len(e.logs))
+	start = max(start, uint64(sort.Search(len(e.logs), func(i int) bool {
+		return e.logs[i].BlockNumber >= q.FromBlock
+	})))
+	if q.ToBlock != 0 {
+		end = uint64(sort.Search(len(e.logs), func(i int) bool {
+			return e.logs[i].BlockNumber > q.ToBlock
+		}))
+	}
+
+	// use the index of the addresses or of the first position with topics
+	var candidates []uint64
+	indexed := false
+	if len(q.Address) != 0 {
+		indexed = true
+		for _, addr := range q.Address {
+			candidates = append(candidates, e.byAddress[addr]...)
+		}
+	} else {
+		for pos, topics := range q.Topics {
+			if len(topics) == 0 {
+				continue
+			}
+			indexed = true
+			for _, topic := range topics {
+				candidates = append(candidates, e.byTopic[topicKey{pos, topic}]...)
+			}
+			break
+		}
+	}
+
+	if !indexed {
+		for indx := start; indx < end; indx++ {
+			if log := e.logs[indx]; q.Match(log) && !handler(indx, log) {
+				return nil
+			}
+		}
+		return nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i] < candidates[j] })
+	for _, indx := range candidates {
+		if indx < start || indx >= end {
+			continue
+		}
+		if log := e.logs[indx]; q.Match(log) && !handler(indx, log) {
+			return nil
+		}
+	}
+	return nil
+}
@@ -7,7 +7,8 @@ import (
 )
 
 func TestInMemoryStore(t *testing.T) {
+	// the logs are not persisted, so there is no partial batch after a crash
 	store.TestStore(t, func(t *testing.T) (store.Store, func()) {
 		return NewInmemStore(), func() {}
-	})
+	}, "StoreLogsInterrupted")
 }
@@ -0,0 +1,188 @@
+package trackerpebble
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sync"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/tracker/store"
+	"github.com/cockroachdb/pebble"
+)
+
+var _ store.Store = (*PebbleStore)(nil)
+
+var (
+	dbLogs = []byte("logs/")
+	dbConf = []byte("conf/")
+)
+
+// PebbleStore is a tracker store implementation that uses a Pebble (LSM tree)
+// database as a backend. It is suited for filters with a high volume of logs.
+type PebbleStore struct {
+	db *pebble.DB
+
+	// lock serializes the writes of the logs since the
+	// index of the logs depends on the last stored log
+	lock sync.Mutex
+}
+
+// New creates a new pebble store in the given directory
+func New(path string) (*PebbleStore, error) {
+	db, err := pebble.Open(path, &pebble.Options{})
+	if err != nil {
+		return nil, err
+	}
+	return &PebbleStore{db: db}, nil
+}
+
+// Close implements the store interface
+func (p *PebbleStore) Close() error {
+	return p.db.Close()
+}
+
+// confKey returns the key of a config value. The prefix is copied so
+// that the keys do not share the backing array of dbConf.
+func confKey(k string) []byte {
+	return append(append([]byte{}, dbConf...), k...)
+}
+
+// Get implements the store interface
+func (p *PebbleStore) Get(k string) (string, error) {
+	val, closer, err := p.db.Get(confKey(k))
+	if err != nil {
+		if err == pebble.ErrNotFound {
+			return "", nil
+		}
+		return "", err
+	}
+	defer closer.Close()
+
+	return string(val), nil
+}
+
+// ListPrefix implements the store interface
+func (p *PebbleStore) ListPrefix(prefix string) ([]string, error) {
+	lower := confKey(prefix)
+	iter, err := p.db.NewIter(&pebble.IterOptions{
+		LowerBound: lower,
+		UpperBound: prefixEnd(lower),
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	res := []string{}
+	for iter.First(); iter.Valid(); iter.Next() {
+		res = append(res, string(iter.Value()))
+	}
+	return res, iter.Error()
+}
+
+// Set implements the store interface
+func (p *PebbleStore) Set(k, v string) error {
+	return p.db.Set(confKey(k), []byte(v), pebble.Sync)
+}
+
+// GetEntry implements the store interface
+func (p *PebbleStore) GetEntry(hash string) (store.Entry, error) {
+	prefix := append(append([]byte{}, dbLogs...), hash...)
+	prefix = append(prefix, '/')
+
+	e := &Entry{
+		store:  p,
+		prefix: prefix,
+	}
+	return e, nil
+}
+
+// Entry is an store.Entry implementation
+type Entry struct {
+	store  *PebbleStore
+	prefix []byte
+}
+
+func (e *Entry) key(indx uint64) []byte {
+	key := make([]byte, len(e.prefix)+8)
+	copy(key, e.prefix)
+	binary.BigEndian.PutUint64(key[len(e.prefix):], indx)
+	return key
+}
+
+// LastIndex implements the store interface
+func (e *Entry) LastIndex() (uint64, error) {
+	iter, err := e.store.db.NewIter(&pebble.IterOptions{
+		LowerBound: e.prefix,
+		UpperBound: prefixEnd(e.prefix),
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer iter.Close()
+
+	if !iter.Last() {
+		return 0, iter.Error()
+	}
+	return binary.BigEndian.Uint64(iter.Key()[len(e.prefix):]) + 1, nil
+}
+
+// StoreLogs implements the store interface. The logs are written in a single
+// batch and either all or none of them are stored.
+func (e *Entry) StoreLogs(logs []*ethgo.Log) error {
+	e.store.lock.Lock()
+	defer e.store.lock.Unlock()
+
+	indx, err := e.LastIndex()
+	if err != nil {
+		return err
+	}
+
+	batch := e.store.db.NewBatch()
+	defer batch.Close()
+
+	for logIndx, log := range logs {
+		val, err := log.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		if err := batch.Set(e.key(indx+uint64(logIndx)), val, nil); err != nil {
+			return err
+		}
+		if store.LogStoredHook != nil {
+			store.LogStoredHook(logIndx)
+		}
+	}
+	return batch.Commit(pebble.Sync)
+}
+
+// RemoveLogs implements the store interface
+func (e *Entry) RemoveLogs(indx uint64) error {
+	e.store.lock.Lock()
+	defer e.store.lock.Unlock()
+
+	return e.store.db.DeleteRange(e.key(indx), prefixEnd(e.prefix), pebble.Sync)
+}
+
+// GetLog implements the store interface
+func (e *Entry) GetLog(indx uint64, log *ethgo.Log) error {
+	val, closer, err := e.store.db.Get(e.key(indx))
+	if err != nil {
+		return err
+	}
+	defer closer.Close()
+
+	return log.UnmarshalJSON(bytes.Clone(val))
+}
+
+// prefixEnd returns the first key after all the keys with the prefix
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte{}, prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		end[i]++
+		if end[i] != 0 {
+			return end[:i+1]
+		}
+	}
+	return nil
+}
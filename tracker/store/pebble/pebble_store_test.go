@@ -0,0 +1,25 @@
+package trackerpebble
+
+import (
+	"testing"
+
+	"github.com/Ethernal-Tech/ethgo/tracker/store"
+)
+
+func setupDB(t *testing.T) (store.Store, func()) {
+	store, err := New(store.TempDir(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	close := func() {
+		if err := store.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store, close
+}
+
+func TestPebbleStore(t *testing.T) {
+	store.TestStore(t, setupDB)
+}
@@ -14,7 +14,10 @@ import (
 	_ "github.com/lib/pq"
 )
 
-var _ store.Store = (*PostgreSQLStore)(nil)
+var (
+	_ store.Store          = (*PostgreSQLStore)(nil)
+	_ store.QueryableEntry = (*Entry)(nil)
+)
 
 // PostgreSQLStore is a tracker store implementation that uses PostgreSQL as a backend.
 type PostgreSQLStore struct {
@@ -81,6 +84,11 @@ func (p *PostgreSQLStore) GetEntry(hash string) (store.Entry, error) {
 	if _, err := p.db.Exec(logSQLSchema(tableName)); err != nil {
 		return nil, err
 	}
+	for _, stmt := range logSQLIndexes(tableName, hash) {
+		if _, err := p.db.Exec(stmt); err != nil {
+			return nil, err
+		}
+	}
 	e := &Entry{
 		table: tableName,
 		db:    p.db,
@@ -142,6 +150,9 @@ func (e *Entry) StoreLogs(logs []*ethgo.Log) error {
 		if _, err := tx.NamedExec(query, obj); err != nil {
 			return err
 		}
+		if store.LogStoredHook != nil {
+			store.LogStoredHook(indx)
+		}
 	}
 	if err := tx.Commit(); err != nil {
 		return err
@@ -163,7 +174,97 @@ func (e *Entry) GetLog(indx uint64, log *ethgo.Log) error {
 	if err := e.db.Get(&obj, "SELECT * FROM "+e.table+" WHERE indx=$1", indx); err != nil {
 		return err
 	}
+	return obj.decode(log)
+}
+
+// QueryLogs implements the store.QueryableEntry interface
+func (e *Entry) QueryLogs(q *store.LogQuery) (*store.LogPage, error) {
+	start, err := store.DecodeLogCursor(q.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := queryConditions(q, start)
+	query := "SELECT * FROM " + e.table + " WHERE " + where + " ORDER BY indx"
+	if q.Limit != 0 {
+		// query one more log to know if there is a next page
+		query += fmt.Sprintf(" LIMIT %d", q.Limit+1)
+	}
 
+	objs := []*logObj{}
+	if err := e.db.Select(&objs, query, args...); err != nil {
+		return nil, err
+	}
+
+	page := &store.LogPage{}
+	for _, obj := range objs {
+		if q.Limit != 0 && uint64(len(page.Logs)) == q.Limit {
+			page.Next = store.EncodeLogCursor(obj.Index)
+			break
+		}
+		log := &ethgo.Log{}
+		if err := obj.decode(log); err != nil {
+			return nil, err
+		}
+		page.Logs = append(page.Logs, log)
+	}
+	return page, nil
+}
+
+// CountLogs implements the store.QueryableEntry interface
+func (e *Entry) CountLogs(q *store.LogQuery) (uint64, error) {
+	where, args := queryConditions(q, 0)
+
+	var count uint64
+	if err := e.db.Get(&count, "SELECT COUNT(*) FROM "+e.table+" WHERE "+where, args...); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// queryConditions returns the conditions of the query starting at the log start
+func queryConditions(q *store.LogQuery, start uint64) (string, []interface{}) {
+	conds := []string{}
+	args := []interface{}{}
+
+	arg := func(v interface{}) string {
+		args = append(args, v)
+		return fmt.Sprintf("$%d", len(args))
+	}
+	in := func(column string, values []string) string {
+		placeholders := []string{}
+		for _, v := range values {
+			placeholders = append(placeholders, arg(v))
+		}
+		return column + " IN (" + strings.Join(placeholders, ", ") + ")"
+	}
+
+	conds = append(conds, "indx >= "+arg(start))
+	conds = append(conds, "block_num >= "+arg(q.FromBlock))
+	if q.ToBlock != 0 {
+		conds = append(conds, "block_num <= "+arg(q.ToBlock))
+	}
+	if len(q.Address) != 0 {
+		addrs := []string{}
+		for _, addr := range q.Address {
+			addrs = append(addrs, addr.String())
+		}
+		conds = append(conds, in("address", addrs))
+	}
+	for pos, topics := range q.Topics {
+		if len(topics) == 0 {
+			continue
+		}
+		values := []string{}
+		for _, topic := range topics {
+			values = append(values, topic.String())
+		}
+		conds = append(conds, in(fmt.Sprintf("split_part(topics, ',', %d)", pos+1), values))
+	}
+	return strings.Join(conds, " AND "), args
+}
+
+func (obj *logObj) decode(log *ethgo.Log) error {
 	log.TransactionIndex = obj.TxIndex
 	if err := log.TransactionHash.UnmarshalText([]byte(obj.TxHash)); err != nil {
 		return err
@@ -237,3 +338,18 @@ func logSQLSchema(name string) string {
 	);
 	`
 }
+
+// logSQLIndexes returns the secondary indexes of the logs table. The name of the index
+// starts with the column since the identifiers are truncated at 63 characters.
+func logSQLIndexes(table, hash string) []string {
+	indexes := []string{
+		"CREATE INDEX IF NOT EXISTS indx_" + hash + " ON " + table + " (indx)",
+		"CREATE INDEX IF NOT EXISTS block_" + hash + " ON " + table + " (block_num)",
+		"CREATE INDEX IF NOT EXISTS address_" + hash + " ON " + table + " (address, indx)",
+	}
+	// index the most used topic positions
+	for pos := 1; pos <= 4; pos++ {
+		indexes = append(indexes, fmt.Sprintf("CREATE INDEX IF NOT EXISTS topic%d_%s ON %s (split_part(topics, ',', %d), indx)", pos, hash, table, pos))
+	}
+	return indexes
+}
@@ -3,13 +3,39 @@ package trackerpostgresql
 import (
 	"database/sql"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
+	"github.com/Ethernal-Tech/ethgo"
 	"github.com/Ethernal-Tech/ethgo/tracker/store"
 	"github.com/ory/dockertest"
 )
 
 func setupDB(t *testing.T) (store.Store, func()) {
+	// the endpoint of the database is kept in the directory of the test,
+	// so the setup reopens the database of the test
+	path := filepath.Join(store.TempDir(t), "endpoint")
+
+	endpoint, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		endpoint = []byte(startDB(t))
+		err = os.WriteFile(path, endpoint, 0600)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := NewPostgreSQLStore(string(endpoint))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return store, func() {
+		store.Close()
+	}
+}
+
+func startDB(t *testing.T) string {
 	pool, err := dockertest.NewPool("")
 	if err != nil {
 		t.Fatalf("Could not connect to docker: %s", err)
@@ -33,19 +59,32 @@ func setupDB(t *testing.T) (store.Store, func()) {
 		t.Fatal(err)
 	}
 
-	cleanup := func() {
+	t.Cleanup(func() {
 		if err := pool.Purge(resource); err != nil {
 			t.Fatalf("Could not purge resource: %s", err)
 		}
-	}
-
-	store, err := NewPostgreSQLStore(endpoint)
-	if err != nil {
-		t.Fatal(err)
-	}
-	return store, cleanup
+	})
+	return endpoint
 }
 
 func TestPostgreSQLStore(t *testing.T) {
 	store.TestStore(t, setupDB)
 }
+
+func TestQueryConditions(t *testing.T) {
+	q := &store.LogQuery{
+		FromBlock: 10,
+		ToBlock:   20,
+		Address:   []ethgo.Address{{0x1}},
+		Topics:    [][]ethgo.Hash{nil, {{0x2}, {0x3}}},
+	}
+	where, args := queryConditions(q, 5)
+
+	expected := "indx >= $1 AND block_num >= $2 AND block_num <= $3 AND address IN ($4) AND split_part(topics, ',', 2) IN ($5, $6)"
+	if where != expected {
+		t.Fatalf("bad conditions: %s", where)
+	}
+	if len(args) != 6 || args[0] != uint64(5) || args[3] != (ethgo.Address{0x1}).String() {
+		t.Fatalf("bad args: %v", args)
+	}
+}
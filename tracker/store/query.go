@@ -0,0 +1,162 @@
+package store
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/Ethernal-Tech/ethgo"
+)
+
+// LogQuery is a query of the logs stored in an entry
+type LogQuery struct {
+	// FromBlock is the first block of the range
+	FromBlock uint64
+
+	// ToBlock is the last block of the range. If zero, the range has no upper limit.
+	ToBlock uint64
+
+	// Address matches the logs emitted by any of the addresses. If empty, it matches all the logs.
+	Address []ethgo.Address
+
+	// Topics matches the logs with any of the topics at each position.
+	// An empty position matches any topic.
+	Topics [][]ethgo.Hash
+
+	// Limit is the maximum number of logs returned. If zero, all the logs are returned.
+	Limit uint64
+
+	// Cursor is the position to continue the query from (the Next cursor of the previous page)
+	Cursor string
+}
+
+// LogPage is a page of the logs of a query
+type LogPage struct {
+	Logs []*ethgo.Log
+
+	// Next is the cursor of the next page. It is empty if there are no more logs.
+	Next string
+}
+
+// QueryableEntry is an entry that can query the stored logs
+type QueryableEntry interface {
+	Entry
+
+	// QueryLogs returns the logs that match the query in the order they were stored
+	QueryLogs(q *LogQuery) (*LogPage, error)
+
+	// CountLogs returns the number of logs that match the query. The limit and the cursor are not used.
+	CountLogs(q *LogQuery) (uint64, error)
+}
+
+// QueryLogs queries the logs of the entry. If the entry does not implement
+// QueryableEntry, the logs are scanned one by one.
+func QueryLogs(e Entry, q *LogQuery) (*LogPage, error) {
+	if qe, ok := e.(QueryableEntry); ok {
+		return qe.QueryLogs(q)
+	}
+
+	start, err := DecodeLogCursor(q.Cursor)
+	if err != nil {
+		return nil, err
+	}
+	last, err := e.LastIndex()
+	if err != nil {
+		return nil, err
+	}
+
+	page := &LogPage{}
+	for indx := start; indx < last; indx++ {
+		log := &ethgo.Log{}
+		if err := e.GetLog(indx, log); err != nil {
+			return nil, err
+		}
+		if q.ToBlock != 0 && log.BlockNumber > q.ToBlock {
+			// the logs are stored in block order
+			break
+		}
+		if !q.Match(log) {
+			continue
+		}
+		if q.Limit != 0 && uint64(len(page.Logs)) == q.Limit {
+			page.Next = EncodeLogCursor(indx)
+			break
+		}
+		page.Logs = append(page.Logs, log)
+	}
+	return page, nil
+}
+
+// CountLogs counts the logs of the entry that match the query
+func CountLogs(e Entry, q *LogQuery) (uint64, error) {
+	if qe, ok := e.(QueryableEntry); ok {
+		return qe.CountLogs(q)
+	}
+
+	all := *q
+	all.Limit = 0
+	all.Cursor = ""
+
+	page, err := QueryLogs(e, &all)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(len(page.Logs)), nil
+}
+
+// Match returns true if the log matches the block range, the addresses and the topics of the query
+func (q *LogQuery) Match(log *ethgo.Log) bool {
+	if log.BlockNumber < q.FromBlock {
+		return false
+	}
+	if q.ToBlock != 0 && log.BlockNumber > q.ToBlock {
+		return false
+	}
+	if len(q.Address) != 0 && !containsAddress(q.Address, log.Address) {
+		return false
+	}
+	for indx, topics := range q.Topics {
+		if len(topics) == 0 {
+			continue
+		}
+		if indx >= len(log.Topics) || !containsHash(topics, log.Topics[indx]) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsAddress(list []ethgo.Address, addr ethgo.Address) bool {
+	for _, a := range list {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+func containsHash(list []ethgo.Hash, hash ethgo.Hash) bool {
+	for _, h := range list {
+		if h == hash {
+			return true
+		}
+	}
+	return false
+}
+
+// EncodeLogCursor encodes the index of the next log of a query as a cursor
+func EncodeLogCursor(indx uint64) string {
+	return strconv.FormatUint(indx, 36)
+}
+
+// DecodeLogCursor decodes the index of the next log of a query from a cursor.
+// An empty cursor starts at the first log.
+func DecodeLogCursor(cursor string) (uint64, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	indx, err := strconv.ParseUint(cursor, 36, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cursor '%s'", cursor)
+	}
+	return indx, nil
+}
@@ -0,0 +1,188 @@
+package trackersqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/tracker/store"
+
+	// Enable the pure-Go sqlite driver
+	_ "modernc.org/sqlite"
+)
+
+var _ store.Store = (*SQLiteStore)(nil)
+
+// SQLiteStore is a tracker store implementation that uses an embedded SQLite database as a backend.
+type SQLiteStore struct {
+	db *sql.DB
+}
+
+// New creates a new SQLite store in the given path
+func New(path string) (*SQLiteStore, error) {
+	// the write ahead log allows to read while the logs are stored
+	dsn := fmt.Sprintf("file:%s?_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_pragma=busy_timeout(5000)", path)
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	// sqlite only allows one writer at a time
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.Exec(kvSQLSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &SQLiteStore{db: db}, nil
+}
+
+// Close implements the store interface
+func (s *SQLiteStore) Close() error {
+	return s.db.Close()
+}
+
+// Get implements the store interface
+func (s *SQLiteStore) Get(k string) (string, error) {
+	var out string
+	if err := s.db.QueryRow("SELECT val FROM kv WHERE key = ?", k).Scan(&out); err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", err
+	}
+	return out, nil
+}
+
+// ListPrefix implements the store interface
+func (s *SQLiteStore) ListPrefix(prefix string) ([]string, error) {
+	rows, err := s.db.Query("SELECT val FROM kv WHERE substr(key, 1, ?) = ? ORDER BY key", len(prefix), prefix)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := []string{}
+	for rows.Next() {
+		var val string
+		if err := rows.Scan(&val); err != nil {
+			return nil, err
+		}
+		res = append(res, val)
+	}
+	return res, rows.Err()
+}
+
+// Set implements the store interface
+func (s *SQLiteStore) Set(k, v string) error {
+	if _, err := s.db.Exec("INSERT INTO kv (key, val) VALUES (?, ?) ON CONFLICT (key) DO UPDATE SET val = excluded.val", k, v); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetEntry implements the store interface
+func (s *SQLiteStore) GetEntry(hash string) (store.Entry, error) {
+	tableName := quote("logs_" + hash)
+	if _, err := s.db.Exec(logSQLSchema(tableName)); err != nil {
+		return nil, err
+	}
+	e := &Entry{
+		table: tableName,
+		db:    s.db,
+	}
+	return e, nil
+}
+
+// Entry is an store.Entry implementation
+type Entry struct {
+	table string
+	db    *sql.DB
+}
+
+type queryer interface {
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func (e *Entry) lastIndex(q queryer) (uint64, error) {
+	var index sql.NullInt64
+	if err := q.QueryRow("SELECT MAX(indx) FROM " + e.table).Scan(&index); err != nil {
+		return 0, err
+	}
+	if !index.Valid {
+		return 0, nil
+	}
+	return uint64(index.Int64) + 1, nil
+}
+
+// LastIndex implements the store interface
+func (e *Entry) LastIndex() (uint64, error) {
+	return e.lastIndex(e.db)
+}
+
+// StoreLogs implements the store interface
+func (e *Entry) StoreLogs(logs []*ethgo.Log) error {
+	tx, err := e.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	lastIndex, err := e.lastIndex(tx)
+	if err != nil {
+		return err
+	}
+
+	query := "INSERT INTO " + e.table + " (indx, log) VALUES (?, ?)"
+	for indx, log := range logs {
+		val, err := log.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(query, lastIndex+uint64(indx), string(val)); err != nil {
+			return err
+		}
+		if store.LogStoredHook != nil {
+			store.LogStoredHook(indx)
+		}
+	}
+	return tx.Commit()
+}
+
+// RemoveLogs implements the store interface
+func (e *Entry) RemoveLogs(indx uint64) error {
+	if _, err := e.db.Exec("DELETE FROM "+e.table+" WHERE indx >= ?", indx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetLog implements the store interface
+func (e *Entry) GetLog(indx uint64, log *ethgo.Log) error {
+	var val string
+	if err := e.db.QueryRow("SELECT log FROM "+e.table+" WHERE indx = ?", indx).Scan(&val); err != nil {
+		return err
+	}
+	return log.UnmarshalJSON([]byte(val))
+}
+
+func quote(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+var kvSQLSchema = `
+CREATE TABLE IF NOT EXISTS kv (
+	key text PRIMARY KEY,
+	val text
+);
+`
+
+func logSQLSchema(name string) string {
+	return `
+	CREATE TABLE IF NOT EXISTS ` + name + ` (
+		indx 	integer PRIMARY KEY,
+		log 	text
+	);
+	`
+}
@@ -0,0 +1,26 @@
+package trackersqlite
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/Ethernal-Tech/ethgo/tracker/store"
+)
+
+func setupDB(t *testing.T) (store.Store, func()) {
+	store, err := New(filepath.Join(store.TempDir(t), "test.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	close := func() {
+		if err := store.Close(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return store, close
+}
+
+func TestSQLiteStore(t *testing.T) {
+	store.TestStore(t, setupDB)
+}
@@ -2,6 +2,10 @@ package store
 
 import "github.com/Ethernal-Tech/ethgo"
 
+// LogStoredHook is called by the backends after each log of a batch is written and before
+// the batch is committed. It is only set by the tests to crash in the middle of a batch.
+var LogStoredHook func(indx int)
+
 // Store is a datastore for the tracker
 type Store interface {
 	// Get gets a value
@@ -1,22 +1,75 @@
 package store
 
 import (
+	"os"
+	"os/exec"
 	"reflect"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/Ethernal-Tech/ethgo"
 )
 
-// SetupDB is a function that creates a backend
+// SetupDB is a function that creates a backend. If it is called again within the
+// same test, it reopens the backend with the data of the test (see TempDir).
 type SetupDB func(t *testing.T) (Store, func())
 
-// TestStore tests a tracker store
-func TestStore(t *testing.T, setup SetupDB) {
-	testMultipleStores(t, setup)
-	testGetSet(t, setup)
-	testRemoveLogs(t, setup)
-	testStoreLogs(t, setup)
-	testPrefix(t, setup)
+// TestStore tests a tracker store. The tests in skip (i.e. StoreLogsInterrupted
+// for a backend that does not persist the logs) are skipped.
+func TestStore(t *testing.T, setup SetupDB, skip ...string) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, setup SetupDB)
+	}{
+		{"MultipleStores", testMultipleStores},
+		{"GetSet", testGetSet},
+		{"RemoveLogs", testRemoveLogs},
+		{"StoreLogs", testStoreLogs},
+		{"Prefix", testPrefix},
+		{"Cursors", testCursors},
+		{"StoreLogsInterrupted", testStoreLogsInterrupted},
+		{"QueryLogs", testQueryLogs},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range skip {
+				if name == tt.name {
+					t.Skip("not supported by the backend")
+				}
+			}
+			tt.test(t, setup)
+		})
+	}
+}
+
+// tempDirEnv is the directory of the backend of the process that
+// is crashed in the middle of a batch by testStoreLogsInterrupted
+const tempDirEnv = "TRACKER_STORE_TEST_DIR"
+
+// crashExitCode is the exit code of the process crashed by testStoreLogsInterrupted
+const crashExitCode = 3
+
+var tempDirs sync.Map
+
+// TempDir returns the directory for the backend of the test. Unlike t.TempDir,
+// it returns the same directory on each call within a test so that the setup
+// of a backend stored on disk reopens it.
+func TempDir(t *testing.T) string {
+	if dir := os.Getenv(tempDirEnv); dir != "" {
+		return dir
+	}
+	if dir, ok := tempDirs.Load(t); ok {
+		return dir.(string)
+	}
+	dir := t.TempDir()
+	tempDirs.Store(t, dir)
+	t.Cleanup(func() {
+		tempDirs.Delete(t)
+	})
+	return dir
 }
 
 func testMultipleStores(t *testing.T, setup SetupDB) {
@@ -240,3 +293,266 @@ func testRemoveLogs(t *testing.T, setup SetupDB) {
 		t.Fatal("bad")
 	}
 }
+
+func testCursors(t *testing.T, setup SetupDB) {
+	store, close := setup(t)
+	defer close()
+
+	cursor, err := GetCursor(store, "1", CursorFinal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cursor != nil {
+		t.Fatal("expected empty cursor")
+	}
+
+	head := &Cursor{Number: 10, Hash: ethgo.Hash{0x1}}
+	if err := SetCursor(store, "1", CursorHead, head); err != nil {
+		t.Fatal(err)
+	}
+	final := &Cursor{Number: 5, Hash: ethgo.Hash{0x2}}
+	if err := SetCursor(store, "1", CursorFinal, final); err != nil {
+		t.Fatal(err)
+	}
+
+	// the cursors are independent
+	cursor, err = GetCursor(store, "1", CursorHead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(head, cursor) {
+		t.Fatal("bad head cursor")
+	}
+	cursor, err = GetCursor(store, "1", CursorFinal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(final, cursor) {
+		t.Fatal("bad final cursor")
+	}
+
+	// the cursors of other entries are not set
+	cursor, err = GetCursor(store, "2", CursorHead)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cursor != nil {
+		t.Fatal("expected empty cursor")
+	}
+}
+
+func testStoreLogsInterrupted(t *testing.T, setup SetupDB) {
+	logs := []*ethgo.Log{}
+	for i := uint64(0); i < 2000; i++ {
+		logs = append(logs, &ethgo.Log{
+			BlockNumber: i,
+			Topics:      []ethgo.Hash{{byte(i)}},
+			Data:        make([]byte, 256),
+		})
+	}
+
+	if os.Getenv(tempDirEnv) != "" {
+		// the process crashes once half of the batch is written
+		LogStoredHook = func(indx int) {
+			if indx == len(logs[5:])/2 {
+				os.Exit(crashExitCode)
+			}
+		}
+		store, _ := setup(t)
+		entry, err := store.GetEntry("1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := entry.StoreLogs(logs[5:]); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	store, _ := setup(t)
+	entry, err := store.GetEntry("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entry.StoreLogs(logs[:5]); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// run the test again in a process that crashes in the middle of the batch
+	pattern := []string{}
+	for _, name := range strings.Split(t.Name(), "/") {
+		pattern = append(pattern, "^"+regexp.QuoteMeta(name)+"$")
+	}
+	cmd := exec.Command(os.Args[0], "-test.run="+strings.Join(pattern, "/"), "-test.count=1")
+	cmd.Env = append(os.Environ(), tempDirEnv+"="+TempDir(t))
+	out, err := cmd.CombinedOutput()
+	if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != crashExitCode {
+		t.Fatalf("expected the process to crash in the middle of the batch: %v %s", err, out)
+	}
+
+	// once the store is reopened, none of the logs of the batch are stored
+	store, close := setup(t)
+	defer close()
+
+	if entry, err = store.GetEntry("1"); err != nil {
+		t.Fatal(err)
+	}
+	indx, err := entry.LastIndex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if indx != 5 {
+		t.Fatalf("expected last index 5 but found %d", indx)
+	}
+	for i := uint64(0); i < indx; i++ {
+		var log ethgo.Log
+		if err := entry.GetLog(i, &log); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(*logs[i], log) {
+			t.Fatalf("bad log %d", i)
+		}
+	}
+
+	// the store keeps working and the logs are stored in order
+	if err := entry.StoreLogs(logs[5:]); err != nil {
+		t.Fatal(err)
+	}
+	if indx, err = entry.LastIndex(); err != nil {
+		t.Fatal(err)
+	}
+	if indx != uint64(len(logs)) {
+		t.Fatalf("expected last index %d but found %d", len(logs), indx)
+	}
+}
+
+func testQueryLogs(t *testing.T, setup SetupDB) {
+	store, close := setup(t)
+	defer close()
+
+	sigA, sigB := ethgo.Hash{0xa}, ethgo.Hash{0xb}
+
+	// 20 logs in 10 blocks from 3 addresses
+	logs := []*ethgo.Log{}
+	for i := 0; i < 20; i++ {
+		sig := sigA
+		if i%2 == 1 {
+			sig = sigB
+		}
+		logs = append(logs, &ethgo.Log{
+			BlockNumber: uint64(i / 2),
+			Address:     ethgo.Address{byte(i%3) + 1},
+			Topics:      []ethgo.Hash{sig, {byte(i)}},
+		})
+	}
+
+	entry, err := store.GetEntry("1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := entry.StoreLogs(logs); err != nil {
+		t.Fatal(err)
+	}
+
+	// query returns the indexes of the logs of the query
+	query := func(q *LogQuery) []int {
+		t.Helper()
+
+		page, err := QueryLogs(entry, q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res := []int{}
+		for _, log := range page.Logs {
+			res = append(res, int(log.Topics[1][0]))
+			if !reflect.DeepEqual(logs[res[len(res)-1]], log) {
+				t.Fatal("bad log")
+			}
+		}
+
+		num, err := CountLogs(entry, q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if q.Limit == 0 && q.Cursor == "" && num != uint64(len(res)) {
+			t.Fatalf("expected count %d but found %d", len(res), num)
+		}
+		return res
+	}
+
+	expect := func(q *LogQuery, expected ...int) {
+		t.Helper()
+
+		if expected == nil {
+			expected = []int{}
+		}
+		if res := query(q); !reflect.DeepEqual(expected, res) {
+			t.Fatalf("expected %v but found %v", expected, res)
+		}
+	}
+
+	expect(&LogQuery{FromBlock: 2, ToBlock: 5}, 4, 5, 6, 7, 8, 9, 10, 11)
+	expect(&LogQuery{FromBlock: 8}, 16, 17, 18, 19)
+	expect(&LogQuery{Address: []ethgo.Address{{0x1}}}, 0, 3, 6, 9, 12, 15, 18)
+	expect(&LogQuery{Address: []ethgo.Address{{0x1}, {0x3}}, ToBlock: 3}, 0, 2, 3, 5, 6)
+	expect(&LogQuery{Address: []ethgo.Address{{0x2}}, Topics: [][]ethgo.Hash{{sigB}}}, 1, 7, 13, 19)
+	expect(&LogQuery{Topics: [][]ethgo.Hash{nil, {{5}, {6}}}}, 5, 6)
+	expect(&LogQuery{Topics: [][]ethgo.Hash{nil, nil, {sigA}}})
+	expect(&LogQuery{FromBlock: 20})
+
+	// paginate the logs with the cursors
+	paginate := func(q *LogQuery) ([]int, int) {
+		t.Helper()
+
+		res, pages := []int{}, 0
+		for {
+			page, err := QueryLogs(entry, q)
+			if err != nil {
+				t.Fatal(err)
+			}
+			pages++
+			for _, log := range page.Logs {
+				res = append(res, int(log.Topics[1][0]))
+			}
+			if page.Next == "" {
+				return res, pages
+			}
+			q.Cursor = page.Next
+		}
+	}
+
+	res, pages := paginate(&LogQuery{Limit: 3})
+	if len(res) != 20 || pages != 7 {
+		t.Fatalf("bad pagination %v in %d pages", res, pages)
+	}
+	for i, indx := range res {
+		if i != indx {
+			t.Fatalf("bad pagination order %v", res)
+		}
+	}
+
+	res, pages = paginate(&LogQuery{Limit: 2, Address: []ethgo.Address{{0x1}}, Topics: [][]ethgo.Hash{{sigA}}})
+	if !reflect.DeepEqual([]int{0, 6, 12, 18}, res) || pages != 2 {
+		t.Fatalf("bad pagination %v in %d pages", res, pages)
+	}
+
+	if _, err := QueryLogs(entry, &LogQuery{Cursor: "#"}); err == nil {
+		t.Fatal("expected an invalid cursor error")
+	}
+
+	// the removed logs are not returned anymore
+	if err := entry.RemoveLogs(10); err != nil {
+		t.Fatal(err)
+	}
+	expect(&LogQuery{Address: []ethgo.Address{{0x1}}}, 0, 3, 6, 9)
+	expect(&LogQuery{FromBlock: 4}, 8, 9)
+
+	// and the logs stored again are not duplicated
+	if err := entry.StoreLogs(logs[10:]); err != nil {
+		t.Fatal(err)
+	}
+	expect(&LogQuery{Address: []ethgo.Address{{0x1}}}, 0, 3, 6, 9, 12, 15, 18)
+}
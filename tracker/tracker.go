@@ -18,7 +18,6 @@ import (
 	"github.com/Ethernal-Tech/ethgo"
 	"github.com/Ethernal-Tech/ethgo/blocktracker"
 	"github.com/Ethernal-Tech/ethgo/etherscan"
-	"github.com/Ethernal-Tech/ethgo/jsonrpc/codec"
 	"github.com/Ethernal-Tech/ethgo/tracker/store"
 	"github.com/Ethernal-Tech/ethgo/tracker/store/inmem"
 )
@@ -53,13 +52,21 @@ type BlockTracking interface {
 type FilterConfig struct {
 	Address []ethgo.Address `json:"address"`
 	Topics  [][]*ethgo.Hash `json:"topics"`
+	Name    string
 	Start   uint64
 	Hash    string
 	Async   bool
+
+	// Transactions tracks the transactions that match instead of the logs.
+	// The addresses and the topics are not used.
+	Transactions *TxFilter `json:"transactions,omitempty"`
 }
 
 func (f *FilterConfig) buildHash() {
 	h := sha256.New()
+	if f.Name != "" {
+		h.Write([]byte(f.Name))
+	}
 	for _, i := range f.Address {
 		h.Write([]byte(i.String()))
 	}
@@ -80,6 +87,19 @@ func (f *FilterConfig) buildHash() {
 			h.Write([]byte(innerTopic.String()))
 		}
 	}
+	if txs := f.Transactions; txs != nil {
+		h.Write([]byte("transactions"))
+		for _, addr := range txs.From {
+			h.Write([]byte("from" + addr.String()))
+		}
+		for _, addr := range txs.To {
+			h.Write([]byte("to" + addr.String()))
+		}
+		for _, selector := range txs.Selectors {
+			h.Write(selector[:])
+		}
+		h.Write([]byte(fmt.Sprintf("%t%t", txs.Receipts, txs.Traces)))
+	}
 	f.Hash = hex.EncodeToString(h.Sum(nil))
 }
 
@@ -94,13 +114,189 @@ func (f *FilterConfig) getFilterSearch() *ethgo.LogFilter {
 	return filter
 }
 
+// match returns true if the log matches the addresses and the topics of the filter
+func (f *FilterConfig) match(log *ethgo.Log) bool {
+	if len(f.Address) != 0 {
+		found := false
+		for _, addr := range f.Address {
+			if addr == log.Address {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	for indx, topics := range f.Topics {
+		if isWildcard(topics) {
+			continue
+		}
+		if indx >= len(log.Topics) {
+			return false
+		}
+		found := false
+		for _, topic := range topics {
+			if *topic == log.Topics[indx] {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// isWildcard returns true if the topics at one position match any topic
+func isWildcard(topics []*ethgo.Hash) bool {
+	if len(topics) == 0 {
+		return true
+	}
+	for _, topic := range topics {
+		if topic == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// combinedFilterSearch returns a log filter that matches the logs of all the filters
+func combinedFilterSearch(filters []*filter) *ethgo.LogFilter {
+	if len(filters) == 1 {
+		return filters[0].config.getFilterSearch()
+	}
+
+	query := &ethgo.LogFilter{}
+
+	// the union of the addresses unless a filter matches any address
+	addrs := []ethgo.Address{}
+	seenAddrs := map[ethgo.Address]struct{}{}
+	for _, f := range filters {
+		if len(f.config.Address) == 0 {
+			addrs = nil
+			break
+		}
+		for _, addr := range f.config.Address {
+			if _, ok := seenAddrs[addr]; !ok {
+				seenAddrs[addr] = struct{}{}
+				addrs = append(addrs, addr)
+			}
+		}
+	}
+	if len(addrs) != 0 {
+		query.Address = addrs
+	}
+
+	// the union of the topics in each position unless a filter matches any topic there
+	maxTopics := 0
+	for _, f := range filters {
+		if len(f.config.Topics) > maxTopics {
+			maxTopics = len(f.config.Topics)
+		}
+	}
+	topics := make([][]*ethgo.Hash, maxTopics)
+	for indx := range topics {
+		seenTopics := map[ethgo.Hash]struct{}{}
+		for _, f := range filters {
+			if indx >= len(f.config.Topics) || isWildcard(f.config.Topics[indx]) {
+				topics[indx] = nil
+				break
+			}
+			for _, topic := range f.config.Topics[indx] {
+				if _, ok := seenTopics[*topic]; !ok {
+					seenTopics[*topic] = struct{}{}
+					topics[indx] = append(topics[indx], topic)
+				}
+			}
+		}
+	}
+	for len(topics) != 0 && topics[len(topics)-1] == nil {
+		topics = topics[:len(topics)-1]
+	}
+	if len(topics) != 0 {
+		query.Topics = topics
+	}
+	return query
+}
+
+// filter is a filter tracked by the tracker with its own entry and checkpoint
+type filter struct {
+	config *FilterConfig
+	entry  store.Entry
+
+	// next is the next block number to process
+	next uint64
+
+	// pending is true if the filter was added at runtime and has not caught up yet
+	pending bool
+
+	removed int32
+
+	// acked are the idempotency keys of the logs acknowledged
+	// by the handler after the checkpoint (in ackedKeys order)
+	acked     map[string]struct{}
+	ackedKeys []string
+}
+
+func (f *filter) isRemoved() bool {
+	return atomic.LoadInt32(&f.removed) != 0
+}
+
+// filterLogs returns the logs of the filter that are not processed yet
+func (f *filter) filterLogs(logs []*ethgo.Log) []*ethgo.Log {
+	res := []*ethgo.Log{}
+	for _, log := range logs {
+		if log.BlockNumber >= f.next && f.config.match(log) {
+			res = append(res, log)
+		}
+	}
+	return res
+}
+
 // Config is the configuration of the tracker
 type Config struct {
 	BatchSize       uint64
 	BlockTracker    BlockTracking
 	EtherscanAPIKey string
 	Filter          *FilterConfig
+	Filters         []*FilterConfig
 	Store           store.Store
+
+	// MaxBatchSize is the maximum number of blocks of a eth_getLogs query
+	MaxBatchSize uint64
+
+	// MaxBatchLogs is the number of logs expected in a eth_getLogs response
+	MaxBatchLogs uint64
+
+	// Concurrency is the number of eth_getLogs queries sent concurrently
+	Concurrency int
+
+	// Finality is the block tag (Finalized or Safe) up to which the tracker syncs
+	Finality ethgo.BlockNumber
+
+	// Confirmations is the number of blocks after which a block is final
+	// if the chain does not support the finality tag
+	Confirmations uint64
+
+	// Handler processes the events instead of EventCh
+	Handler Handler
+
+	// HandlerAttempts is the number of times an event is delivered to the handler
+	HandlerAttempts uint64
+
+	// HandlerBackoff is the delay before the first retry of the handler
+	HandlerBackoff time.Duration
+
+	// AtLeastOnce stores the idempotency keys of the acknowledged logs
+	AtLeastOnce bool
+
+	// Tracer traces the blocks for the internal transfers of the transaction filters
+	Tracer BlockTracer
+
+	// HistoricalSource is the source of the logs to seed the filters without a checkpoint
+	HistoricalSource HistoricalSource
 }
 
 type ConfigOption func(*Config)
@@ -129,6 +325,14 @@ func WithFilter(f *FilterConfig) ConfigOption {
 	}
 }
 
+// WithFilters sets multiple filters to track with a single tracker. Each filter
+// must have a unique name. If set, the filter of WithFilter is not used.
+func WithFilters(f ...*FilterConfig) ConfigOption {
+	return func(c *Config) {
+		c.Filters = append(c.Filters, f...)
+	}
+}
+
 func WithEtherscan(k string) ConfigOption {
 	return func(c *Config) {
 		c.EtherscanAPIKey = k
@@ -160,7 +364,9 @@ type Tracker struct {
 	provider     Provider
 	config       *Config
 	store        store.Store
-	entry        store.Entry
+	filters      []*filter
+	filtersLock  sync.RWMutex
+	filterCh     chan struct{}
 	preSyncOnce  sync.Once
 	blockTracker BlockTracking
 	synced       int32
@@ -189,58 +395,230 @@ func NewTracker(provider Provider, opts ...ConfigOption) (*Tracker, error) {
 		DoneCh:       make(chan struct{}, 1),
 		EventCh:      make(chan *Event),
 		SyncCh:       make(chan uint64, 1),
+		filterCh:     make(chan struct{}, 1),
 		synced:       0,
 	}
-	if err := t.setupFilter(); err != nil {
-		return nil, err
+	if config.Finality != 0 && config.Finality != ethgo.Finalized && config.Finality != ethgo.Safe {
+		return nil, fmt.Errorf("finality tag %d not supported", config.Finality)
 	}
-	return t, nil
-}
 
-// NewFilter creates a new log filter
-func (t *Tracker) setupFilter() error {
-	if t.config.Filter == nil {
-		// generic config
-		t.config.Filter = &FilterConfig{}
+	filters := config.Filters
+	if len(filters) == 0 {
+		if config.Filter == nil {
+			// generic config
+			config.Filter = &FilterConfig{}
+		}
+		filters = []*FilterConfig{config.Filter}
+	}
+	for _, filterConfig := range filters {
+		if t.findFilter(filterConfig.Name) != nil {
+			return nil, fmt.Errorf("filter '%s' already exists", filterConfig.Name)
+		}
+		f, err := t.setupFilter(filterConfig)
+		if err != nil {
+			return nil, err
+		}
+		t.filters = append(t.filters, f)
 	}
+	return t, nil
+}
 
+// setupFilter creates the entry of the filter in the store
+func (t *Tracker) setupFilter(config *FilterConfig) (*filter, error) {
 	// generate a random hash if not provided
-	if t.config.Filter.Hash == "" {
-		t.config.Filter.buildHash()
+	if config.Hash == "" {
+		config.buildHash()
+	}
+	if err := t.validateTxFilter(config); err != nil {
+		return nil, err
 	}
 
-	entry, err := t.store.GetEntry(t.config.Filter.Hash)
+	entry, err := t.store.GetEntry(config.Hash)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	f := &filter{
+		config: config,
+		entry:  entry,
 	}
-	t.entry = entry
 
 	// insert the filter config in the db
-	filterKey := dbFilter + "_" + t.config.Filter.Hash
+	filterKey := dbFilter + "_" + config.Hash
 	data, err := t.store.Get(filterKey)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	if data == "" {
-		raw, err := json.Marshal(t.config.Filter)
+		raw, err := json.Marshal(config)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		rawStr := hex.EncodeToString(raw)
 		if err := t.store.Set(filterKey, rawStr); err != nil {
-			return err
+			return nil, err
+		}
+	}
+
+	// resume from the last block processed
+	last, err := t.getLastBlock(f)
+	if err != nil {
+		return nil, err
+	}
+	if last != nil {
+		f.next = last.Number + 1
+	}
+
+	// remove the logs stored after the checkpoint if the tracker stopped before
+	// storing it. They are processed again from the checkpoint.
+	logs, index, err := t.logsFrom(f, f.next, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(logs) != 0 {
+		if err := f.entry.RemoveLogs(index); err != nil {
+			return nil, err
 		}
 	}
+
+	if err := t.loadAcked(f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// AddFilter adds a filter to the tracker. If the tracker is already syncing, the
+// filter catches up with the head on its own without resyncing the other filters.
+func (t *Tracker) AddFilter(config *FilterConfig) error {
+	t.filtersLock.Lock()
+	defer t.filtersLock.Unlock()
+
+	if t.findFilterLocked(config.Name) != nil {
+		return fmt.Errorf("filter '%s' already exists", config.Name)
+	}
+	f, err := t.setupFilter(config)
+	if err != nil {
+		return err
+	}
+	f.pending = true
+	t.filters = append(t.filters, f)
+
+	select {
+	case t.filterCh <- struct{}{}:
+	default:
+	}
 	return nil
 }
 
+// RemoveFilter stops tracking the filter. The logs and the checkpoint of the
+// filter are kept in the store so that it resumes if it is added again.
+func (t *Tracker) RemoveFilter(name string) error {
+	t.filtersLock.Lock()
+	defer t.filtersLock.Unlock()
+
+	for indx, f := range t.filters {
+		if f.config.Name == name {
+			atomic.StoreInt32(&f.removed, 1)
+			t.filters = append(t.filters[:indx], t.filters[indx+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("filter '%s' not found", name)
+}
+
+func (t *Tracker) findFilter(name string) *filter {
+	t.filtersLock.RLock()
+	defer t.filtersLock.RUnlock()
+
+	return t.findFilterLocked(name)
+}
+
+func (t *Tracker) findFilterLocked(name string) *filter {
+	for _, f := range t.filters {
+		if f.config.Name == name {
+			return f
+		}
+	}
+	return nil
+}
+
+// getFilters returns the filters that are tracked. If pending is true, it only returns
+// the filters added at runtime that have not caught up with the head yet.
+func (t *Tracker) getFilters(pending bool) []*filter {
+	t.filtersLock.RLock()
+	defer t.filtersLock.RUnlock()
+
+	res := []*filter{}
+	for _, f := range t.filters {
+		if !pending || f.pending {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// clearPending marks the filters added at runtime as synced with the head
+func (t *Tracker) clearPending(filters []*filter) {
+	t.filtersLock.Lock()
+	defer t.filtersLock.Unlock()
+
+	for _, f := range filters {
+		f.pending = false
+	}
+}
+
+// activeFilters returns the filters that are synced with the head of the tracker.
+// The filters added at runtime catch up on their own.
+func (t *Tracker) activeFilters() []*filter {
+	t.filtersLock.RLock()
+	defer t.filtersLock.RUnlock()
+
+	res := []*filter{}
+	for _, f := range t.filters {
+		if !f.pending {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
+// Entry returns the store entry of the first filter
 func (t *Tracker) Entry() store.Entry {
-	return t.entry
+	filters := t.getFilters(false)
+	if len(filters) == 0 {
+		return nil
+	}
+	return filters[0].entry
 }
 
-// GetLastBlock returns the last block processed for this filter
+// FilterEntry returns the store entry of the filter
+func (t *Tracker) FilterEntry(name string) (store.Entry, error) {
+	f := t.findFilter(name)
+	if f == nil {
+		return nil, fmt.Errorf("filter '%s' not found", name)
+	}
+	return f.entry, nil
+}
+
+// GetLastBlock returns the last block processed for the first filter
 func (t *Tracker) GetLastBlock() (*ethgo.Block, error) {
-	buf, err := t.store.Get(dbLastBlock + "_" + t.config.Filter.Hash)
+	filters := t.getFilters(false)
+	if len(filters) == 0 {
+		return nil, nil
+	}
+	return t.getLastBlock(filters[0])
+}
+
+// GetFilterLastBlock returns the last block processed for the filter
+func (t *Tracker) GetFilterLastBlock(name string) (*ethgo.Block, error) {
+	f := t.findFilter(name)
+	if f == nil {
+		return nil, fmt.Errorf("filter '%s' not found", name)
+	}
+	return t.getLastBlock(f)
+}
+
+func (t *Tracker) getLastBlock(f *filter) (*ethgo.Block, error) {
+	buf, err := t.store.Get(dbLastBlock + "_" + f.config.Hash)
 	if err != nil {
 		return nil, err
 	}
@@ -258,7 +636,7 @@ func (t *Tracker) GetLastBlock() (*ethgo.Block, error) {
 	return b, nil
 }
 
-func (t *Tracker) storeLastBlock(b *ethgo.Block) error {
+func (t *Tracker) storeLastBlock(f *filter, b *ethgo.Block) error {
 	if b.Difficulty == nil {
 		b.Difficulty = big.NewInt(0)
 	}
@@ -267,14 +645,19 @@ func (t *Tracker) storeLastBlock(b *ethgo.Block) error {
 		return err
 	}
 	raw := hex.EncodeToString(buf)
-	return t.store.Set(dbLastBlock+"_"+t.config.Filter.Hash, raw)
+	if err := t.store.Set(dbLastBlock+"_"+f.config.Hash, raw); err != nil {
+		return err
+	}
+	f.next = b.Number + 1
+	return nil
 }
 
-func (t *Tracker) emitEvent(evnt *Event) {
+func (t *Tracker) emitEvent(f *filter, evnt *Event) {
 	if evnt == nil {
 		return
 	}
-	if t.config.Filter.Async {
+	evnt.Filter = f.config.Name
+	if f.config.Async {
 		select {
 		case t.EventCh <- evnt:
 		default:
@@ -343,92 +726,6 @@ func (t *Tracker) findAncestor(block, pivot *ethgo.Block) (uint64, error) {
 	return 0, fmt.Errorf("the reorg is bigger than maxBlockBacklog %d", t.blockTracker.MaxBlockBacklog())
 }
 
-func (t *Tracker) emitLogs(typ EventType, logs []*ethgo.Log) {
-	evnt := &Event{}
-	if typ == EventAdd {
-		evnt.Added = logs
-	}
-	if typ == EventDel {
-		evnt.Removed = logs
-	}
-	t.emitEvent(evnt)
-}
-
-func tooMuchDataRequestedError(err error) bool {
-	obj, ok := err.(*codec.ErrorObject)
-	if !ok {
-		return false
-	}
-	if obj.Message == "query returned more than 10000 results" {
-		return true
-	}
-	return false
-}
-
-func (t *Tracker) syncBatch(ctx context.Context, from, to uint64) error {
-	query := t.config.Filter.getFilterSearch()
-
-	batchSize := t.config.BatchSize
-	additiveFactor := uint64(float64(batchSize) * 0.10)
-
-	i := from
-
-START:
-	dst := min(to, i+batchSize)
-
-	query.SetFromUint64(i)
-	query.SetToUint64(dst)
-
-	logs, err := t.provider.GetLogs(query)
-	if err != nil {
-		if tooMuchDataRequestedError(err) {
-			// multiplicative decrease
-			batchSize = batchSize / 2
-			goto START
-		}
-		return err
-	}
-
-	if t.SyncCh != nil {
-		select {
-		case t.SyncCh <- dst:
-		default:
-		}
-	}
-
-	// add logs to the store
-	if err := t.entry.StoreLogs(logs); err != nil {
-		return err
-	}
-	t.emitLogs(EventAdd, logs)
-
-	// update the last block entry
-	block, err := t.getBlockByNumber(dst)
-	if err != nil {
-		return err
-	}
-	if err := t.storeLastBlock(block); err != nil {
-		return err
-	}
-
-	// check if the execution is over after each query batch
-	if err := ctx.Err(); err != nil {
-		return err
-	}
-
-	i += batchSize + 1
-
-	// update the batchSize with additive increase
-	if batchSize < t.config.BatchSize {
-		batchSize = min(t.config.BatchSize, batchSize+additiveFactor)
-	}
-
-	if i <= to {
-		goto START
-	}
-	return nil
-}
-
 func (t *Tracker) preSyncCheck() error {
 	var err error
 	t.preSyncOnce.Do(func() {
@@ -483,8 +780,8 @@ func (t *Tracker) fastTrack(filterConfig *FilterConfig) (*ethgo.Block, error) {
 		return bb, nil
 	}
 
-	// Only possible if we filter addresses
-	if len(filterConfig.Address) == 0 {
+	// Only possible if we filter the logs of addresses
+	if len(filterConfig.Address) == 0 || filterConfig.Transactions != nil {
 		return nil, nil
 	}
 
@@ -574,7 +871,7 @@ func (t *Tracker) BatchSync(ctx context.Context) error {
 
 	close(t.ReadyCh)
 
-	if err := t.syncImpl(ctx); err != nil {
+	if err := t.syncFilters(ctx, t.getFilters(false)); err != nil {
 		return err
 	}
 
@@ -599,7 +896,17 @@ func (t *Tracker) Sync(ctx context.Context) error {
 	for {
 		select {
 		case evnt := <-sub:
-			if err := t.handleBlockEvnt(evnt); err != nil {
+			if t.isFinalityMode() {
+				// a new head may move the final block
+				if err := t.syncFinal(ctx, t.activeFilters()); err != nil {
+					return err
+				}
+			} else if err := t.handleBlockEvnt(ctx, evnt); err != nil {
+				return err
+			}
+		case <-t.filterCh:
+			// catch up the filters added at runtime
+			if err := t.syncFilters(ctx, t.getFilters(true)); err != nil {
 				return err
 			}
 		case <-ctx.Done():
@@ -608,10 +915,22 @@ func (t *Tracker) Sync(ctx context.Context) error {
 	}
 }
 
-func (t *Tracker) syncImpl(ctx context.Context) error {
+// syncFilters syncs the filters up to the head or up to the final block in finality mode
+func (t *Tracker) syncFilters(ctx context.Context, filters []*filter) error {
+	if t.isFinalityMode() {
+		return t.syncFinal(ctx, filters)
+	}
+	return t.syncImpl(ctx, filters)
+}
+
+func (t *Tracker) syncImpl(ctx context.Context, filters []*filter) error {
 	if err := t.preSyncCheck(); err != nil {
 		return err
 	}
+	if len(filters) == 0 {
+		return nil
+	}
+	defer t.clearPending(filters)
 
 	lock := t.blockTracker.AcquireLock()
 	defer func() {
@@ -637,67 +956,24 @@ func (t *Tracker) syncImpl(ctx context.Context) error {
 	}
 	targetNum := target.Number
 
-	last, err := t.GetLastBlock()
-	if err != nil {
-		return err
-	}
-	if last == nil {
-		// Try to fast track to the valid block (if possible)
-		last, err = t.fastTrack(t.config.Filter)
-		if err != nil {
-			return fmt.Errorf("failed to fasttrack: %v", err)
-		}
-		if last != nil {
-			if err := t.storeLastBlock(last); err != nil {
-				return err
-			}
-		}
-	} else {
-		if last.Hash == target.Hash {
-			return nil
-		}
-	}
-
-	// There might been a reorg when we stopped syncing last time,
-	// check that our 'beacon' block matches the one in the chain.
-	// If that is not the case, we consider beacon-maxBackLog our
-	// real origin point and remove any logs ahead of that point.
-
-	var origin uint64
-	if last != nil {
-		if last.Number > targetNum {
-			return fmt.Errorf("store is more advanced than the chain")
-		}
-
-		pivot, err := t.getBlockByNumber(last.Number)
+	// find the first block to sync for each filter
+	syncing := []*filter{}
+	for _, f := range filters {
+		synced, err := t.reconcileFilter(ctx, f, target)
 		if err != nil {
 			return err
 		}
-
-		if last.Number == targetNum {
-			origin = last.Number
-		} else {
-			origin = last.Number + 1
+		if !synced {
+			syncing = append(syncing, f)
 		}
+	}
+	if len(syncing) == 0 {
+		return nil
+	}
 
-		if pivot.Hash != last.Hash {
-			ancestor, err := t.findAncestor(last, pivot)
-			if err != nil {
-				return err
-			}
-
-			origin = ancestor + 1
-			logs, err := t.removeLogs(ancestor+1, nil)
-			if err != nil {
-				return err
-			}
-			t.emitLogs(EventDel, logs)
-
-			last, err = t.getBlockByNumber(ancestor)
-			if err != nil {
-				return err
-			}
-		}
+	origin := syncing[0].next
+	for _, f := range syncing[1:] {
+		origin = min(origin, f.next)
 	}
 
 	step := targetNum - origin + 1
@@ -718,7 +994,7 @@ func (t *Tracker) syncImpl(ctx context.Context) error {
 			lock.Unlock()
 
 			limit := targetNum - t.blockTracker.MaxBlockBacklog()
-			if err := t.syncBatch(ctx, origin, limit); err != nil {
+			if err := t.syncBatch(ctx, syncing, origin, limit); err != nil {
 				return err
 			}
 
@@ -735,26 +1011,87 @@ func (t *Tracker) syncImpl(ctx context.Context) error {
 	trackerBlocks := t.blockTracker.BlocksBlocked()
 	added := trackerBlocks[uint64(len(trackerBlocks))-1-(targetNum-origin):]
 
-	evnt, err := t.doFilter(added, nil)
-	if err != nil {
+	if err := t.doFilter(ctx, syncing, added, nil); err != nil {
 		return err
 	}
-	if evnt != nil {
-		t.emitEvent(evnt)
-	}
 
 	// release the lock on the blocks
 	lock.Unlock()
 	return nil
 }
 
-func (t *Tracker) removeLogs(number uint64, hash *ethgo.Hash) ([]*ethgo.Log, error) {
-	index, err := t.entry.LastIndex()
+// reconcileFilter sets the next block to sync for the filter. If there was a reorg
+// since the filter stopped syncing, the last block processed (the 'beacon') does not
+// match the one in the chain. In that case, the logs after the common ancestor are
+// removed. It returns true if the filter is already synced up to the target block.
+func (t *Tracker) reconcileFilter(ctx context.Context, f *filter, target *ethgo.Block) (bool, error) {
+	last, err := t.getLastBlock(f)
 	if err != nil {
-		return nil, err
+		return false, err
+	}
+	if last == nil {
+		// Try to seed the filter with the historical source (if any)
+		last, err = t.seed(ctx, f, target)
+		if err != nil {
+			return false, fmt.Errorf("failed to seed: %v", err)
+		}
+	}
+	if last == nil {
+		// Try to fast track to the valid block (if possible)
+		last, err = t.fastTrack(f.config)
+		if err != nil {
+			return false, fmt.Errorf("failed to fasttrack: %v", err)
+		}
+		if last == nil {
+			f.next = 0
+			return false, nil
+		}
+		if err := t.storeLastBlock(f, last); err != nil {
+			return false, err
+		}
+	} else if last.Hash == target.Hash {
+		f.next = last.Number + 1
+		return true, nil
+	}
+
+	if last.Number > target.Number {
+		return false, fmt.Errorf("store is more advanced than the chain")
+	}
+
+	pivot, err := t.getBlockByNumber(last.Number)
+	if err != nil {
+		return false, err
+	}
+
+	f.next = last.Number + 1
+	if pivot.Hash != last.Hash {
+		ancestor, err := t.findAncestor(last, pivot)
+		if err != nil {
+			return false, err
+		}
+
+		f.next = ancestor + 1
+		logs, index, err := t.logsFrom(f, ancestor+1, nil)
+		if err != nil {
+			return false, err
+		}
+		if err := t.commit(ctx, f, &update{removed: logs, removeIndex: index}); err != nil {
+			return false, err
+		}
+	}
+	return f.next > target.Number, nil
+}
+
+// logsFrom returns the logs of the entry from the block number in reverse order and the
+// index of the first one. If the hash is set, the logs of that block number are only
+// returned if they belong to the block with the hash.
+func (t *Tracker) logsFrom(f *filter, number uint64, hash *ethgo.Hash) ([]*ethgo.Log, uint64, error) {
+	index, err := f.entry.LastIndex()
+	if err != nil {
+		return nil, 0, err
 	}
 	if index == 0 {
-		return nil, nil
+		return nil, 0, nil
 	}
 
 	var remove []*ethgo.Log
@@ -762,8 +1099,8 @@ func (t *Tracker) removeLogs(number uint64, hash *ethgo.Hash) ([]*ethgo.Log, err
 		elemIndex := index - 1
 
 		var log ethgo.Log
-		if err := t.entry.GetLog(elemIndex, &log); err != nil {
-			return nil, err
+		if err := f.entry.GetLog(elemIndex, &log); err != nil {
+			return nil, 0, err
 		}
 		if log.BlockNumber == number {
 			if hash != nil && log.BlockHash != *hash {
@@ -780,11 +1117,7 @@ func (t *Tracker) removeLogs(number uint64, hash *ethgo.Hash) ([]*ethgo.Log, err
 		}
 		index = elemIndex
 	}
-
-	if err := t.entry.RemoveLogs(index); err != nil {
-		return nil, err
-	}
-	return remove, nil
+	return remove, index, nil
 }
 
 func revertLogs(in []*ethgo.Log) (out []*ethgo.Log) {
@@ -794,7 +1127,7 @@ func revertLogs(in []*ethgo.Log) (out []*ethgo.Log) {
 	return
 }
 
-func (t *Tracker) handleBlockEvnt(blockEvnt *blocktracker.BlockEvent) error {
+func (t *Tracker) handleBlockEvnt(ctx context.Context, blockEvnt *blocktracker.BlockEvent) error {
 	if blockEvnt == nil {
 		return nil
 	}
@@ -806,60 +1139,114 @@ func (t *Tracker) handleBlockEvnt(blockEvnt *blocktracker.BlockEvent) error {
 	}
 
 	if t.IsSynced() {
-		evnt, err := t.doFilter(blockEvnt.Added, blockEvnt.Removed)
-		if err != nil {
+		if err := t.doFilter(ctx, t.activeFilters(), blockEvnt.Added, blockEvnt.Removed); err != nil {
 			return err
 		}
-		if evnt != nil {
-			t.emitEvent(evnt)
-		}
 	}
 	return nil
 }
 
-func (t *Tracker) doFilter(added []*ethgo.Block, removed []*ethgo.Block) (*Event, error) {
-	evnt := &Event{}
+// doFilter removes the logs of the removed blocks and stores the logs of the added blocks
+// for each filter. A single query is done for each block with the logs of all the filters.
+func (t *Tracker) doFilter(ctx context.Context, filters []*filter, added []*ethgo.Block, removed []*ethgo.Block) error {
+	updates := make([]*update, len(filters))
+	for indx := range filters {
+		updates[indx] = &update{}
+	}
+
 	if len(removed) != 0 {
 		pivot := removed[0]
-		logs, err := t.removeLogs(pivot.Number, &pivot.Hash)
-		if err != nil {
-			return nil, err
+		for indx, f := range filters {
+			logs, index, err := t.logsFrom(f, pivot.Number, &pivot.Hash)
+			if err != nil {
+				return err
+			}
+			updates[indx].removed = revertLogs(logs)
+			updates[indx].removeIndex = index
+
+			// the blocks after the pivot have to be processed again
+			if f.next > pivot.Number {
+				f.next = pivot.Number
+			}
 		}
-		evnt.Removed = append(evnt.Removed, revertLogs(logs)...)
 	}
 
 	for _, block := range added {
-		// check logs for this blocks
-		query := t.config.Filter.getFilterSearch()
-		query.BlockHash = &block.Hash
+		// only the filters that have not processed the block yet are part of the query
+		logActive, txActive := []int{}, []int{}
+		logFilters, txFilters := []*filter{}, []*filter{}
+		for indx, f := range filters {
+			if f.next > block.Number || f.isRemoved() {
+				continue
+			}
+			if f.isTx() {
+				txActive = append(txActive, indx)
+				txFilters = append(txFilters, f)
+			} else {
+				logActive = append(logActive, indx)
+				logFilters = append(logFilters, f)
+			}
+		}
+
+		if len(logActive) != 0 {
+			// check logs for this blocks
+			query := combinedFilterSearch(logFilters)
+			query.BlockHash = &block.Hash
+
+			// We check the hash, we need to do a retry to let unsynced nodes get the block
+			var logs []*ethgo.Log
+			var err error
+
+			for i := 0; i < 5; i++ {
+				logs, err = t.provider.GetLogs(query)
+				if err == nil {
+					break
+				}
+				time.Sleep(500 * time.Millisecond)
+			}
+			if err != nil {
+				return err
+			}
 
-		// We check the hash, we need to do a retry to let unsynced nodes get the block
-		var logs []*ethgo.Log
-		var err error
+			for _, indx := range logActive {
+				f := filters[indx]
 
-		for i := 0; i < 5; i++ {
-			logs, err = t.provider.GetLogs(query)
-			if err == nil {
-				break
+				filterLogs := f.filterLogs(logs)
+				updates[indx].added = append(updates[indx].added, filterLogs...)
+				updates[indx].last = block
 			}
-			time.Sleep(500 * time.Millisecond)
-		}
-		if err != nil {
-			return nil, err
 		}
 
-		// add logs to the store
-		if err := t.entry.StoreLogs(logs); err != nil {
-			return nil, err
+		if len(txActive) != 0 {
+			// check the transactions of the full block
+			full, err := t.provider.GetBlockByHash(block.Hash, true)
+			if err != nil {
+				return err
+			} else if full == nil {
+				return fmt.Errorf("block with hash %s not found", block.Hash)
+			}
+			txs, err := t.matchTxs(txFilters, full)
+			if err != nil {
+				return err
+			}
+
+			for i, indx := range txActive {
+				updates[indx].added = append(updates[indx].added, txs[i]...)
+				updates[indx].last = block
+			}
 		}
-		evnt.Added = append(evnt.Added, logs...)
 	}
 
-	// store the last block as the new index
-	if err := t.storeLastBlock(added[len(added)-1]); err != nil {
-		return nil, err
+	for indx, f := range filters {
+		if updates[indx].last == nil && len(removed) == 0 {
+			continue
+		}
+		// store the logs and the last block as the new index
+		if err := t.commit(ctx, f, updates[indx]); err != nil {
+			return err
+		}
 	}
-	return evnt, nil
+	return nil
 }
 
 func (t *Tracker) getBlockByNumber(blockNumber uint64) (*ethgo.Block, error) {
@@ -889,6 +1276,13 @@ type Event struct {
 	Type    EventType
 	Added   []*ethgo.Log
 	Removed []*ethgo.Log
+
+	// AddedTxs and RemovedTxs are the transactions of a transaction filter (see TxFilter)
+	AddedTxs   []*TrackedTx
+	RemovedTxs []*TrackedTx
+
+	// Filter is the name of the filter of the logs
+	Filter string
 }
 
 // BlockEvent is an event emitted when a new block is included
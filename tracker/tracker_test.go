@@ -7,6 +7,8 @@ import (
 	"math/rand"
 	"reflect"
 	"strconv"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -15,8 +17,11 @@ import (
 	"github.com/Ethernal-Tech/ethgo/blocktracker"
 	"github.com/Ethernal-Tech/ethgo/jsonrpc"
 	"github.com/Ethernal-Tech/ethgo/jsonrpc/codec"
+	"github.com/Ethernal-Tech/ethgo/simulated"
 	"github.com/Ethernal-Tech/ethgo/testutil"
+	"github.com/Ethernal-Tech/ethgo/tracker/store"
 	"github.com/Ethernal-Tech/ethgo/tracker/store/inmem"
+	"github.com/Ethernal-Tech/ethgo/wallet"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -37,7 +42,7 @@ func testFilter(t *testing.T, provider Provider, filterConfig *FilterConfig) []*
 	if err := tt.BatchSync(ctx); err != nil {
 		require.NoError(t, err)
 	}
-	return tt.entry.(*inmem.Entry).Logs()
+	return tt.Entry().(*inmem.Entry).Logs()
 }
 
 func TestPolling(t *testing.T) {
@@ -213,7 +218,7 @@ func TestTrackerSyncerRestarts(t *testing.T) {
 		if tt.blockTracker.BlocksBlocked()[9].Number != uint64(last-1) {
 			t.Fatal("bad")
 		}
-		if !testutil.CompareLogs(l.GetLogs(), tt.entry.(*inmem.Entry).Logs()) {
+		if !testutil.CompareLogs(l.GetLogs(), tt.Entry().(*inmem.Entry).Logs()) {
 			t.Fatal("bad")
 		}
 	}
@@ -288,7 +293,7 @@ func testSyncerReconcile(t *testing.T, iniLen, forkNum, endLen int) {
 	}()
 	tt1.WaitDuration(2 * time.Second)
 
-	logs := tt1.entry.(*inmem.Entry).Logs()
+	logs := tt1.Entry().(*inmem.Entry).Logs()
 
 	if !testutil.CompareLogs(l1.GetLogs(), logs) {
 		t.Fatal("bad")
@@ -420,7 +425,7 @@ func testTrackerSyncerRandom(t *testing.T, n int, backlog uint64) {
 			}
 		}
 		// validate logs
-		if logs := m.GetAllLogs(); !testutil.CompareLogs(tt.entry.(*inmem.Entry).Logs(), logs) {
+		if logs := m.GetAllLogs(); !testutil.CompareLogs(tt.Entry().(*inmem.Entry).Logs(), logs) {
 			t.Fatal("bad logs")
 		}
 
@@ -657,7 +662,7 @@ func TestTrackerReconcile(t *testing.T) {
 			}
 			// add the history to the store
 			for _, b := range c.History {
-				tt.entry.StoreLogs(b.GetLogs())
+				tt.Entry().StoreLogs(b.GetLogs())
 			}
 
 			for _, b := range c.Reconcile {
@@ -668,7 +673,7 @@ func TestTrackerReconcile(t *testing.T) {
 				if aux == nil {
 					continue
 				}
-				if err := tt.handleBlockEvnt(aux); err != nil {
+				if err := tt.handleBlockEvnt(context.Background(), aux); err != nil {
 					t.Fatal(err)
 				}
 
@@ -704,7 +709,7 @@ func TestTrackerReconcile(t *testing.T) {
 			}
 
 			// check the post state (logs and blocks) after all the reconcile events
-			if !testutil.CompareLogs(tt.entry.(*inmem.Entry).Logs(), c.Expected.GetLogs()) {
+			if !testutil.CompareLogs(tt.Entry().(*inmem.Entry).Logs(), c.Expected.GetLogs()) {
 				t.Fatal("bad3")
 			}
 			if !testutil.CompareBlocks(tt.blockTracker.BlocksBlocked(), c.Expected.ToBlocks()) {
@@ -769,7 +774,406 @@ func TestTooMuchDataRequested(t *testing.T) {
 	if err := tt.BatchSync(context.Background()); err != nil {
 		t.Fatal(err)
 	}
-	if count != len(tt.entry.(*inmem.Entry).Logs()) {
+	if count != len(tt.Entry().(*inmem.Entry).Logs()) {
 		t.Fatal("not the same count")
 	}
 }
+
+type countingProvider struct {
+	*simulated.Backend
+	getLogs int32
+}
+
+func (c *countingProvider) GetLogs(filter *ethgo.LogFilter) ([]*ethgo.Log, error) {
+	atomic.AddInt32(&c.getLogs, 1)
+	return c.Backend.GetLogs(filter)
+}
+
+// newEmitterBackend returns a simulated backend with two contracts that emit
+// a LOG0 with the calldata and a funded key to send transactions to them
+func newEmitterBackend(t *testing.T, opts ...simulated.ConfigOption) (*simulated.Backend, func(addr ethgo.Address)) {
+	key, err := wallet.GenerateKey()
+	require.NoError(t, err)
+
+	opts = append(opts, simulated.WithBalance(key.Address(), big.NewInt(1e18)))
+	b := simulated.NewBackend(opts...)
+
+	// calldatacopy(0, 0, calldatasize) log0(0, calldatasize)
+	code := []byte{0x36, 0x5f, 0x5f, 0x37, 0x36, 0x5f, 0xa0}
+	b.SetCode(addrA, code)
+	b.SetCode(addrB, code)
+
+	signer := wallet.NewEIP155Signer(simulated.DefaultChainID)
+	nonce := uint64(0)
+
+	send := func(addr ethgo.Address) {
+		txn, err := signer.SignTx(&ethgo.Transaction{
+			To:       &addr,
+			Nonce:    nonce,
+			Gas:      100000,
+			GasPrice: 2 * simulated.DefaultBaseFee.Uint64(),
+			Input:    []byte{byte(nonce)},
+			ChainID:  new(big.Int).SetUint64(simulated.DefaultChainID),
+		}, key)
+		require.NoError(t, err)
+
+		raw, err := txn.MarshalRLPTo(nil)
+		require.NoError(t, err)
+
+		_, err = b.SendRawTransaction(raw)
+		require.NoError(t, err)
+		nonce++
+	}
+	return b, send
+}
+
+var (
+	addrA = ethgo.Address{0x1}
+	addrB = ethgo.Address{0x2}
+)
+
+func entryLogs(t *testing.T, tt *Tracker, name string) []*ethgo.Log {
+	t.Helper()
+
+	entry, err := tt.FilterEntry(name)
+	require.NoError(t, err)
+	return entry.(*inmem.Entry).Logs()
+}
+
+func TestTrackerMultipleFilters(t *testing.T) {
+	b, send := newEmitterBackend(t)
+
+	for i := 0; i < 3; i++ {
+		send(addrA)
+	}
+	for i := 0; i < 2; i++ {
+		send(addrB)
+	}
+	// move the logs out of the block backlog
+	b.Mine(30)
+
+	provider := &countingProvider{Backend: b}
+
+	tt, err := NewTracker(provider,
+		WithBatchSize(5),
+		WithFilters(
+			&FilterConfig{Name: "a", Address: []ethgo.Address{addrA}},
+			&FilterConfig{Name: "b", Address: []ethgo.Address{addrB}},
+		),
+	)
+	require.NoError(t, err)
+
+	// count the logs of the events of each filter
+	var lock sync.Mutex
+	added := map[string]int{}
+	go func() {
+		for evnt := range tt.EventCh {
+			lock.Lock()
+			added[evnt.Filter] += len(evnt.Added)
+			lock.Unlock()
+		}
+	}()
+
+	require.NoError(t, tt.BatchSync(context.Background()))
+
+	logsA := entryLogs(t, tt, "a")
+	require.Len(t, logsA, 3)
+	for _, log := range logsA {
+		require.Equal(t, addrA, log.Address)
+	}
+
+	logsB := entryLogs(t, tt, "b")
+	require.Len(t, logsB, 2)
+	for _, log := range logsB {
+		require.Equal(t, addrB, log.Address)
+	}
+
+	// a single query is done for both filters on each batch
+	// (the bulk sync of 26 blocks in batches of 6 and 10 blocks of backlog)
+	require.Equal(t, int32(5+10), atomic.LoadInt32(&provider.getLogs))
+
+	// each filter has its own checkpoint
+	for _, name := range []string{"a", "b"} {
+		last, err := tt.GetFilterLastBlock(name)
+		require.NoError(t, err)
+		require.Equal(t, uint64(35), last.Number)
+	}
+
+	require.Eventually(t, func() bool {
+		lock.Lock()
+		defer lock.Unlock()
+		return reflect.DeepEqual(map[string]int{"a": 3, "b": 2}, added)
+	}, time.Second, 10*time.Millisecond)
+
+	_, err = tt.FilterEntry("c")
+	require.Error(t, err)
+
+	require.Error(t, tt.AddFilter(&FilterConfig{Name: "a"}))
+}
+
+func TestTrackerAddRemoveFilter(t *testing.T) {
+	b, send := newEmitterBackend(t)
+
+	for i := 0; i < 3; i++ {
+		send(addrA)
+	}
+	send(addrB)
+	b.Mine(30)
+
+	blockTracker := blocktracker.NewBlockTracker(b)
+
+	tt, err := NewTracker(b,
+		WithBatchSize(5),
+		WithBlockTracker(blockTracker),
+		WithFilters(&FilterConfig{Name: "a", Address: []ethgo.Address{addrA}, Async: true}),
+	)
+	require.NoError(t, err)
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	go func() {
+		if err := tt.Sync(ctx); err != nil && err != context.Canceled {
+			panic(err)
+		}
+	}()
+	require.NoError(t, tt.WaitDuration(2*time.Second))
+
+	lastA, err := tt.GetFilterLastBlock("a")
+	require.NoError(t, err)
+
+	// add a filter at runtime, it catches up with the head without resyncing 'a'
+	require.NoError(t, tt.AddFilter(&FilterConfig{Name: "b", Address: []ethgo.Address{addrB}, Async: true}))
+
+	require.Eventually(t, func() bool {
+		return len(entryLogs(t, tt, "b")) == 1
+	}, 2*time.Second, 10*time.Millisecond)
+	require.Len(t, entryLogs(t, tt, "a"), 3)
+
+	lastB, err := tt.GetFilterLastBlock("b")
+	require.NoError(t, err)
+	require.Equal(t, lastA.Hash, lastB.Hash)
+
+	// remove 'a', it does not track the new logs anymore
+	require.NoError(t, tt.RemoveFilter("a"))
+	require.Error(t, tt.RemoveFilter("a"))
+
+	send(addrA)
+	send(addrB)
+
+	go blockTracker.Start()
+	defer blockTracker.Close()
+
+	require.Eventually(t, func() bool {
+		return len(entryLogs(t, tt, "b")) == 2
+	}, 4*time.Second, 10*time.Millisecond)
+
+	_, err = tt.FilterEntry("a")
+	require.Error(t, err)
+}
+
+func TestTrackerAddFilterConcurrent(t *testing.T) {
+	b, send := newEmitterBackend(t)
+	send(addrA)
+	b.Mine(30)
+
+	blockTracker := blocktracker.NewBlockTracker(b)
+
+	tt, err := NewTracker(b,
+		WithBatchSize(5),
+		WithBlockTracker(blockTracker),
+		WithFilters(&FilterConfig{Name: "a", Address: []ethgo.Address{addrA}, Async: true}),
+	)
+	require.NoError(t, err)
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	go func() {
+		if err := tt.Sync(ctx); err != nil && err != context.Canceled {
+			panic(err)
+		}
+	}()
+
+	// the filters are added and read while the tracker syncs them (run with -race)
+	for i := 0; i < 10; i++ {
+		require.NoError(t, tt.AddFilter(&FilterConfig{Name: fmt.Sprintf("f%d", i), Address: []ethgo.Address{addrA}, Async: true}))
+		require.NotNil(t, tt.Entry())
+
+		_, err := tt.GetLastBlock()
+		require.NoError(t, err)
+	}
+
+	require.Eventually(t, func() bool {
+		require.NotNil(t, tt.Entry())
+
+		for i := 0; i < 10; i++ {
+			if len(entryLogs(t, tt, fmt.Sprintf("f%d", i))) != 1 {
+				return false
+			}
+		}
+		return true
+	}, 4*time.Second, 10*time.Millisecond)
+}
+
+func TestTrackerFinality(t *testing.T) {
+	b, send := newEmitterBackend(t, simulated.WithFinalityDepth(10))
+
+	// blocks 1 to 3 are final
+	for i := 0; i < 3; i++ {
+		send(addrA)
+	}
+	b.Mine(20)
+	send(addrA)
+
+	blockTracker := blocktracker.NewBlockTracker(b)
+
+	tt, err := NewTracker(b,
+		WithFinality(ethgo.Finalized),
+		WithBlockTracker(blockTracker),
+		WithFilters(&FilterConfig{Name: "a", Address: []ethgo.Address{addrA}}),
+	)
+	require.NoError(t, err)
+
+	// the removed logs are never emitted
+	var lock sync.Mutex
+	removed := 0
+	go func() {
+		for evnt := range tt.EventCh {
+			lock.Lock()
+			removed += len(evnt.Removed)
+			lock.Unlock()
+		}
+	}()
+
+	ctx, cancelFn := context.WithCancel(context.Background())
+	defer cancelFn()
+
+	go func() {
+		if err := tt.Sync(ctx); err != nil && err != context.Canceled {
+			panic(err)
+		}
+	}()
+	require.NoError(t, tt.WaitDuration(2*time.Second))
+
+	require.Len(t, entryLogs(t, tt, "a"), 3)
+
+	head, err := tt.GetCursor("a", store.CursorHead)
+	require.NoError(t, err)
+	require.Equal(t, uint64(24), head.Number)
+
+	final, err := tt.GetCursor("a", store.CursorFinal)
+	require.NoError(t, err)
+	require.Equal(t, uint64(14), final.Number)
+
+	// the last log is emitted once its block is final
+	b.Mine(10)
+
+	go blockTracker.Start()
+	defer blockTracker.Close()
+
+	require.Eventually(t, func() bool {
+		return len(entryLogs(t, tt, "a")) == 4
+	}, 4*time.Second, 10*time.Millisecond)
+
+	final, err = tt.GetCursor("a", store.CursorFinal)
+	require.NoError(t, err)
+	require.Equal(t, uint64(24), final.Number)
+
+	lock.Lock()
+	require.Zero(t, removed)
+	lock.Unlock()
+}
+
+func TestTrackerConfirmations(t *testing.T) {
+	l := testutil.MockList{}
+	l.Create(0, 50, func(b *testutil.MockBlock) {
+		b.Log("0x1")
+	})
+
+	m := &testutil.MockClient{}
+	m.AddScenario(l)
+
+	// the mock client does not support the finality tag and the confirmations are used
+	tt, err := NewTracker(m,
+		testConfig(),
+		WithFinality(ethgo.Finalized),
+		WithConfirmations(5),
+		WithFilter(&FilterConfig{Async: true}),
+	)
+	require.NoError(t, err)
+	require.NoError(t, tt.BatchSync(context.Background()))
+
+	final := l[:45]
+	require.True(t, testutil.CompareLogs(final.GetLogs(), tt.Entry().(*inmem.Entry).Logs()))
+
+	head, err := tt.GetCursor("", store.CursorHead)
+	require.NoError(t, err)
+	require.Equal(t, uint64(49), head.Number)
+
+	finalCursor, err := tt.GetCursor("", store.CursorFinal)
+	require.NoError(t, err)
+	require.Equal(t, uint64(44), finalCursor.Number)
+
+	// only the finalized and safe tags are supported
+	_, err = NewTracker(m, WithFinality(ethgo.Latest))
+	require.Error(t, err)
+}
+
+// finalityErrorProvider fails the queries of the finality tags with an error
+type finalityErrorProvider struct {
+	*testutil.MockClient
+	err error
+}
+
+func (f *finalityErrorProvider) GetBlockByNumber(i ethgo.BlockNumber, full bool) (*ethgo.Block, error) {
+	if i == ethgo.Finalized || i == ethgo.Safe {
+		return nil, f.err
+	}
+	return f.MockClient.GetBlockByNumber(i, full)
+}
+
+func TestTrackerFinalityError(t *testing.T) {
+	l := testutil.MockList{}
+	l.Create(0, 50, func(b *testutil.MockBlock) {
+		b.Log("0x1")
+	})
+
+	m := &testutil.MockClient{}
+	m.AddScenario(l)
+
+	// a transient error does not fall back to the confirmations
+	p := &finalityErrorProvider{MockClient: m, err: &codec.ErrorObject{Code: 429, Message: "Too Many Requests"}}
+	tt, err := NewTracker(p,
+		testConfig(),
+		WithFinality(ethgo.Finalized),
+		WithConfirmations(5),
+		WithFilter(&FilterConfig{Async: true}),
+	)
+	require.NoError(t, err)
+	require.ErrorIs(t, tt.BatchSync(context.Background()), jsonrpc.ErrRateLimited)
+	require.Empty(t, tt.Entry().(*inmem.Entry).Logs())
+
+	cursor, err := tt.GetCursor("", store.CursorFinal)
+	require.NoError(t, err)
+	require.Nil(t, cursor)
+
+	// the tag is not supported and there are no confirmations
+	tt, err = NewTracker(m,
+		testConfig(),
+		WithFinality(ethgo.Finalized),
+		WithFilter(&FilterConfig{Async: true}),
+	)
+	require.NoError(t, err)
+	require.ErrorContains(t, tt.BatchSync(context.Background()), "does not support the finality tag")
+
+	// the node does not return the block of the tag
+	p.err = nil
+	tt, err = NewTracker(p,
+		testConfig(),
+		WithFinality(ethgo.Safe),
+		WithFilter(&FilterConfig{Async: true}),
+	)
+	require.NoError(t, err)
+	require.ErrorContains(t, tt.BatchSync(context.Background()), "does not support the finality tag")
+}
@@ -0,0 +1,381 @@
+package tracker
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/jsonrpc"
+)
+
+// TxFilter tracks the transactions of the blocks instead of the logs. A transaction
+// matches if it matches all the fields that are set. The failed transactions match too.
+type TxFilter struct {
+	// From matches the transactions sent by any of the addresses
+	From []ethgo.Address `json:"from"`
+
+	// To matches the transactions sent to any of the addresses
+	To []ethgo.Address `json:"to"`
+
+	// Selectors matches the transactions that call any of the method selectors
+	Selectors [][4]byte `json:"selectors"`
+
+	// Receipts fetches the receipt of each transaction
+	Receipts bool `json:"receipts"`
+
+	// Traces derives the internal transfers of each transaction from the trace of the
+	// block. A transaction also matches if any of its internal transfers matches From and To.
+	Traces bool `json:"traces"`
+}
+
+func (f *TxFilter) matchAddrs(from ethgo.Address, to *ethgo.Address) bool {
+	if len(f.From) != 0 && !containsAddr(f.From, from) {
+		return false
+	}
+	if len(f.To) != 0 && (to == nil || !containsAddr(f.To, *to)) {
+		return false
+	}
+	return true
+}
+
+func (f *TxFilter) matchSelector(input []byte) bool {
+	if len(f.Selectors) == 0 {
+		return true
+	}
+	if len(input) < 4 {
+		return false
+	}
+	for _, selector := range f.Selectors {
+		if bytes.Equal(selector[:], input[:4]) {
+			return true
+		}
+	}
+	return false
+}
+
+// match returns true if the transaction or any of its internal transfers matches the filter
+func (f *TxFilter) match(txn *ethgo.Transaction, transfers []*InternalTransfer) bool {
+	if !f.matchSelector(txn.Input) {
+		return false
+	}
+	if f.matchAddrs(txn.From, txn.To) {
+		return true
+	}
+	for _, transfer := range transfers {
+		to := transfer.To
+		if f.matchAddrs(transfer.From, &to) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsAddr(list []ethgo.Address, addr ethgo.Address) bool {
+	for _, a := range list {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// ReceiptProvider is a provider that returns the receipts of the transactions (i.e. jsonrpc.Eth)
+type ReceiptProvider interface {
+	GetTransactionReceipt(hash ethgo.Hash) (*ethgo.Receipt, error)
+}
+
+// BlockTracer traces the calls of the transactions of a block (i.e. jsonrpc.Debug)
+type BlockTracer interface {
+	TraceBlockByHash(hash ethgo.Hash) ([]*jsonrpc.BlockTrace, error)
+}
+
+// WithTracer sets the tracer used to derive the internal transfers of the
+// transaction filters with Traces. Without a tracer, or if the node does not
+// support debug_traceBlockByHash, the internal transfers are not derived.
+func WithTracer(tracer BlockTracer) ConfigOption {
+	return func(c *Config) {
+		c.Tracer = tracer
+	}
+}
+
+// InternalTransfer is a transfer of ether done by a contract during a transaction
+type InternalTransfer struct {
+	// Type is the type of the call (i.e. CALL, CREATE or SELFDESTRUCT)
+	Type  string        `json:"type"`
+	From  ethgo.Address `json:"from"`
+	To    ethgo.Address `json:"to"`
+	Value *big.Int      `json:"value"`
+}
+
+// TrackedTx is a transaction tracked by a transaction filter
+type TrackedTx struct {
+	Transaction *ethgo.Transaction
+
+	// Receipt is the receipt of the transaction if the filter fetches the receipts
+	Receipt *ethgo.Receipt
+
+	// Transfers are the internal transfers of the transaction if the filter uses traces
+	Transfers []*InternalTransfer
+}
+
+type trackedTxJSON struct {
+	Transaction json.RawMessage     `json:"transaction"`
+	Receipt     json.RawMessage     `json:"receipt,omitempty"`
+	Transfers   []*InternalTransfer `json:"transfers,omitempty"`
+}
+
+// MarshalJSON implements the marshal interface
+func (t *TrackedTx) MarshalJSON() ([]byte, error) {
+	var err error
+
+	obj := &trackedTxJSON{Transfers: t.Transfers}
+	if obj.Transaction, err = marshalTx(t.Transaction); err != nil {
+		return nil, err
+	}
+	if t.Receipt != nil {
+		if obj.Receipt, err = t.Receipt.MarshalJSON(); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(obj)
+}
+
+// marshalTx encodes the transaction with the input, the value, the nonce and the gas
+// that are omitted if they are not set, since the decoding of a transaction requires them
+func marshalTx(txn *ethgo.Transaction) ([]byte, error) {
+	data, err := txn.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, err
+	}
+	for key, empty := range map[string]string{"input": `"0x"`, "value": `"0x0"`, "nonce": `"0x0"`, "gas": `"0x0"`} {
+		if _, ok := obj[key]; !ok {
+			obj[key] = json.RawMessage(empty)
+		}
+	}
+	return json.Marshal(obj)
+}
+
+// UnmarshalJSON implements the unmarshal interface
+func (t *TrackedTx) UnmarshalJSON(data []byte) error {
+	var obj trackedTxJSON
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+
+	t.Transaction = new(ethgo.Transaction)
+	if err := t.Transaction.UnmarshalJSON(obj.Transaction); err != nil {
+		return err
+	}
+	t.Receipt = nil
+	if len(obj.Receipt) != 0 {
+		t.Receipt = new(ethgo.Receipt)
+		if err := t.Receipt.UnmarshalJSON(obj.Receipt); err != nil {
+			return err
+		}
+	}
+	t.Transfers = obj.Transfers
+	return nil
+}
+
+// txLog encodes the transaction as a log to store it in the entry of the filter.
+// The log has the block of the transaction so the reorgs remove it like any other log.
+func txLog(txn *TrackedTx) (*ethgo.Log, error) {
+	data, err := txn.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	log := &ethgo.Log{
+		BlockNumber:      txn.Transaction.BlockNumber,
+		BlockHash:        txn.Transaction.BlockHash,
+		TransactionHash:  txn.Transaction.Hash,
+		TransactionIndex: txn.Transaction.TxnIndex,
+		LogIndex:         txn.Transaction.TxnIndex,
+		Address:          txn.Transaction.From,
+		Data:             data,
+	}
+	return log, nil
+}
+
+// DecodeTrackedTx decodes a transaction stored in the entry of a transaction filter
+func DecodeTrackedTx(log *ethgo.Log) (*TrackedTx, error) {
+	txn := &TrackedTx{}
+	if err := txn.UnmarshalJSON(log.Data); err != nil {
+		return nil, fmt.Errorf("failed to decode tracked transaction: %v", err)
+	}
+	return txn, nil
+}
+
+func decodeTrackedTxs(logs []*ethgo.Log) ([]*TrackedTx, error) {
+	if len(logs) == 0 {
+		return nil, nil
+	}
+	res := make([]*TrackedTx, len(logs))
+	for indx, log := range logs {
+		txn, err := DecodeTrackedTx(log)
+		if err != nil {
+			return nil, err
+		}
+		res[indx] = txn
+	}
+	return res, nil
+}
+
+// isTx returns true if the filter tracks transactions instead of logs
+func (f *filter) isTx() bool {
+	return f.config.Transactions != nil
+}
+
+// splitFilters splits the filters that track logs and the ones that track transactions
+func splitFilters(filters []*filter) ([]*filter, []*filter) {
+	logFilters, txFilters := []*filter{}, []*filter{}
+	for _, f := range filters {
+		if f.isTx() {
+			txFilters = append(txFilters, f)
+		} else {
+			logFilters = append(logFilters, f)
+		}
+	}
+	return logFilters, txFilters
+}
+
+// validateTxFilter checks that the provider supports the transaction filter
+func (t *Tracker) validateTxFilter(config *FilterConfig) error {
+	if config.Transactions == nil || !config.Transactions.Receipts {
+		return nil
+	}
+	if _, ok := t.provider.(ReceiptProvider); !ok {
+		return fmt.Errorf("filter '%s' requires a provider with receipts", config.Name)
+	}
+	return nil
+}
+
+// matchTxs returns the transactions of the full block that match each of the
+// transaction filters, encoded as logs to store them in the entries
+func (t *Tracker) matchTxs(filters []*filter, block *ethgo.Block) ([][]*ethgo.Log, error) {
+	res := make([][]*ethgo.Log, len(filters))
+	if len(block.Transactions) == 0 {
+		return res, nil
+	}
+
+	traces := false
+	for _, f := range filters {
+		traces = traces || f.config.Transactions.Traces
+	}
+	var transfers [][]*InternalTransfer
+	if traces {
+		var err error
+		if transfers, err = t.blockTransfers(block); err != nil {
+			return nil, err
+		}
+	}
+
+	for txIndx, txn := range block.Transactions {
+		txn.BlockHash = block.Hash
+		txn.BlockNumber = block.Number
+		txn.TxnIndex = uint64(txIndx)
+	}
+
+	receipts := map[ethgo.Hash]*ethgo.Receipt{}
+	for indx, f := range filters {
+		config := f.config.Transactions
+
+		for txIndx, txn := range block.Transactions {
+			tracked := &TrackedTx{Transaction: txn}
+			if config.Traces && transfers != nil {
+				tracked.Transfers = transfers[txIndx]
+			}
+			if !config.match(txn, tracked.Transfers) {
+				continue
+			}
+
+			if config.Receipts {
+				receipt, ok := receipts[txn.Hash]
+				if !ok {
+					var err error
+					if receipt, err = t.provider.(ReceiptProvider).GetTransactionReceipt(txn.Hash); err != nil {
+						return nil, err
+					} else if receipt == nil {
+						return nil, fmt.Errorf("receipt of transaction %s not found", txn.Hash)
+					}
+					receipts[txn.Hash] = receipt
+				}
+				tracked.Receipt = receipt
+			}
+
+			log, err := txLog(tracked)
+			if err != nil {
+				return nil, err
+			}
+			res[indx] = append(res[indx], log)
+		}
+	}
+	return res, nil
+}
+
+// blockTransfers returns the internal transfers of each transaction of the block. It
+// returns nil if there is no tracer or the node does not support the traces.
+func (t *Tracker) blockTransfers(block *ethgo.Block) ([][]*InternalTransfer, error) {
+	if t.config.Tracer == nil {
+		return nil, nil
+	}
+	traces, err := t.config.Tracer.TraceBlockByHash(block.Hash)
+	if err != nil {
+		if errors.Is(err, jsonrpc.ErrMethodNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(traces) != len(block.Transactions) {
+		return nil, fmt.Errorf("block %d has %d transactions but %d traces", block.Number, len(block.Transactions), len(traces))
+	}
+
+	res := make([][]*InternalTransfer, len(traces))
+	for indx, trace := range traces {
+		if trace.TxHash != ethgo.ZeroHash && trace.TxHash != block.Transactions[indx].Hash {
+			return nil, fmt.Errorf("trace of transaction %s not found", block.Transactions[indx].Hash)
+		}
+		if trace.Result != nil {
+			res[indx] = internalTransfers(trace.Result)
+		}
+	}
+	return res, nil
+}
+
+// internalTransfers returns the transfers of ether of the internal calls of the trace.
+// The calls that failed are skipped with their subcalls since they were reverted.
+func internalTransfers(trace *jsonrpc.CallTrace) []*InternalTransfer {
+	if trace.Error != "" {
+		return nil
+	}
+
+	var res []*InternalTransfer
+	var walk func(calls []*jsonrpc.CallTrace)
+	walk = func(calls []*jsonrpc.CallTrace) {
+		for _, call := range calls {
+			if call.Error != "" {
+				continue
+			}
+			if call.Value != nil && call.Type != "DELEGATECALL" && call.Type != "STATICCALL" {
+				value := new(big.Int).Set((*big.Int)(call.Value))
+				if value.Sign() > 0 {
+					res = append(res, &InternalTransfer{
+						Type:  call.Type,
+						From:  call.From,
+						To:    call.To,
+						Value: value,
+					})
+				}
+			}
+			walk(call.Calls)
+		}
+	}
+	walk(trace.Calls)
+	return res
+}
@@ -0,0 +1,296 @@
+package tracker
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/Ethernal-Tech/ethgo"
+	"github.com/Ethernal-Tech/ethgo/jsonrpc"
+	"github.com/Ethernal-Tech/ethgo/simulated"
+	"github.com/Ethernal-Tech/ethgo/tracker/store/inmem"
+	"github.com/Ethernal-Tech/ethgo/wallet"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	addrX = ethgo.Address{0x10}
+	addrY = ethgo.Address{0x11}
+
+	// addrR is a contract that always reverts
+	addrR = ethgo.Address{0x12}
+
+	selector1 = [4]byte{0xaa, 0xbb, 0xcc, 0xdd}
+	selector2 = [4]byte{0x11, 0x22, 0x33, 0x44}
+)
+
+func newTxBackend(t *testing.T) (*simulated.Backend, func(to ethgo.Address, value int64, input []byte) ethgo.Hash) {
+	key, err := wallet.GenerateKey()
+	require.NoError(t, err)
+
+	b := simulated.NewBackend(simulated.WithBalance(key.Address(), big.NewInt(1e18)))
+
+	// calldatacopy(0, 0, calldatasize) log0(0, calldatasize)
+	b.SetCode(addrA, []byte{0x36, 0x5f, 0x5f, 0x37, 0x36, 0x5f, 0xa0})
+	// revert(0, 0)
+	b.SetCode(addrR, []byte{0x5f, 0x5f, 0xfd})
+
+	signer := wallet.NewEIP155Signer(simulated.DefaultChainID)
+	nonce := uint64(0)
+
+	send := func(to ethgo.Address, value int64, input []byte) ethgo.Hash {
+		txn, err := signer.SignTx(&ethgo.Transaction{
+			To:       &to,
+			Nonce:    nonce,
+			Gas:      100000,
+			GasPrice: 2 * simulated.DefaultBaseFee.Uint64(),
+			Value:    big.NewInt(value),
+			Input:    input,
+			ChainID:  new(big.Int).SetUint64(simulated.DefaultChainID),
+		}, key)
+		require.NoError(t, err)
+
+		raw, err := txn.MarshalRLPTo(nil)
+		require.NoError(t, err)
+
+		hash, err := b.SendRawTransaction(raw)
+		require.NoError(t, err)
+		nonce++
+		return hash
+	}
+	return b, send
+}
+
+// eventRecorder is a handler that records the events of each filter
+type eventRecorder struct {
+	lock   sync.Mutex
+	events map[string][]*Event
+}
+
+func (e *eventRecorder) handle(ctx context.Context, evnt *Event) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.events == nil {
+		e.events = map[string][]*Event{}
+	}
+	e.events[evnt.Filter] = append(e.events[evnt.Filter], evnt)
+	return nil
+}
+
+func (e *eventRecorder) added(name string) []*TrackedTx {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	res := []*TrackedTx{}
+	for _, evnt := range e.events[name] {
+		res = append(res, evnt.AddedTxs...)
+	}
+	return res
+}
+
+func (e *eventRecorder) removed(name string) []*TrackedTx {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	res := []*TrackedTx{}
+	for _, evnt := range e.events[name] {
+		res = append(res, evnt.RemovedTxs...)
+	}
+	return res
+}
+
+func txHashes(txs []*TrackedTx) []ethgo.Hash {
+	res := []ethgo.Hash{}
+	for _, txn := range txs {
+		res = append(res, txn.Transaction.Hash)
+	}
+	return res
+}
+
+func TestTrackerTransactions(t *testing.T) {
+	b, send := newTxBackend(t)
+
+	transfer1 := send(addrX, 1000, nil)
+	call1 := send(addrA, 0, append(selector1[:], 0x1))
+	send(addrA, 0, selector2[:])
+	failed := send(addrR, 0, nil)
+
+	// move the transactions out of the block backlog
+	b.Mine(30)
+
+	// the last transfer is synced from the block backlog
+	transfer2 := send(addrX, 2000, nil)
+
+	h := &eventRecorder{}
+	tt, err := NewTracker(b,
+		WithBatchSize(5),
+		WithHandler(h.handle),
+		WithFilters(
+			&FilterConfig{Name: "transfers", Transactions: &TxFilter{To: []ethgo.Address{addrX}}},
+			&FilterConfig{Name: "calls", Transactions: &TxFilter{To: []ethgo.Address{addrA}, Selectors: [][4]byte{selector1}, Receipts: true}},
+			&FilterConfig{Name: "failed", Transactions: &TxFilter{To: []ethgo.Address{addrR}, Receipts: true}},
+			&FilterConfig{Name: "logs", Address: []ethgo.Address{addrA}},
+		),
+	)
+	require.NoError(t, err)
+	require.NoError(t, tt.BatchSync(context.Background()))
+
+	// native transfers
+	transfers := h.added("transfers")
+	require.Equal(t, []ethgo.Hash{transfer1, transfer2}, txHashes(transfers))
+	require.Equal(t, big.NewInt(1000), transfers[0].Transaction.Value)
+	require.Nil(t, transfers[0].Receipt)
+
+	// contract calls with the selector
+	calls := h.added("calls")
+	require.Equal(t, []ethgo.Hash{call1}, txHashes(calls))
+	require.Equal(t, uint64(1), calls[0].Receipt.Status)
+	require.Len(t, calls[0].Receipt.Logs, 1)
+
+	// failed transactions
+	fails := h.added("failed")
+	require.Equal(t, []ethgo.Hash{failed}, txHashes(fails))
+	require.Equal(t, uint64(0), fails[0].Receipt.Status)
+
+	// the log filters are tracked along with the transaction filters
+	require.Len(t, entryLogs(t, tt, "logs"), 2)
+
+	// the transactions are stored in the entry of the filter
+	stored := []*TrackedTx{}
+	for _, log := range entryLogs(t, tt, "calls") {
+		txn, err := DecodeTrackedTx(log)
+		require.NoError(t, err)
+		stored = append(stored, txn)
+	}
+	require.Equal(t, calls, stored)
+}
+
+func TestTrackerTransactionsReceipts(t *testing.T) {
+	// the provider does not return receipts
+	_, err := NewTracker(struct{ Provider }{simulated.NewBackend()},
+		WithFilter(&FilterConfig{Transactions: &TxFilter{Receipts: true}}),
+	)
+	require.Error(t, err)
+}
+
+// mockTracer traces the calls to addrA as an internal transfer to addrY and a failed
+// internal transfer to addrX
+type mockTracer struct {
+	b *simulated.Backend
+}
+
+func (m *mockTracer) TraceBlockByHash(hash ethgo.Hash) ([]*jsonrpc.BlockTrace, error) {
+	block, err := m.b.GetBlockByHash(hash, true)
+	if err != nil {
+		return nil, err
+	}
+
+	res := []*jsonrpc.BlockTrace{}
+	for _, txn := range block.Transactions {
+		trace := &jsonrpc.CallTrace{Type: "CALL", From: txn.From, To: *txn.To}
+		if *txn.To == addrA {
+			trace.Calls = []*jsonrpc.CallTrace{
+				{Type: "CALL", From: addrA, To: addrY, Value: (*ethgo.ArgBig)(big.NewInt(5))},
+				{Type: "CALL", From: addrA, To: addrX, Value: (*ethgo.ArgBig)(big.NewInt(6)), Error: "execution reverted"},
+				{Type: "STATICCALL", From: addrA, To: addrX},
+			}
+		}
+		res = append(res, &jsonrpc.BlockTrace{TxHash: txn.Hash, Result: trace})
+	}
+	return res, nil
+}
+
+func TestTrackerTransactionsTraces(t *testing.T) {
+	b, send := newTxBackend(t)
+
+	send(addrX, 1000, nil)
+	call := send(addrA, 0, nil)
+	b.Mine(30)
+
+	h := &eventRecorder{}
+	tt, err := NewTracker(b,
+		WithBatchSize(5),
+		WithHandler(h.handle),
+		WithTracer(&mockTracer{b: b}),
+		WithFilters(
+			&FilterConfig{Name: "internal", Transactions: &TxFilter{To: []ethgo.Address{addrY}, Traces: true}},
+			&FilterConfig{Name: "direct", Transactions: &TxFilter{To: []ethgo.Address{addrY}}},
+		),
+	)
+	require.NoError(t, err)
+	require.NoError(t, tt.BatchSync(context.Background()))
+
+	// the call matches because of the internal transfer and the reverted transfer is skipped
+	internal := h.added("internal")
+	require.Equal(t, []ethgo.Hash{call}, txHashes(internal))
+	require.Equal(t, []*InternalTransfer{
+		{Type: "CALL", From: addrA, To: addrY, Value: big.NewInt(5)},
+	}, internal[0].Transfers)
+
+	// without traces only the direct transactions match
+	require.Empty(t, h.added("direct"))
+}
+
+func TestTrackerTransactionsReorg(t *testing.T) {
+	b, send := newTxBackend(t)
+	b.Mine(5)
+
+	snapshot := b.Snapshot()
+	transfer := send(addrX, 1000, nil)
+
+	store := inmem.NewInmemStore()
+	filter := &FilterConfig{Name: "transfers", Transactions: &TxFilter{To: []ethgo.Address{addrX}}}
+
+	h := &eventRecorder{}
+	tt, err := NewTracker(b, WithStore(store), WithHandler(h.handle), WithFilter(filter))
+	require.NoError(t, err)
+	require.NoError(t, tt.BatchSync(context.Background()))
+	require.Equal(t, []ethgo.Hash{transfer}, txHashes(h.added("transfers")))
+
+	// the block with the transfer is replaced
+	require.NoError(t, b.Revert(snapshot))
+	b.Mine(2)
+
+	h = &eventRecorder{}
+	tt, err = NewTracker(b, WithStore(store), WithHandler(h.handle), WithFilter(filter))
+	require.NoError(t, err)
+	require.NoError(t, tt.BatchSync(context.Background()))
+
+	require.Equal(t, []ethgo.Hash{transfer}, txHashes(h.removed("transfers")))
+	require.Empty(t, h.added("transfers"))
+	require.Empty(t, tt.Entry().(*inmem.Entry).Logs())
+}
+
+func TestTrackedTxEncoding(t *testing.T) {
+	to := ethgo.Address{0x2}
+	txn := &ethgo.Transaction{
+		Hash:        ethgo.Hash{0x1},
+		From:        ethgo.Address{0x1},
+		To:          &to,
+		GasPrice:    1,
+		V:           []byte{0x1},
+		R:           []byte{0x2},
+		S:           []byte{0x3},
+		BlockHash:   ethgo.Hash{0x2},
+		BlockNumber: 1,
+		TxnIndex:    2,
+	}
+
+	// the nonce, the gas, the input and the value are not set
+	log, err := txLog(&TrackedTx{Transaction: txn})
+	require.NoError(t, err)
+
+	tracked, err := DecodeTrackedTx(log)
+	require.NoError(t, err)
+
+	decoded := tracked.Transaction
+	require.Zero(t, decoded.Nonce)
+	require.Zero(t, decoded.Gas)
+	require.Empty(t, decoded.Input)
+	require.Zero(t, decoded.Value.Sign())
+
+	decoded.Input, decoded.Value = nil, nil
+	require.Equal(t, txn, decoded)
+}
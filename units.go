@@ -1,6 +1,11 @@
 package ethgo
 
-import "math/big"
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
 
 func convert(val uint64, decimals int64) *big.Int {
 	v := big.NewInt(int64(val))
@@ -17,3 +22,253 @@ func Ether(i uint64) *big.Int {
 func Gwei(i uint64) *big.Int {
 	return convert(i, 9)
 }
+
+// Unit is a denomination of a value expressed as its number of decimals.
+// Any token decimals can be used as a unit (i.e. Unit(6) for USDC).
+type Unit int
+
+const (
+	UnitWei    Unit = 0
+	UnitKwei   Unit = 3
+	UnitMwei   Unit = 6
+	UnitGwei   Unit = 9
+	UnitSzabo  Unit = 12
+	UnitFinney Unit = 15
+	UnitEther  Unit = 18
+)
+
+var unitNames = map[string]Unit{
+	"wei":    UnitWei,
+	"kwei":   UnitKwei,
+	"mwei":   UnitMwei,
+	"gwei":   UnitGwei,
+	"szabo":  UnitSzabo,
+	"finney": UnitFinney,
+	"ether":  UnitEther,
+	"eth":    UnitEther,
+}
+
+// ParseUnit returns the unit with the given name (i.e. gwei or ether)
+func ParseUnit(name string) (Unit, error) {
+	unit, ok := unitNames[strings.ToLower(name)]
+	if !ok {
+		return 0, fmt.Errorf("unit '%s' not found", name)
+	}
+	return unit, nil
+}
+
+// ParseUnits converts a decimal amount (i.e. "0.015") in the given unit to
+// its integer value. It fails if the amount cannot be represented exactly.
+func ParseUnits(amount string, unit Unit) (*big.Int, error) {
+	d, err := ParseDecimal(amount, int(unit))
+	if err != nil {
+		return nil, err
+	}
+	return d.Value, nil
+}
+
+// ParseWei converts an amount with an optional unit (i.e. "0.015 ether" or "3.2 gwei")
+// to wei. The amount is in wei if there is no unit.
+func ParseWei(str string) (*big.Int, error) {
+	fields := strings.Fields(str)
+
+	unit := UnitWei
+	switch len(fields) {
+	case 1:
+	case 2:
+		var err error
+		if unit, err = ParseUnit(fields[1]); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("invalid amount '%s'", str)
+	}
+	return ParseUnits(fields[0], unit)
+}
+
+// FormatUnits formats an integer value in the given unit with precision fractional
+// digits. The value is rounded half away from zero if it has more digits.
+// A negative precision returns the exact value without trailing zeros.
+func FormatUnits(value *big.Int, unit Unit, precision int) string {
+	return NewDecimal(value, int(unit)).Text(precision)
+}
+
+// Decimal is an exact decimal number represented as an integer value scaled by
+// 10^Decimals (i.e. 1.5 with 2 decimals has a value of 150)
+type Decimal struct {
+	Value    *big.Int
+	Decimals int
+}
+
+// NewDecimal creates a new decimal number
+func NewDecimal(value *big.Int, decimals int) *Decimal {
+	return &Decimal{Value: value, Decimals: decimals}
+}
+
+// maxDecimalExponent limits the exponent of the parsed decimals to
+// avoid allocating huge numbers with inputs like '1e1000000000'
+const maxDecimalExponent = 1000
+
+// ParseDecimal parses a decimal number (i.e. "-12.5" or "1.5e3") with the
+// given number of decimals. It fails if the number has more fractional digits
+// than decimals since it cannot be represented exactly.
+func ParseDecimal(str string, decimals int) (*Decimal, error) {
+	if decimals < 0 {
+		return nil, fmt.Errorf("negative decimals %d", decimals)
+	}
+
+	num := strings.TrimSpace(str)
+
+	neg := false
+	if strings.HasPrefix(num, "-") || strings.HasPrefix(num, "+") {
+		neg = num[0] == '-'
+		num = num[1:]
+	}
+
+	exp := 0
+	if indx := strings.IndexAny(num, "eE"); indx != -1 {
+		var err error
+		if exp, err = strconv.Atoi(num[indx+1:]); err != nil {
+			return nil, fmt.Errorf("invalid exponent in '%s'", str)
+		}
+		if exp > maxDecimalExponent || exp < -maxDecimalExponent {
+			return nil, fmt.Errorf("exponent out of range in '%s'", str)
+		}
+		num = num[:indx]
+	}
+
+	integer, fraction := num, ""
+	if indx := strings.Index(num, "."); indx != -1 {
+		integer, fraction = num[:indx], num[indx+1:]
+	}
+	if integer == "" && fraction == "" {
+		return nil, fmt.Errorf("invalid decimal '%s'", str)
+	}
+	for _, c := range integer + fraction {
+		if c < '0' || c > '9' {
+			return nil, fmt.Errorf("invalid decimal '%s'", str)
+		}
+	}
+
+	value, ok := new(big.Int).SetString("0"+integer+fraction, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid decimal '%s'", str)
+	}
+
+	// the digits are scaled by 10^scale, rescale them to the decimals
+	scale := len(fraction) - exp
+	if scale <= decimals {
+		value.Mul(value, pow10(decimals-scale))
+	} else {
+		rem := new(big.Int)
+		value.QuoRem(value, pow10(scale-decimals), rem)
+		if rem.Sign() != 0 {
+			return nil, fmt.Errorf("decimal '%s' has more than %d decimals", str, decimals)
+		}
+	}
+	if neg {
+		value.Neg(value)
+	}
+	return NewDecimal(value, decimals), nil
+}
+
+// String returns the exact representation of the number without trailing zeros
+func (d *Decimal) String() string {
+	return d.Text(-1)
+}
+
+// Text returns the representation of the number with precision fractional digits. The
+// number is rounded half away from zero if it has more digits. A negative precision
+// returns the exact representation without trailing zeros.
+func (d *Decimal) Text(precision int) string {
+	value := d.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	if precision < 0 {
+		return formatScaled(value, d.Decimals, true)
+	}
+
+	if precision < d.Decimals {
+		div := pow10(d.Decimals - precision)
+
+		rem := new(big.Int)
+		q, _ := new(big.Int).QuoRem(new(big.Int).Abs(value), div, rem)
+		if rem.Lsh(rem, 1).Cmp(div) >= 0 {
+			q.Add(q, big.NewInt(1))
+		}
+		if value.Sign() < 0 {
+			q.Neg(q)
+		}
+		value = q
+	} else {
+		value = new(big.Int).Mul(value, pow10(precision-d.Decimals))
+	}
+	return formatScaled(value, precision, false)
+}
+
+// Rescale returns the same number with a different number of decimals. It
+// fails if the number cannot be represented exactly with the new decimals.
+func (d *Decimal) Rescale(decimals int) (*Decimal, error) {
+	if decimals < 0 {
+		return nil, fmt.Errorf("negative decimals %d", decimals)
+	}
+	value := new(big.Int)
+	if d.Value != nil {
+		value.Set(d.Value)
+	}
+	if decimals >= d.Decimals {
+		value.Mul(value, pow10(decimals-d.Decimals))
+	} else {
+		rem := new(big.Int)
+		value.QuoRem(value, pow10(d.Decimals-decimals), rem)
+		if rem.Sign() != 0 {
+			return nil, fmt.Errorf("decimal %s has more than %d decimals", d, decimals)
+		}
+	}
+	return NewDecimal(value, decimals), nil
+}
+
+// Cmp compares two decimal numbers and returns -1, 0 or +1
+func (d *Decimal) Cmp(o *Decimal) int {
+	a, b := d.Value, o.Value
+	if d.Decimals < o.Decimals {
+		a = new(big.Int).Mul(a, pow10(o.Decimals-d.Decimals))
+	} else if d.Decimals > o.Decimals {
+		b = new(big.Int).Mul(b, pow10(d.Decimals-o.Decimals))
+	}
+	return a.Cmp(b)
+}
+
+// MarshalText implements the encoding.TextMarshaler interface
+func (d *Decimal) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func formatScaled(value *big.Int, decimals int, trim bool) string {
+	str := new(big.Int).Abs(value).String()
+
+	sign := ""
+	if value.Sign() < 0 {
+		sign = "-"
+	}
+	if decimals == 0 {
+		return sign + str
+	}
+
+	if len(str) <= decimals {
+		str = strings.Repeat("0", decimals-len(str)+1) + str
+	}
+	integer, fraction := str[:len(str)-decimals], str[len(str)-decimals:]
+	if trim {
+		fraction = strings.TrimRight(fraction, "0")
+	}
+	if fraction == "" {
+		return sign + integer
+	}
+	return sign + integer + "." + fraction
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
@@ -0,0 +1,88 @@
+package ethgo
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnits_ParseWei(t *testing.T) {
+	cases := []struct {
+		str string
+		wei string
+	}{
+		{"0.015 ether", "15000000000000000"},
+		{"3.2 gwei", "3200000000"},
+		{"3.2 GWEI", "3200000000"},
+		{"1 wei", "1"},
+		{"100", "100"},
+		{"1e18", "1000000000000000000"},
+		{"1.5e-9 ether", "1500000000"},
+		{"-2 finney", "-2000000000000000"},
+		{"1 eth", "1000000000000000000"},
+	}
+
+	for _, c := range cases {
+		wei, err := ParseWei(c.str)
+		require.NoError(t, err, c.str)
+		require.Equal(t, c.wei, wei.String(), c.str)
+	}
+
+	for _, str := range []string{"", "0.5", "1 ethers", "1 gwei wei", "1.0000000001 gwei", "1e", "1e100000", "0x10"} {
+		_, err := ParseWei(str)
+		require.Error(t, err, str)
+	}
+}
+
+func TestUnits_ParseUnits(t *testing.T) {
+	// token with 6 decimals
+	value, err := ParseUnits("12.345678", Unit(6))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(12345678), value)
+
+	_, err = ParseUnits("12.3456789", Unit(6))
+	require.Error(t, err)
+
+	// trailing zeros do not lose precision
+	value, err = ParseUnits("12.34567800", Unit(6))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(12345678), value)
+}
+
+func TestUnits_FormatUnits(t *testing.T) {
+	wei := Ether(1)
+	wei.Add(wei, Gwei(1500000000))
+
+	require.Equal(t, "2.5", FormatUnits(wei, UnitEther, -1))
+	require.Equal(t, "2.500", FormatUnits(wei, UnitEther, 3))
+	require.Equal(t, "3", FormatUnits(wei, UnitEther, 0))
+	require.Equal(t, "2500000000", FormatUnits(wei, UnitGwei, -1))
+	require.Equal(t, "0.000000001", FormatUnits(big.NewInt(1), UnitGwei, -1))
+	require.Equal(t, "0.00", FormatUnits(big.NewInt(1), UnitGwei, 2))
+	require.Equal(t, "-0.13", FormatUnits(big.NewInt(-125), Unit(3), 2))
+	require.Equal(t, "0.12", FormatUnits(big.NewInt(124), Unit(3), 2))
+	require.Equal(t, "12", FormatUnits(big.NewInt(12), UnitWei, -1))
+}
+
+func TestDecimal(t *testing.T) {
+	a, err := ParseDecimal("1.5", 2)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(150), a.Value)
+
+	b, err := a.Rescale(4)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(15000), b.Value)
+	require.Equal(t, 0, a.Cmp(b))
+
+	_, err = a.Rescale(0)
+	require.Error(t, err)
+
+	c, err := ParseDecimal("-1.51", 2)
+	require.NoError(t, err)
+	require.Equal(t, -1, c.Cmp(a))
+
+	text, err := c.MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, "-1.51", string(text))
+}